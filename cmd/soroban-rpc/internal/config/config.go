@@ -20,45 +20,84 @@ type Config struct {
 	CaptiveCoreConfigPath  string
 	CaptiveCoreHTTPPort    uint
 
-	Endpoint                                    string
-	AdminEndpoint                               string
-	CheckpointFrequency                         uint32
-	CoreRequestTimeout                          time.Duration
-	DefaultEventsLimit                          uint
-	EventLedgerRetentionWindow                  uint32
-	FriendbotURL                                string
-	HistoryArchiveURLs                          []string
-	HistoryArchiveUserAgent                     string
-	IngestionTimeout                            time.Duration
-	LogFormat                                   LogFormat
-	LogLevel                                    logrus.Level
-	MaxEventsLimit                              uint
-	MaxHealthyLedgerLatency                     time.Duration
-	NetworkPassphrase                           string
-	PreflightWorkerCount                        uint
-	PreflightWorkerQueueSize                    uint
-	PreflightEnableDebug                        bool
-	SQLiteDBPath                                string
-	TransactionLedgerRetentionWindow            uint32
-	RequestBacklogGlobalQueueLimit              uint
-	RequestBacklogGetHealthQueueLimit           uint
-	RequestBacklogGetEventsQueueLimit           uint
-	RequestBacklogGetNetworkQueueLimit          uint
-	RequestBacklogGetLatestLedgerQueueLimit     uint
-	RequestBacklogGetLedgerEntriesQueueLimit    uint
-	RequestBacklogGetTransactionQueueLimit      uint
-	RequestBacklogSendTransactionQueueLimit     uint
-	RequestBacklogSimulateTransactionQueueLimit uint
-	RequestExecutionWarningThreshold            time.Duration
-	MaxRequestExecutionDuration                 time.Duration
-	MaxGetHealthExecutionDuration               time.Duration
-	MaxGetEventsExecutionDuration               time.Duration
-	MaxGetNetworkExecutionDuration              time.Duration
-	MaxGetLatestLedgerExecutionDuration         time.Duration
-	MaxGetLedgerEntriesExecutionDuration        time.Duration
-	MaxGetTransactionExecutionDuration          time.Duration
-	MaxSendTransactionExecutionDuration         time.Duration
-	MaxSimulateTransactionExecutionDuration     time.Duration
+	Endpoint                                     string
+	AdminEndpoint                                string
+	CheckpointFrequency                          uint32
+	CoreRequestTimeout                           time.Duration
+	DefaultEventsLimit                           uint
+	DefaultAccountActivityLimit                  uint
+	DefaultContractDataLimit                     uint
+	DefaultTransactionsLimit                     uint
+	DefaultLedgersLimit                          uint
+	EventLedgerRetentionWindow                   uint32
+	FeeStatsLedgerWindow                         uint32
+	FriendbotURL                                 string
+	HistoryArchiveURLs                           []string
+	HistoryArchiveUserAgent                      string
+	IngestionTimeout                             time.Duration
+	LogFormat                                    LogFormat
+	LogLevel                                     logrus.Level
+	MaxEventsLimit                               uint
+	MaxAccountActivityLimit                      uint
+	MaxContractDataLimit                         uint
+	MaxTransactionsLimit                         uint
+	MaxLedgersLimit                              uint
+	MaxSimulateTransactionsBundleSize            uint
+	MaxSubscriptionsPerConnection                uint
+	SubscriptionBufferSize                       uint
+	MaxHealthyLedgerLatency                      time.Duration
+	MaxHistoricalLedgerDepth                     uint32
+	MaxWaitForTransactionTimeout                 time.Duration
+	NetworkPassphrase                            string
+	PreflightWorkerCount                         uint
+	PreflightWorkerQueueSize                     uint
+	PreflightEnableDebug                         bool
+	SimulationCacheSize                          uint
+	SubmissionTrackingEnabled                    bool
+	SubmissionRetryBackoff                       time.Duration
+	SubmissionMaxRetryDuration                   time.Duration
+	SubmissionRetentionWindow                    time.Duration
+	SQLiteDBPath                                 string
+	TransactionLedgerRetentionWindow             uint32
+	RequestBacklogGlobalQueueLimit               uint
+	RequestBacklogGetHealthQueueLimit            uint
+	RequestBacklogGetEventsQueueLimit            uint
+	RequestBacklogGetNetworkQueueLimit           uint
+	RequestBacklogGetLatestLedgerQueueLimit      uint
+	RequestBacklogGetLedgerEntriesQueueLimit     uint
+	RequestBacklogGetTransactionQueueLimit       uint
+	RequestBacklogGetTransactionsQueueLimit      uint
+	RequestBacklogWaitForTransactionQueueLimit   uint
+	RequestBacklogGetLedgersQueueLimit           uint
+	RequestBacklogGetFeeStatsQueueLimit          uint
+	RequestBacklogSendTransactionQueueLimit      uint
+	RequestBacklogSimulateTransactionQueueLimit  uint
+	RequestBacklogSimulateTransactionsQueueLimit uint
+	RequestBacklogPrepareTransactionQueueLimit   uint
+	RequestBacklogGetSubmissionStatusQueueLimit  uint
+	RequestBacklogGetAccountActivityQueueLimit   uint
+	RequestBacklogGetIndexedLedgersQueueLimit    uint
+	RequestBacklogGetContractDataQueueLimit      uint
+	RequestExecutionWarningThreshold             time.Duration
+	MaxRequestExecutionDuration                  time.Duration
+	MaxGetHealthExecutionDuration                time.Duration
+	MaxGetEventsExecutionDuration                time.Duration
+	MaxGetNetworkExecutionDuration               time.Duration
+	MaxGetLatestLedgerExecutionDuration          time.Duration
+	MaxGetLedgerEntriesExecutionDuration         time.Duration
+	MaxGetTransactionExecutionDuration           time.Duration
+	MaxGetTransactionsExecutionDuration          time.Duration
+	MaxWaitForTransactionExecutionDuration       time.Duration
+	MaxGetLedgersExecutionDuration               time.Duration
+	MaxGetFeeStatsExecutionDuration              time.Duration
+	MaxSendTransactionExecutionDuration          time.Duration
+	MaxSimulateTransactionExecutionDuration      time.Duration
+	MaxSimulateTransactionsExecutionDuration     time.Duration
+	MaxPrepareTransactionExecutionDuration       time.Duration
+	MaxGetSubmissionStatusExecutionDuration      time.Duration
+	MaxGetAccountActivityExecutionDuration       time.Duration
+	MaxGetIndexedLedgersExecutionDuration        time.Duration
+	MaxGetContractDataExecutionDuration          time.Duration
 
 	// We memoize these, so they bind to pflags correctly
 	optionsCache *ConfigOptions
@@ -225,6 +225,26 @@ func (cfg *Config) options() ConfigOptions {
 			DefaultValue: uint32(1440),
 			Validate:     positive,
 		},
+		{
+			Name: "fee-stats-ledger-window",
+			Usage: "configures the amount of recent ledgers, expressed in number of ledgers, over which getFeeStats" +
+				" computes the inclusion fee distributions. It cannot exceed the transaction retention window",
+			ConfigKey:    &cfg.FeeStatsLedgerWindow,
+			DefaultValue: uint32(50),
+			Validate: func(co *ConfigOption) error {
+				if err := positive(co); err != nil {
+					return err
+				}
+				if cfg.FeeStatsLedgerWindow > cfg.TransactionLedgerRetentionWindow {
+					return fmt.Errorf(
+						"fee-stats-ledger-window (%v) cannot exceed transaction-retention-window (%v)",
+						cfg.FeeStatsLedgerWindow,
+						cfg.TransactionLedgerRetentionWindow,
+					)
+				}
+				return nil
+			},
+		},
 		{
 			Name:         "max-events-limit",
 			Usage:        "Maximum amount of events allowed in a single getEvents response",
@@ -247,6 +267,143 @@ func (cfg *Config) options() ConfigOptions {
 				return nil
 			},
 		},
+		{
+			Name:         "max-transactions-limit",
+			Usage:        "Maximum amount of transactions allowed in a single getTransactions response",
+			ConfigKey:    &cfg.MaxTransactionsLimit,
+			DefaultValue: uint(200),
+		},
+		{
+			Name:         "default-transactions-limit",
+			Usage:        "Default cap on the amount of transactions included in a single getTransactions response",
+			ConfigKey:    &cfg.DefaultTransactionsLimit,
+			DefaultValue: uint(50),
+			Validate: func(co *ConfigOption) error {
+				if cfg.DefaultTransactionsLimit > cfg.MaxTransactionsLimit {
+					return fmt.Errorf(
+						"default-transactions-limit (%v) cannot exceed max-transactions-limit (%v)",
+						cfg.DefaultTransactionsLimit,
+						cfg.MaxTransactionsLimit,
+					)
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "max-ledgers-limit",
+			Usage:        "Maximum amount of ledgers allowed in a single getLedgers response",
+			ConfigKey:    &cfg.MaxLedgersLimit,
+			DefaultValue: uint(200),
+		},
+		{
+			Name:         "default-ledgers-limit",
+			Usage:        "Default cap on the amount of ledgers included in a single getLedgers response",
+			ConfigKey:    &cfg.DefaultLedgersLimit,
+			DefaultValue: uint(50),
+			Validate: func(co *ConfigOption) error {
+				if cfg.DefaultLedgersLimit > cfg.MaxLedgersLimit {
+					return fmt.Errorf(
+						"default-ledgers-limit (%v) cannot exceed max-ledgers-limit (%v)",
+						cfg.DefaultLedgersLimit,
+						cfg.MaxLedgersLimit,
+					)
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "max-simulate-transactions-bundle-size",
+			Usage:        "Maximum amount of transactions allowed in a single simulateTransactions request",
+			ConfigKey:    &cfg.MaxSimulateTransactionsBundleSize,
+			DefaultValue: uint(5),
+			Validate:     positive,
+		},
+		{
+			Name: "max-historical-ledger-depth",
+			Usage: "Maximum amount of ledgers behind the latest ledger whose state getLedgerEntries and simulateTransaction" +
+				" can reconstruct, the default value is 720 which corresponds to about 1 hour of history",
+			ConfigKey:    &cfg.MaxHistoricalLedgerDepth,
+			DefaultValue: uint32(720),
+			Validate:     positive,
+		},
+		{
+			Name:         "max-wait-for-transaction-timeout",
+			Usage:        "Maximum amount of time waitForTransaction and subscribeTransaction wait for a transaction to be ingested",
+			ConfigKey:    &cfg.MaxWaitForTransactionTimeout,
+			DefaultValue: 20 * time.Second,
+			Validate: func(co *ConfigOption) error {
+				if cfg.MaxWaitForTransactionTimeout <= 0 {
+					return fmt.Errorf("max-wait-for-transaction-timeout must be positive")
+				}
+				if cfg.MaxWaitForTransactionTimeout >= cfg.MaxWaitForTransactionExecutionDuration {
+					return fmt.Errorf(
+						"max-wait-for-transaction-timeout (%v) must be lower than max-wait-for-transaction-execution-duration (%v)",
+						cfg.MaxWaitForTransactionTimeout,
+						cfg.MaxWaitForTransactionExecutionDuration,
+					)
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "max-subscriptions-per-connection",
+			Usage:        "Maximum amount of subscriptions of a single WebSocket connection",
+			ConfigKey:    &cfg.MaxSubscriptionsPerConnection,
+			DefaultValue: uint(10),
+			Validate:     positive,
+		},
+		{
+			Name: "subscription-buffer-size",
+			Usage: "Maximum amount of notifications buffered for a single WebSocket connection," +
+				" connections which don't keep up are closed",
+			ConfigKey:    &cfg.SubscriptionBufferSize,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			Name:         "max-account-activity-limit",
+			Usage:        "Maximum amount of activities allowed in a single getAccountActivity response",
+			ConfigKey:    &cfg.MaxAccountActivityLimit,
+			DefaultValue: uint(1000),
+		},
+		{
+			Name:         "default-account-activity-limit",
+			Usage:        "Default cap on the amount of activities included in a single getAccountActivity response",
+			ConfigKey:    &cfg.DefaultAccountActivityLimit,
+			DefaultValue: uint(100),
+			Validate: func(co *ConfigOption) error {
+				if cfg.DefaultAccountActivityLimit > cfg.MaxAccountActivityLimit {
+					return fmt.Errorf(
+						"default-account-activity-limit (%v) cannot exceed max-account-activity-limit (%v)",
+						cfg.DefaultAccountActivityLimit,
+						cfg.MaxAccountActivityLimit,
+					)
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "max-contract-data-limit",
+			Usage:        "Maximum amount of entries allowed in a single getContractData response",
+			ConfigKey:    &cfg.MaxContractDataLimit,
+			DefaultValue: uint(200),
+		},
+		{
+			Name:         "default-contract-data-limit",
+			Usage:        "Default cap on the amount of entries included in a single getContractData response",
+			ConfigKey:    &cfg.DefaultContractDataLimit,
+			DefaultValue: uint(50),
+			Validate: func(co *ConfigOption) error {
+				if cfg.DefaultContractDataLimit > cfg.MaxContractDataLimit {
+					return fmt.Errorf(
+						"default-contract-data-limit (%v) cannot exceed max-contract-data-limit (%v)",
+						cfg.DefaultContractDataLimit,
+						cfg.MaxContractDataLimit,
+					)
+				}
+				return nil
+			},
+		},
 		{
 			Name: "max-healthy-ledger-latency",
 			Usage: "maximum ledger latency (i.e. time elapsed since the last known ledger closing time) considered to be healthy" +
@@ -274,6 +431,48 @@ func (cfg *Config) options() ConfigOptions {
 			ConfigKey:    &cfg.PreflightEnableDebug,
 			DefaultValue: true,
 		},
+		{
+			Name:         "simulation-cache-size",
+			Usage:        "Maximum number of simulateTransaction results cached until the next ledger is ingested (0 disables the cache)",
+			ConfigKey:    &cfg.SimulationCacheSize,
+			DefaultValue: uint(1000),
+		},
+		{
+			Name:         "enable-submission-tracking",
+			Usage:        "Track the transactions submitted through sendTransaction (exposed by getSubmissionStatus), resubmitting the ones rejected with TRY_AGAIN_LATER until they expire",
+			ConfigKey:    &cfg.SubmissionTrackingEnabled,
+			DefaultValue: false,
+		},
+		{
+			Name:         "submission-retry-backoff",
+			Usage:        "Delay before resubmitting a tracked transaction rejected with TRY_AGAIN_LATER, doubled after every attempt (up to a minute)",
+			ConfigKey:    &cfg.SubmissionRetryBackoff,
+			DefaultValue: 2 * time.Second,
+			Validate: func(co *ConfigOption) error {
+				if cfg.SubmissionRetryBackoff <= 0 {
+					return fmt.Errorf("submission-retry-backoff must be positive")
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "submission-max-retry-duration",
+			Usage:        "Maximum amount of time a submitted transaction is tracked, for transactions without time bounds or with a later max time",
+			ConfigKey:    &cfg.SubmissionMaxRetryDuration,
+			DefaultValue: 5 * time.Minute,
+			Validate: func(co *ConfigOption) error {
+				if cfg.SubmissionMaxRetryDuration <= 0 {
+					return fmt.Errorf("submission-max-retry-duration must be positive")
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "submission-retention-window",
+			Usage:        "Amount of time the submissions are kept once they are no longer tracked",
+			ConfigKey:    &cfg.SubmissionRetentionWindow,
+			DefaultValue: 24 * time.Hour,
+		},
 		{
 			TomlKey:      strutils.KebabToConstantCase("request-backlog-global-queue-limit"),
 			Usage:        "Maximum number of outstanding requests",
@@ -323,6 +522,34 @@ func (cfg *Config) options() ConfigOptions {
 			DefaultValue: uint(1000),
 			Validate:     positive,
 		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-transactions-queue-limit"),
+			Usage:        "Maximum number of outstanding GetTransactions requests",
+			ConfigKey:    &cfg.RequestBacklogGetTransactionsQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-wait-for-transaction-queue-limit"),
+			Usage:        "Maximum number of outstanding WaitForTransaction requests",
+			ConfigKey:    &cfg.RequestBacklogWaitForTransactionQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-ledgers-queue-limit"),
+			Usage:        "Maximum number of outstanding GetLedgers requests",
+			ConfigKey:    &cfg.RequestBacklogGetLedgersQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-fee-stats-queue-limit"),
+			Usage:        "Maximum number of outstanding GetFeeStats requests",
+			ConfigKey:    &cfg.RequestBacklogGetFeeStatsQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
 		{
 			TomlKey:      strutils.KebabToConstantCase("request-backlog-send-transaction-queue-limit"),
 			Usage:        "Maximum number of outstanding SendTransaction requests",
@@ -337,6 +564,48 @@ func (cfg *Config) options() ConfigOptions {
 			DefaultValue: uint(100),
 			Validate:     positive,
 		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-simulate-transactions-queue-limit"),
+			Usage:        "Maximum number of outstanding SimulateTransactions requests",
+			ConfigKey:    &cfg.RequestBacklogSimulateTransactionsQueueLimit,
+			DefaultValue: uint(50),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-prepare-transaction-queue-limit"),
+			Usage:        "Maximum number of outstanding PrepareTransaction requests",
+			ConfigKey:    &cfg.RequestBacklogPrepareTransactionQueueLimit,
+			DefaultValue: uint(100),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-submission-status-queue-limit"),
+			Usage:        "Maximum number of outstanding GetSubmissionStatus requests",
+			ConfigKey:    &cfg.RequestBacklogGetSubmissionStatusQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-account-activity-queue-limit"),
+			Usage:        "Maximum number of outstanding GetAccountActivity requests",
+			ConfigKey:    &cfg.RequestBacklogGetAccountActivityQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-indexed-ledgers-queue-limit"),
+			Usage:        "Maximum number of outstanding GetIndexedLedgers requests",
+			ConfigKey:    &cfg.RequestBacklogGetIndexedLedgersQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-contract-data-queue-limit"),
+			Usage:        "Maximum number of outstanding GetContractData requests",
+			ConfigKey:    &cfg.RequestBacklogGetContractDataQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
 		{
 			TomlKey:      strutils.KebabToConstantCase("request-execution-warning-threshold"),
 			Usage:        "The request execution warning threshold is the predetermined maximum duration of time that a request can take to be processed before a warning would be generated",
@@ -385,6 +654,30 @@ func (cfg *Config) options() ConfigOptions {
 			ConfigKey:    &cfg.MaxGetTransactionExecutionDuration,
 			DefaultValue: 5 * time.Second,
 		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-transactions-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getTransactions request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetTransactionsExecutionDuration,
+			DefaultValue: 10 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-wait-for-transaction-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a waitForTransaction request, it must exceed the maximum wait timeout. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxWaitForTransactionExecutionDuration,
+			DefaultValue: 24 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-ledgers-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getLedgers request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetLedgersExecutionDuration,
+			DefaultValue: 10 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-fee-stats-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getFeeStats request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetFeeStatsExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
 		{
 			TomlKey:      strutils.KebabToConstantCase("max-send-transaction-execution-duration"),
 			Usage:        "The maximum duration of time allowed for processing a sendTransaction request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
@@ -397,6 +690,42 @@ func (cfg *Config) options() ConfigOptions {
 			ConfigKey:    &cfg.MaxSimulateTransactionExecutionDuration,
 			DefaultValue: 15 * time.Second,
 		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-simulate-transactions-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a simulateTransactions request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxSimulateTransactionsExecutionDuration,
+			DefaultValue: 30 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-prepare-transaction-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a prepareTransaction request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxPrepareTransactionExecutionDuration,
+			DefaultValue: 15 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-submission-status-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getSubmissionStatus request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetSubmissionStatusExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-account-activity-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getAccountActivity request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetAccountActivityExecutionDuration,
+			DefaultValue: 10 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-indexed-ledgers-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getIndexedLedgers request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetIndexedLedgersExecutionDuration,
+			DefaultValue: 10 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-contract-data-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getContractData request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetContractDataExecutionDuration,
+			DefaultValue: 10 * time.Second,
+		},
 	}
 	return *cfg.optionsCache
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ingest"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/transactions"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
@@ -48,8 +49,10 @@ type Daemon struct {
 	ingestService       *ingest.Service
 	db                  *db.DB
 	jsonRPCHandler      *internal.Handler
+	webSocketHandler    *internal.WebSocketHandler
 	logger              *supportlog.Entry
 	preflightWorkerPool *preflight.PreflightWorkerPool
+	submissionTracker   *methods.SubmissionTracker
 	server              *http.Server
 	adminServer         *http.Server
 	closeOnce           sync.Once
@@ -87,6 +90,8 @@ func (d *Daemon) close() {
 		closeErrors = append(closeErrors, err)
 	}
 	d.jsonRPCHandler.Close()
+	d.webSocketHandler.Close()
+	d.submissionTracker.Close()
 	if err := d.db.Close(); err != nil {
 		d.logger.WithError(err).Error("Error closing db")
 		closeErrors = append(closeErrors, err)
@@ -222,6 +227,10 @@ func MustNew(cfg *config.Config) *Daemon {
 	}
 	rdb := clients.NewRedis(logger)
 	indexerService := indexer.New(logger, rdb)
+	simulationCache, err := methods.NewSimulationCache(daemon, cfg.SimulationCacheSize)
+	if err != nil {
+		logger.WithError(err).Fatal("could not create simulation cache")
+	}
 	ingestService := ingest.NewService(ingest.Config{
 		Logger:            logger,
 		DB:                db.NewReadWriter(dbConn, maxLedgerEntryWriteBatchSize, maxRetentionWindow),
@@ -236,6 +245,7 @@ func MustNew(cfg *config.Config) *Daemon {
 		IndexerService:    indexerService,
 		LedgerEntryReader: db.NewLedgerEntryReader(dbConn),
 		Redis:             rdb,
+		SimulationCache:   simulationCache,
 	})
 
 	ledgerEntryReader := db.NewLedgerEntryReader(dbConn)
@@ -249,23 +259,46 @@ func MustNew(cfg *config.Config) *Daemon {
 		logger,
 	)
 
-	jsonRPCHandler := internal.NewJSONRPCHandler(cfg, internal.HandlerParams{
-		Daemon:            daemon,
-		EventStore:        eventStore,
-		TransactionStore:  transactionStore,
-		Logger:            logger,
-		IndexerService:    indexerService,
-		LedgerReader:      db.NewLedgerReader(dbConn),
-		LedgerEntryReader: db.NewLedgerEntryReader(dbConn),
-		PreflightGetter:   preflightWorkerPool,
-	})
+	var submissionTracker *methods.SubmissionTracker
+	if cfg.SubmissionTrackingEnabled {
+		submissionTracker = methods.NewSubmissionTracker(methods.SubmissionTrackerConfig{
+			Logger:           logger,
+			Daemon:           daemon,
+			Submitter:        daemon.CoreClient(),
+			Store:            db.NewSubmissionStore(dbConn),
+			TransactionStore: transactionStore,
+			RetryBackoff:     cfg.SubmissionRetryBackoff,
+			MaxRetryDuration: cfg.SubmissionMaxRetryDuration,
+			RetentionWindow:  cfg.SubmissionRetentionWindow,
+		})
+		submissionTracker.Start()
+	}
+
+	handlerParams := internal.HandlerParams{
+		Daemon:             daemon,
+		EventStore:         eventStore,
+		TransactionStore:   transactionStore,
+		Logger:             logger,
+		IndexerService:     indexerService,
+		LedgerReader:       db.NewLedgerReader(dbConn),
+		LedgerEntryReader:  db.NewLedgerEntryReader(dbConn),
+		ContractDataReader: db.NewContractDataReader(dbConn),
+		PreflightGetter:    preflightWorkerPool,
+		SimulationCache:    simulationCache,
+		SubmissionTracker:  submissionTracker,
+	}
+	jsonRPCHandler := internal.NewJSONRPCHandler(cfg, handlerParams)
+	webSocketHandler := internal.NewWebSocketHandler(cfg, handlerParams)
 
 	httpHandler := supporthttp.NewAPIMux(logger)
+	httpHandler.Handle("/ws", webSocketHandler)
 	httpHandler.Handle("/", jsonRPCHandler)
 
 	daemon.preflightWorkerPool = preflightWorkerPool
+	daemon.submissionTracker = submissionTracker
 	daemon.ingestService = ingestService
 	daemon.jsonRPCHandler = &jsonRPCHandler
+	daemon.webSocketHandler = webSocketHandler
 
 	daemon.server = &http.Server{
 		Addr:        cfg.Endpoint,
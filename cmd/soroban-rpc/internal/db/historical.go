@@ -0,0 +1,358 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// historicalLedgerBatchSize is the number of ledgers read at once when walking through the stored ledgers
+const historicalLedgerBatchSize = 16
+
+// LedgerOutOfRangeError is returned when the ledger state can't be reconstructed for a ledger,
+// because it's outside the stored ledger range.
+type LedgerOutOfRangeError struct {
+	Ledger      uint32
+	FirstLedger uint32
+	LastLedger  uint32
+}
+
+func (e *LedgerOutOfRangeError) Error() string {
+	return fmt.Sprintf("ledger %d is outside the retention window [%d, %d]", e.Ledger, e.FirstLedger, e.LastLedger)
+}
+
+// HistoricalLedgerEntryTx provides the ledger entries as they were right after a past ledger closed,
+// reconstructing them from the current entries of a read transaction and the LedgerCloseMeta stored
+// since that ledger. Undoing the changes of the later ledgers, the historical entry of a key is the
+// pre-state of its first change after the ledger, or the current entry if it didn't change since.
+type HistoricalLedgerEntryTx struct {
+	LedgerEntryReadTx
+	ctx          context.Context
+	ledgerReader LedgerReader
+	ledger       uint32
+	latestLedger uint32
+	buffer       *xdr.EncodingBuffer
+	// reconstructed entries by encoded key, nil if they didn't exist
+	entries map[string]*xdr.LedgerEntry
+	// XDR-encoded entries before their first change after the ledger by encoded key, empty if they
+	// didn't exist, and the ledgers in which entries were evicted without changing before. Both are
+	// set at once by undoLedgers. Keeping the entries encoded bounds their memory to the size of the
+	// ledgers, and only the entries which are looked up get decoded.
+	changed map[string]string
+	evicted map[string]uint32
+}
+
+// NewHistoricalLedgerEntryTx creates a view of the ledger state of the given read transaction after the
+// given ledger closed, which must be within the ledgers stored by the ledger reader and at most maxDepth
+// ledgers behind the latest ledger (since every later ledger is read to reconstruct the state).
+func NewHistoricalLedgerEntryTx(ctx context.Context, tx LedgerEntryReadTx, ledgerReader LedgerReader, ledger uint32, maxDepth uint32) (*HistoricalLedgerEntryTx, error) {
+	latestLedger, err := tx.GetLatestLedgerSequence()
+	if err != nil {
+		return nil, err
+	}
+	firstLedger, _, err := ledgerReader.GetLedgerSequenceRange(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if latestLedger > maxDepth && latestLedger-maxDepth > firstLedger {
+		firstLedger = latestLedger - maxDepth
+	}
+	if ledger > latestLedger || ledger < firstLedger || firstLedger == 0 {
+		return nil, &LedgerOutOfRangeError{
+			Ledger:      ledger,
+			FirstLedger: firstLedger,
+			LastLedger:  latestLedger,
+		}
+	}
+	return &HistoricalLedgerEntryTx{
+		LedgerEntryReadTx: tx,
+		ctx:               ctx,
+		ledgerReader:      ledgerReader,
+		ledger:            ledger,
+		latestLedger:      latestLedger,
+		buffer:            xdr.NewEncodingBuffer(),
+		entries:           make(map[string]*xdr.LedgerEntry),
+	}, nil
+}
+
+// GetLatestLedgerSequence returns the historical ledger
+func (h *HistoricalLedgerEntryTx) GetLatestLedgerSequence() (uint32, error) {
+	return h.ledger, nil
+}
+
+// ledgerEntryChanges returns the ledger entry changes of a ledger in the order they were applied:
+// fee processing, transactions and upgrades. Evictions (which happen last) aren't included.
+func ledgerEntryChanges(ledger xdr.LedgerCloseMeta) []xdr.LedgerEntryChanges {
+	var result []xdr.LedgerEntryChanges
+	txCount := ledger.CountTransactions()
+	for i := 0; i < txCount; i++ {
+		result = append(result, ledger.FeeProcessing(i))
+	}
+	for i := 0; i < txCount; i++ {
+		meta := ledger.TxApplyProcessing(i)
+		var operations []xdr.OperationMeta
+		switch meta.V {
+		case 0:
+			if meta.Operations != nil {
+				operations = *meta.Operations
+			}
+		case 1:
+			result = append(result, meta.V1.TxChanges)
+			operations = meta.V1.Operations
+		case 2:
+			result = append(result, meta.V2.TxChangesBefore)
+			operations = meta.V2.Operations
+		case 3:
+			result = append(result, meta.V3.TxChangesBefore)
+			operations = meta.V3.Operations
+		}
+		for _, operation := range operations {
+			result = append(result, operation.Changes)
+		}
+		switch meta.V {
+		case 2:
+			result = append(result, meta.V2.TxChangesAfter)
+		case 3:
+			result = append(result, meta.V3.TxChangesAfter)
+		}
+	}
+	for _, upgrade := range ledger.UpgradesProcessing() {
+		result = append(result, upgrade.Changes)
+	}
+	return result
+}
+
+// undoLedger records the state before the ledger of the entries it changed, unless they changed
+// in a previous ledger. Entries which didn't exist before the ledger are recorded as nil.
+func (h *HistoricalLedgerEntryTx) undoLedger(ledger xdr.LedgerCloseMeta) error {
+	for _, changes := range ledgerEntryChanges(ledger) {
+		for _, change := range changes {
+			key, err := change.LedgerKey()
+			if err != nil {
+				return err
+			}
+			encodedKey, err := encodeLedgerKey(h.buffer, key)
+			if err != nil {
+				return err
+			}
+			if _, ok := h.changed[encodedKey]; ok {
+				continue
+			}
+			if _, ok := h.evicted[encodedKey]; ok {
+				continue
+			}
+			switch change.Type {
+			case xdr.LedgerEntryChangeTypeLedgerEntryState:
+				state := change.MustState()
+				// this is safe since we are converting to string right away, which causes a copy
+				encodedState, err := h.buffer.UnsafeMarshalBinary(&state)
+				if err != nil {
+					return err
+				}
+				h.changed[encodedKey] = string(encodedState)
+			case xdr.LedgerEntryChangeTypeLedgerEntryCreated:
+				h.changed[encodedKey] = ""
+			default:
+				// updates and removals are preceded by the state of the entry
+				return fmt.Errorf("ledger %d changes entry %v without its previous state", ledger.LedgerSequence(), key)
+			}
+		}
+	}
+	evictedKeys, err := ledger.EvictedTemporaryLedgerKeys()
+	if err != nil {
+		return err
+	}
+	for _, key := range evictedKeys {
+		encodedKey, err := encodeLedgerKey(h.buffer, key)
+		if err != nil {
+			return err
+		}
+		if _, ok := h.changed[encodedKey]; ok {
+			continue
+		}
+		if _, ok := h.evicted[encodedKey]; !ok {
+			h.evicted[encodedKey] = ledger.LedgerSequence()
+		}
+	}
+	return nil
+}
+
+// undoLedgers walks once through the ledgers after the historical ledger, recording the first change
+// (or eviction) of every entry, so that any number of keys can be reconstructed without reading them again.
+func (h *HistoricalLedgerEntryTx) undoLedgers() error {
+	if h.changed != nil {
+		return nil
+	}
+	h.changed = make(map[string]string)
+	h.evicted = make(map[string]uint32)
+	for next := h.ledger + 1; next <= h.latestLedger; {
+		ledgers, err := h.ledgerReader.BatchGetLedgers(h.ctx, next, historicalLedgerBatchSize)
+		if err != nil {
+			h.changed = nil
+			return err
+		}
+		for _, ledger := range ledgers {
+			if next > h.latestLedger {
+				break
+			}
+			if ledger.LedgerSequence() != next {
+				h.changed = nil
+				return fmt.Errorf("ledger %d is missing", next)
+			}
+			if err := h.undoLedger(ledger); err != nil {
+				h.changed = nil
+				return err
+			}
+			next++
+		}
+		if len(ledgers) == 0 {
+			h.changed = nil
+			return fmt.Errorf("ledger %d is missing", next)
+		}
+	}
+	return nil
+}
+
+// historicalEntries returns the historical entries of the keys changed after the ledger, by encoded key
+func (h *HistoricalLedgerEntryTx) historicalEntries(keys []xdr.LedgerKey) (map[string]*xdr.LedgerEntry, error) {
+	if err := h.undoLedgers(); err != nil {
+		return nil, err
+	}
+	entries := make(map[string]*xdr.LedgerEntry, len(keys))
+	for _, key := range keys {
+		encodedKey, err := encodeLedgerKey(h.buffer, key)
+		if err != nil {
+			return nil, err
+		}
+		if ledger, ok := h.evicted[encodedKey]; ok {
+			// the ledger metadata doesn't include the evicted entries
+			return nil, fmt.Errorf("entry %v was evicted in ledger %d and cannot be reconstructed", key, ledger)
+		}
+		encodedEntry, ok := h.changed[encodedKey]
+		if !ok {
+			continue
+		}
+		if encodedEntry == "" {
+			entries[encodedKey] = nil
+			continue
+		}
+		var entry xdr.LedgerEntry
+		if err := xdr.SafeUnmarshal([]byte(encodedEntry), &entry); err != nil {
+			return nil, err
+		}
+		entries[encodedKey] = &entry
+	}
+	return entries, nil
+}
+
+func (h *HistoricalLedgerEntryTx) GetLedgerEntries(keys ...xdr.LedgerKey) ([]LedgerKeyAndEntry, error) {
+	// the TTLs are reconstructed along with their entries
+	var missingKeys []xdr.LedgerKey
+	ttlKeys := make([]*xdr.LedgerKey, len(keys))
+	addMissing := func(key xdr.LedgerKey) error {
+		encodedKey, err := encodeLedgerKey(h.buffer, key)
+		if err != nil {
+			return err
+		}
+		if _, ok := h.entries[encodedKey]; !ok {
+			missingKeys = append(missingKeys, key)
+		}
+		return nil
+	}
+	for i, key := range keys {
+		if err := addMissing(key); err != nil {
+			return nil, err
+		}
+		if !hasTTLKey(key) {
+			continue
+		}
+		ttlKey, err := entryKeyToTTLEntryKey(key)
+		if err != nil {
+			return nil, err
+		}
+		ttlKeys[i] = &ttlKey
+		if err := addMissing(ttlKey); err != nil {
+			return nil, err
+		}
+	}
+	if err := h.reconstruct(missingKeys); err != nil {
+		return nil, err
+	}
+
+	lookup := func(key xdr.LedgerKey) (*xdr.LedgerEntry, error) {
+		encodedKey, err := encodeLedgerKey(h.buffer, key)
+		if err != nil {
+			return nil, err
+		}
+		return h.entries[encodedKey], nil
+	}
+	result := make([]LedgerKeyAndEntry, 0, len(keys))
+	for i, key := range keys {
+		entry, err := lookup(key)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			continue
+		}
+		if ttlKeys[i] == nil {
+			result = append(result, LedgerKeyAndEntry{Key: key, Entry: *entry})
+			continue
+		}
+		ttlEntry, err := lookup(*ttlKeys[i])
+		if err != nil {
+			return nil, err
+		}
+		if ttlEntry == nil {
+			return nil, errors.New("missing ttl key entry")
+		}
+		liveUntilSeq := uint32(ttlEntry.Data.MustTtl().LiveUntilLedgerSeq)
+		result = append(result, LedgerKeyAndEntry{Key: key, Entry: *entry, LiveUntilLedgerSeq: &liveUntilSeq})
+	}
+	return result, nil
+}
+
+// reconstruct adds the historical entries of the keys to the reconstructed entries
+func (h *HistoricalLedgerEntryTx) reconstruct(keys []xdr.LedgerKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	changed, err := h.historicalEntries(keys)
+	if err != nil {
+		return err
+	}
+	for encodedKey, entry := range changed {
+		h.entries[encodedKey] = entry
+	}
+
+	// the entries which didn't change since the ledger are obtained from the underlying transaction
+	var unchangedKeys []xdr.LedgerKey
+	for _, key := range keys {
+		encodedKey, err := encodeLedgerKey(h.buffer, key)
+		if err != nil {
+			return err
+		}
+		if _, ok := changed[encodedKey]; !ok {
+			unchangedKeys = append(unchangedKeys, key)
+			h.entries[encodedKey] = nil
+		}
+	}
+	if len(unchangedKeys) == 0 {
+		return nil
+	}
+	current, err := h.LedgerEntryReadTx.GetLedgerEntries(unchangedKeys...)
+	if err != nil {
+		return err
+	}
+	for _, keyAndEntry := range current {
+		encodedKey, err := encodeLedgerKey(h.buffer, keyAndEntry.Key)
+		if err != nil {
+			return err
+		}
+		entry := keyAndEntry.Entry
+		h.entries[encodedKey] = &entry
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+)
+
+// countingLedgerReader counts the ledger batches read
+type countingLedgerReader struct {
+	LedgerReader
+	batches int
+}
+
+func (r *countingLedgerReader) BatchGetLedgers(ctx context.Context, startSequence uint32, limit uint) ([]xdr.LedgerCloseMeta, error) {
+	r.batches++
+	return r.LedgerReader.BatchGetLedgers(ctx, startSequence, limit)
+}
+
+func TestHistoricalLedgerEntryTx(t *testing.T) {
+	db := NewTestDB(t)
+	readWriter := NewReadWriter(db, 150, 15)
+
+	contractData := func(key, val uint32) (xdr.LedgerKey, xdr.LedgerEntry, xdr.LedgerKey) {
+		scKey, scVal := xdr.Uint32(key), xdr.Uint32(val)
+		ledgerKey, entry := getContractDataLedgerEntry(t, xdr.ContractDataEntry{
+			Contract: xdr.ScAddress{
+				Type:       xdr.ScAddressTypeScAddressTypeContract,
+				ContractId: &xdr.Hash{0xca, 0xfe},
+			},
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &scKey},
+			Durability: xdr.ContractDataDurabilityPersistent,
+			Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &scVal},
+		})
+		ttlKey, err := entryKeyToTTLEntryKey(ledgerKey)
+		require.NoError(t, err)
+		return ledgerKey, entry, ttlKey
+	}
+	ttl := func(key xdr.LedgerKey, liveUntil uint32) xdr.LedgerEntry {
+		entry := getTTLLedgerEntry(key)
+		entry.Data.Ttl.LiveUntilLedgerSeq = xdr.Uint32(liveUntil)
+		return entry
+	}
+	state := func(entry xdr.LedgerEntry) xdr.LedgerEntryChange {
+		return xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &entry}
+	}
+	updated := func(entry xdr.LedgerEntry) xdr.LedgerEntryChange {
+		return xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &entry}
+	}
+	created := func(entry xdr.LedgerEntry) xdr.LedgerEntryChange {
+		return xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &entry}
+	}
+	removed := func(key xdr.LedgerKey) xdr.LedgerEntryChange {
+		return xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &key}
+	}
+	// ingest applies the changes of a ledger to the ledger entries and stores it
+	ingest := func(sequence uint32, changes ...xdr.LedgerEntryChange) {
+		ledger := createLedger(sequence)
+		ledger.V1.TxProcessing = []xdr.TransactionResultMeta{{
+			Result: xdr.TransactionResultPair{
+				Result: xdr.TransactionResult{
+					Result: xdr.TransactionResultResult{
+						Code:    xdr.TransactionResultCodeTxSuccess,
+						Results: &[]xdr.OperationResult{},
+					},
+				},
+			},
+			TxApplyProcessing: xdr.TransactionMeta{
+				V:  3,
+				V3: &xdr.TransactionMetaV3{Operations: []xdr.OperationMeta{{Changes: changes}}},
+			},
+		}}
+		tx, err := readWriter.NewTx(context.Background())
+		require.NoError(t, err)
+		for _, change := range changes {
+			if entry, ok := change.GetLedgerEntry(); ok && change.Type != xdr.LedgerEntryChangeTypeLedgerEntryState {
+				require.NoError(t, tx.LedgerEntryWriter().UpsertLedgerEntry(entry))
+			} else if change.Type == xdr.LedgerEntryChangeTypeLedgerEntryRemoved {
+				require.NoError(t, tx.LedgerEntryWriter().DeleteLedgerEntry(*change.Removed))
+			}
+		}
+		require.NoError(t, tx.LedgerWriter().InsertLedger(ledger))
+		require.NoError(t, tx.Commit(sequence))
+	}
+
+	keyA, a1, ttlKeyA := contractData(1, 1)
+	_, a2, _ := contractData(1, 2)
+	_, a3, _ := contractData(1, 3)
+	keyB, b1, ttlKeyB := contractData(2, 1)
+	keyC, c1, ttlKeyC := contractData(3, 1)
+	ingest(10, created(a1), created(ttl(ttlKeyA, 100)), created(b1), created(ttl(ttlKeyB, 100)))
+	ingest(11, state(a1), updated(a2), state(ttl(ttlKeyA, 100)), updated(ttl(ttlKeyA, 200)), created(c1), created(ttl(ttlKeyC, 100)))
+	ingest(12, state(b1), removed(keyB), state(ttl(ttlKeyB, 100)), removed(ttlKeyB), state(a2), updated(a3))
+
+	readTx, err := NewLedgerEntryReader(db).NewTx(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, readTx.Done())
+	}()
+	entriesAt := func(ledger uint32) map[uint32]uint32 {
+		historicalTx, err := NewHistoricalLedgerEntryTx(context.Background(), readTx, NewLedgerReader(db), ledger, 100)
+		require.NoError(t, err)
+		sequence, err := historicalTx.GetLatestLedgerSequence()
+		require.NoError(t, err)
+		assert.Equal(t, ledger, sequence)
+		entries, err := historicalTx.GetLedgerEntries(keyA, keyB, keyC)
+		require.NoError(t, err)
+		// value of each entry by key, with its live-until ledger
+		result := map[uint32]uint32{}
+		for _, entry := range entries {
+			data := entry.Entry.Data.MustContractData()
+			result[uint32(*data.Key.U32)] = uint32(*data.Val.U32)
+			result[100+uint32(*data.Key.U32)] = *entry.LiveUntilLedgerSeq
+		}
+		// lookups are memoized
+		cached, err := historicalTx.GetLedgerEntries(keyA, keyB, keyC)
+		require.NoError(t, err)
+		assert.Equal(t, entries, cached)
+		return result
+	}
+	assert.Equal(t, map[uint32]uint32{1: 1, 101: 100, 2: 1, 102: 100}, entriesAt(10))
+	assert.Equal(t, map[uint32]uint32{1: 2, 101: 200, 2: 1, 102: 100, 3: 1, 103: 100}, entriesAt(11))
+	assert.Equal(t, map[uint32]uint32{1: 3, 101: 200, 3: 1, 103: 100}, entriesAt(12))
+
+	// keys looked up one at a time (like the preflight does) are reconstructed from a single walk through the ledgers
+	ledgerReader := &countingLedgerReader{LedgerReader: NewLedgerReader(db)}
+	historicalTx, err := NewHistoricalLedgerEntryTx(context.Background(), readTx, ledgerReader, 10, 100)
+	require.NoError(t, err)
+	for _, key := range []xdr.LedgerKey{keyA, keyB, keyC} {
+		_, err = historicalTx.GetLedgerEntries(key)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, ledgerReader.batches)
+
+	for _, ledger := range []uint32{9, 13} {
+		_, err = NewHistoricalLedgerEntryTx(context.Background(), readTx, NewLedgerReader(db), ledger, 100)
+		var outOfRangeErr *LedgerOutOfRangeError
+		require.ErrorAs(t, err, &outOfRangeErr)
+		assert.Equal(t, LedgerOutOfRangeError{Ledger: ledger, FirstLedger: 10, LastLedger: 12}, *outOfRangeErr)
+	}
+
+	// ledgers deeper than the maximum depth aren't reconstructed, even if they are stored
+	_, err = NewHistoricalLedgerEntryTx(context.Background(), readTx, NewLedgerReader(db), 10, 1)
+	var outOfRangeErr *LedgerOutOfRangeError
+	require.ErrorAs(t, err, &outOfRangeErr)
+	assert.Equal(t, LedgerOutOfRangeError{Ledger: 10, FirstLedger: 11, LastLedger: 12}, *outOfRangeErr)
+	_, err = NewHistoricalLedgerEntryTx(context.Background(), readTx, NewLedgerReader(db), 11, 1)
+	require.NoError(t, err)
+}
@@ -2,10 +2,12 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
 
+	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/xdr"
 )
 
@@ -18,6 +20,20 @@ type StreamLedgerFn func(xdr.LedgerCloseMeta) error
 type LedgerReader interface {
 	GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, bool, error)
 	StreamAllLedgers(ctx context.Context, f StreamLedgerFn) error
+	BatchGetLedgers(ctx context.Context, startSequence uint32, limit uint) ([]xdr.LedgerCloseMeta, error)
+	GetLedgerSequenceRange(ctx context.Context) (uint32, uint32, error)
+	GetLedgerRange(ctx context.Context) (LedgerRange, error)
+}
+
+type LedgerSeqAndCloseTime struct {
+	Sequence  uint32
+	CloseTime int64
+}
+
+// LedgerRange is the range of ledgers stored in the database, it is empty if there are none.
+type LedgerRange struct {
+	FirstLedger LedgerSeqAndCloseTime
+	LastLedger  LedgerSeqAndCloseTime
 }
 
 type LedgerWriter interface {
@@ -69,6 +85,78 @@ func (r ledgerReader) GetLedger(ctx context.Context, sequence uint32) (xdr.Ledge
 	}
 }
 
+// BatchGetLedgers fetches up to limit consecutive ledgers from the db, starting at startSequence.
+func (r ledgerReader) BatchGetLedgers(ctx context.Context, startSequence uint32, limit uint) ([]xdr.LedgerCloseMeta, error) {
+	sql := sq.Select("meta").
+		From(ledgerCloseMetaTableName).
+		Where(sq.GtOrEq{"sequence": startSequence}).
+		OrderBy("sequence asc").
+		Limit(uint64(limit))
+	var results []xdr.LedgerCloseMeta
+	if err := r.db.Select(ctx, &results, sql); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetLedgerSequenceRange fetches the sequences of the first and last ledgers stored in the db, without
+// reading the ledgers themselves. Both are 0 if there are none.
+func (r ledgerReader) GetLedgerSequenceRange(ctx context.Context) (uint32, uint32, error) {
+	return getLedgerSequenceRange(ctx, r.db)
+}
+
+func getLedgerSequenceRange(ctx context.Context, session db.SessionInterface) (uint32, uint32, error) {
+	sql := sq.Select("COALESCE(MIN(sequence), 0) AS first", "COALESCE(MAX(sequence), 0) AS last").
+		From(ledgerCloseMetaTableName)
+	var bounds struct {
+		First uint32 `db:"first"`
+		Last  uint32 `db:"last"`
+	}
+	if err := session.Get(ctx, &bounds, sql); err != nil {
+		return 0, 0, err
+	}
+	return bounds.First, bounds.Last, nil
+}
+
+// GetLedgerRange fetches the first and last ledgers stored in the db, along with their close times.
+func (r ledgerReader) GetLedgerRange(ctx context.Context) (LedgerRange, error) {
+	// Use a read transaction, so that the boundaries don't move (due to ingestion or trimming)
+	// between the queries.
+	txSession := r.db.Clone()
+	if err := txSession.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return LedgerRange{}, err
+	}
+	defer func() {
+		_ = txSession.Rollback()
+	}()
+
+	first, last, err := getLedgerSequenceRange(ctx, txSession)
+	if err != nil || last == 0 {
+		return LedgerRange{}, err
+	}
+	query := sq.Select("meta").
+		From(ledgerCloseMetaTableName).
+		Where(sq.Eq{"sequence": []uint32{first, last}}).
+		OrderBy("sequence asc")
+	var results []xdr.LedgerCloseMeta
+	if err := txSession.Select(ctx, &results, query); err != nil {
+		return LedgerRange{}, err
+	}
+	if len(results) == 0 || len(results) > 2 {
+		return LedgerRange{}, fmt.Errorf("unexpected number of lcm entries (%d) for the ledger range [%d, %d]", len(results), first, last)
+	}
+	boundary := func(lcm xdr.LedgerCloseMeta) LedgerSeqAndCloseTime {
+		return LedgerSeqAndCloseTime{
+			Sequence:  lcm.LedgerSequence(),
+			CloseTime: int64(lcm.LedgerHeaderHistoryEntry().Header.ScpValue.CloseTime),
+		}
+	}
+	return LedgerRange{
+		FirstLedger: boundary(results[0]),
+		LastLedger:  boundary(results[len(results)-1]),
+	}, nil
+}
+
 type ledgerWriter struct {
 	stmtCache *sq.StmtCache
 }
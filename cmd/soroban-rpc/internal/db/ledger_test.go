@@ -17,6 +17,7 @@ func createLedger(ledgerSequence uint32) xdr.LedgerCloseMeta {
 				Hash: xdr.Hash{},
 				Header: xdr.LedgerHeader{
 					LedgerSeq: xdr.Uint32(ledgerSequence),
+					ScpValue:  xdr.StellarValue{CloseTime: xdr.TimePoint(5 * ledgerSequence)},
 				},
 			},
 			TxSet: xdr.GeneralizedTransactionSet{
@@ -55,6 +56,29 @@ func assertLedgerRange(t *testing.T, reader LedgerReader, start, end uint32) {
 		allLedgers = allLedgers[1:]
 	}
 	assert.Empty(t, allLedgers)
+
+	ledgerRange, err := reader.GetLedgerRange(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, start, ledgerRange.FirstLedger.Sequence)
+	assert.Equal(t, end, ledgerRange.LastLedger.Sequence)
+	assert.Equal(t, int64(5*start), ledgerRange.FirstLedger.CloseTime)
+	assert.Equal(t, int64(5*end), ledgerRange.LastLedger.CloseTime)
+
+	first, last, err := reader.GetLedgerSequenceRange(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, start, first)
+	assert.Equal(t, end, last)
+
+	ledgers, err := reader.BatchGetLedgers(context.Background(), start+1, 2)
+	assert.NoError(t, err)
+	assert.Len(t, ledgers, 2)
+	assert.Equal(t, start+1, ledgers[0].LedgerSequence())
+	assert.Equal(t, start+2, ledgers[1].LedgerSequence())
+
+	ledgers, err = reader.BatchGetLedgers(context.Background(), end, 10)
+	assert.NoError(t, err)
+	assert.Len(t, ledgers, 1)
+	assert.Equal(t, end, ledgers[0].LedgerSequence())
 }
 
 func TestLedgers(t *testing.T) {
@@ -64,6 +88,13 @@ func TestLedgers(t *testing.T) {
 	_, exists, err := reader.GetLedger(context.Background(), 1)
 	assert.NoError(t, err)
 	assert.False(t, exists)
+	ledgerRange, err := reader.GetLedgerRange(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, LedgerRange{}, ledgerRange)
+	first, last, err := reader.GetLedgerSequenceRange(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, first)
+	assert.Zero(t, last)
 
 	for i := 1; i <= 10; i++ {
 		ledgerSequence := uint32(i)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"database/sql"
+	"encoding/binary"
 	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
@@ -15,6 +16,7 @@ import (
 
 const (
 	ledgerEntriesTableName = "ledger_entries"
+	contractDataTableName  = "contract_data"
 )
 
 type LedgerEntryReader interface {
@@ -35,6 +37,23 @@ type LedgerEntryReadTx interface {
 	Done() error
 }
 
+// ContractDataRequest selects the contract data entries of a contract, ordered by key
+type ContractDataRequest struct {
+	Contract   xdr.ScAddress
+	Durability xdr.ContractDataDurability
+	// KeyType optionally restricts the entries to the keys of the given type
+	KeyType *xdr.ScValType
+	// Cursor is the key of the last entry of a previous request, only the entries after it are returned
+	Cursor *xdr.LedgerKey
+	Limit  uint
+}
+
+// ContractDataReader enumerates the contract data entries of contracts, using the contract data index
+type ContractDataReader interface {
+	// GetContractData returns the selected contract data entries along with the latest ledger sequence
+	GetContractData(ctx context.Context, request ContractDataRequest) ([]LedgerKeyAndEntry, uint32, error)
+}
+
 type LedgerEntryWriter interface {
 	UpsertLedgerEntry(entry xdr.LedgerEntry) error
 	DeleteLedgerEntry(key xdr.LedgerKey) error
@@ -86,6 +105,10 @@ func (l ledgerEntryWriter) flush() error {
 	upsertCount := 0
 	upsertSQL := sq.StatementBuilder.RunWith(l.stmtCache).Replace(ledgerEntriesTableName)
 	var deleteKeys = make([]string, 0, len(l.keyToEntryBatch))
+	// the contract data index is maintained along with the entries
+	contractDataCount := 0
+	contractDataSQL := sq.StatementBuilder.RunWith(l.stmtCache).Replace(contractDataTableName)
+	var contractDataDeleteKeys []string
 
 	upsertCacheUpdates := make(map[string]*string, len(l.keyToEntryBatch))
 	for key, entry := range l.keyToEntryBatch {
@@ -102,8 +125,24 @@ func (l ledgerEntryWriter) flush() error {
 			if entry.Data.Type == xdr.LedgerEntryTypeConfigSetting {
 				upsertCacheUpdates[key] = &encodedEntryStr
 			}
+			if entry.Data.Type == xdr.LedgerEntryTypeContractData {
+				contractData := entry.Data.MustContractData()
+				contractBytes, err := contractData.Contract.MarshalBinary()
+				if err != nil {
+					return err
+				}
+				contractDataSQL = contractDataSQL.Values(key, contractBytes, int(contractData.Durability))
+				contractDataCount += 1
+			}
 		} else {
 			deleteKeys = append(deleteKeys, key)
+			keyType, err := xdr.GetBinaryCompressedLedgerKeyType([]byte(key))
+			if err != nil {
+				return err
+			}
+			if keyType == xdr.LedgerEntryTypeContractData {
+				contractDataDeleteKeys = append(contractDataDeleteKeys, key)
+			}
 		}
 		// Delete each entry instead of reassigning l.keyToEntryBatch
 		// to the empty map because the map was allocated with a
@@ -133,6 +172,19 @@ func (l ledgerEntryWriter) flush() error {
 		}
 	}
 
+	if contractDataCount > 0 {
+		if _, err := contractDataSQL.Exec(); err != nil {
+			return err
+		}
+	}
+
+	if len(contractDataDeleteKeys) > 0 {
+		deleteSQL := sq.StatementBuilder.RunWith(l.stmtCache).Delete(contractDataTableName).Where(sq.Eq{"key": contractDataDeleteKeys})
+		if _, err := deleteSQL.Exec(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -327,6 +379,107 @@ func (l *ledgerEntryReadTx) GetLedgerEntries(keys ...xdr.LedgerKey) ([]LedgerKey
 	return result, nil
 }
 
+// contractDataKeyPrefix returns the prefix of the compressed keys of the contract data entries
+// of a contract whose key has the given type
+func contractDataKeyPrefix(contractBytes []byte, keyType uint32) string {
+	prefix := make([]byte, 0, 1+len(contractBytes)+4)
+	prefix = append(prefix, byte(xdr.LedgerEntryTypeContractData))
+	prefix = append(prefix, contractBytes...)
+	prefix = binary.BigEndian.AppendUint32(prefix, keyType)
+	return string(prefix)
+}
+
+func (l *ledgerEntryReadTx) getContractData(request ContractDataRequest) ([]LedgerKeyAndEntry, error) {
+	contractBytes, err := request.Contract.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	builder := sq.StatementBuilder
+	if l.stmtCache != nil {
+		builder = builder.RunWith(l.stmtCache)
+	} else {
+		builder = builder.RunWith(l.tx.GetTx())
+	}
+	indexKey := contractDataTableName + ".key"
+	sql := builder.Select(ledgerEntriesTableName + ".entry").
+		From(contractDataTableName).
+		Join(ledgerEntriesTableName + " ON " + ledgerEntriesTableName + ".key = " + indexKey).
+		// contract_id is compared as a whole, sq.Eq would expand the byte slice
+		Where(sq.Expr(contractDataTableName+".contract_id = ?", contractBytes)).
+		Where(sq.Eq{contractDataTableName + ".durability": int(request.Durability)}).
+		OrderBy(indexKey).
+		Limit(uint64(request.Limit))
+	if request.KeyType != nil {
+		// the keys of the contract are ordered by the type of their ScVal, which comes first in their XDR
+		sql = sql.Where(sq.GtOrEq{indexKey: contractDataKeyPrefix(contractBytes, uint32(*request.KeyType))}).
+			Where(sq.Lt{indexKey: contractDataKeyPrefix(contractBytes, uint32(*request.KeyType)+1)})
+	}
+	if request.Cursor != nil {
+		encodedCursor, err := encodeLedgerKey(l.buffer, *request.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		sql = sql.Where(sq.Gt{indexKey: encodedCursor})
+	}
+	q, err := sql.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer q.Close()
+
+	var result []LedgerKeyAndEntry
+	var encodedTTLKeys []string
+	for q.Next() {
+		var encodedEntry string
+		if err = q.Scan(&encodedEntry); err != nil {
+			return nil, err
+		}
+		var entry xdr.LedgerEntry
+		if err := xdr.SafeUnmarshal([]byte(encodedEntry), &entry); err != nil {
+			return nil, errors.Wrap(err, "cannot decode ledger entry from DB")
+		}
+		key, err := entry.LedgerKey()
+		if err != nil {
+			return nil, err
+		}
+		ttlKey, err := entryKeyToTTLEntryKey(key)
+		if err != nil {
+			return nil, err
+		}
+		encodedTTLKey, err := encodeLedgerKey(l.buffer, ttlKey)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, LedgerKeyAndEntry{Key: key, Entry: entry})
+		encodedTTLKeys = append(encodedTTLKeys, encodedTTLKey)
+	}
+	if err = q.Err(); err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return result, nil
+	}
+
+	rawTTLEntries, err := l.getRawLedgerEntries(encodedTTLKeys...)
+	if err != nil {
+		return nil, err
+	}
+	for i, encodedTTLKey := range encodedTTLKeys {
+		encodedTTLEntry, ok := rawTTLEntries[encodedTTLKey]
+		if !ok {
+			// missing ttl key. This should not happen.
+			return nil, errors.New("missing ttl key entry")
+		}
+		var ttlEntry xdr.LedgerEntry
+		if err := xdr.SafeUnmarshal([]byte(encodedTTLEntry), &ttlEntry); err != nil {
+			return nil, errors.Wrap(err, "cannot decode TTL ledger entry from DB")
+		}
+		liveUntilSeq := uint32(ttlEntry.Data.Ttl.LiveUntilLedgerSeq)
+		result[i].LiveUntilLedgerSeq = &liveUntilSeq
+	}
+	return result, nil
+}
+
 func (l ledgerEntryReadTx) Done() error {
 	// Since it's a read-only transaction, we don't
 	// care whether we commit it or roll it back as long as we close it
@@ -371,6 +524,14 @@ func (r ledgerEntryReader) NewCachedTx(ctx context.Context) (LedgerEntryReadTx,
 }
 
 func (r ledgerEntryReader) NewTx(ctx context.Context) (LedgerEntryReadTx, error) {
+	tx, err := r.newTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (r ledgerEntryReader) newTx(ctx context.Context) (*ledgerEntryReadTx, error) {
 	txSession := r.db.Clone()
 	if err := txSession.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
 		return nil, err
@@ -385,6 +546,30 @@ func (r ledgerEntryReader) NewTx(ctx context.Context) (LedgerEntryReadTx, error)
 	}, nil
 }
 
+// NewContractDataReader returns a reader of the contract data entries indexed by contract
+func NewContractDataReader(db *DB) ContractDataReader {
+	return ledgerEntryReader{db: db}
+}
+
+func (r ledgerEntryReader) GetContractData(ctx context.Context, request ContractDataRequest) ([]LedgerKeyAndEntry, uint32, error) {
+	tx, err := r.newTx(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		_ = tx.Done()
+	}()
+	latestLedger, err := tx.GetLatestLedgerSequence()
+	if err != nil {
+		return nil, 0, err
+	}
+	entries, err := tx.getContractData(request)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, latestLedger, nil
+}
+
 func encodeLedgerKey(buffer *xdr.EncodingBuffer, key xdr.LedgerKey) (string, error) {
 	// this is safe since we are converting to string right away, which causes a copy
 	binKey, err := buffer.LedgerKeyUnsafeMarshalBinaryCompress(key)
@@ -582,3 +582,113 @@ func NewTestDB(tb testing.TB) *DB {
 		},
 	}
 }
+
+func TestGetContractData(t *testing.T) {
+	dbPath := path.Join(t.TempDir(), "db.sqlite")
+	db, err := OpenSQLiteDB(dbPath)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+	contractA := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.Hash{0xca, 0xfe}}
+	contractB := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.Hash{0xbe, 0xef}}
+	u32 := func(v uint32) xdr.ScVal {
+		val := xdr.Uint32(v)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &val}
+	}
+	symbol := func(v string) xdr.ScVal {
+		val := xdr.ScSymbol(v)
+		return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &val}
+	}
+	contractData := func(contract xdr.ScAddress, key xdr.ScVal, durability xdr.ContractDataDurability) (xdr.LedgerKey, xdr.LedgerEntry) {
+		return getContractDataLedgerEntry(t, xdr.ContractDataEntry{
+			Contract:   contract,
+			Key:        key,
+			Durability: durability,
+			Val:        u32(0),
+		})
+	}
+	keyA1, entryA1 := contractData(contractA, u32(1), xdr.ContractDataDurabilityPersistent)
+	keyA2, entryA2 := contractData(contractA, u32(2), xdr.ContractDataDurabilityPersistent)
+	keyAX, entryAX := contractData(contractA, symbol("x"), xdr.ContractDataDurabilityPersistent)
+	keyAT, entryAT := contractData(contractA, u32(3), xdr.ContractDataDurabilityTemporary)
+	keyB1, entryB1 := contractData(contractB, u32(1), xdr.ContractDataDurabilityPersistent)
+
+	tx, err := NewReadWriter(db, 150, 15).NewTx(context.Background())
+	require.NoError(t, err)
+	writer := tx.LedgerEntryWriter()
+	for _, entry := range []xdr.LedgerEntry{entryA1, entryA2, entryAX, entryAT, entryB1} {
+		key, err := entry.LedgerKey()
+		require.NoError(t, err)
+		ttlKey, err := entryKeyToTTLEntryKey(key)
+		require.NoError(t, err)
+		require.NoError(t, writer.UpsertLedgerEntry(entry))
+		require.NoError(t, writer.UpsertLedgerEntry(getTTLLedgerEntry(ttlKey)))
+	}
+	require.NoError(t, tx.Commit(23))
+
+	reader := NewContractDataReader(db)
+	// keys of the selected entries
+	getContractData := func(request ContractDataRequest) []xdr.LedgerKey {
+		entries, _, err := reader.GetContractData(context.Background(), request)
+		require.NoError(t, err)
+		keys := make([]xdr.LedgerKey, len(entries))
+		for i, entry := range entries {
+			require.NotNil(t, entry.LiveUntilLedgerSeq)
+			assert.Equal(t, uint32(100), *entry.LiveUntilLedgerSeq)
+			keys[i] = entry.Key
+		}
+		return keys
+	}
+
+	// the entries are ordered by key type, so the u32 keys come before the symbol
+	persistentA := ContractDataRequest{Contract: contractA, Durability: xdr.ContractDataDurabilityPersistent, Limit: 2}
+	assert.Equal(t, []xdr.LedgerKey{keyA1, keyA2}, getContractData(persistentA))
+	persistentA.Cursor = &keyA2
+	assert.Equal(t, []xdr.LedgerKey{keyAX}, getContractData(persistentA))
+	persistentA.Cursor = &keyAX
+	assert.Empty(t, getContractData(persistentA))
+
+	symbolType := xdr.ScValTypeScvSymbol
+	assert.Equal(t, []xdr.LedgerKey{keyAX}, getContractData(ContractDataRequest{
+		Contract: contractA, Durability: xdr.ContractDataDurabilityPersistent, KeyType: &symbolType, Limit: 10,
+	}))
+	u32Type := xdr.ScValTypeScvU32
+	assert.Equal(t, []xdr.LedgerKey{keyA2}, getContractData(ContractDataRequest{
+		Contract: contractA, Durability: xdr.ContractDataDurabilityPersistent, KeyType: &u32Type, Cursor: &keyA1, Limit: 10,
+	}))
+	assert.Equal(t, []xdr.LedgerKey{keyAT}, getContractData(ContractDataRequest{
+		Contract: contractA, Durability: xdr.ContractDataDurabilityTemporary, Limit: 10,
+	}))
+	assert.Equal(t, []xdr.LedgerKey{keyB1}, getContractData(ContractDataRequest{
+		Contract: contractB, Durability: xdr.ContractDataDurabilityPersistent, Limit: 10,
+	}))
+
+	// removed entries are no longer indexed
+	tx, err = NewReadWriter(db, 150, 15).NewTx(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, tx.LedgerEntryWriter().DeleteLedgerEntry(keyA1))
+	require.NoError(t, tx.Commit(24))
+	_, latestLedger, err := reader.GetContractData(context.Background(), persistentA)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(24), latestLedger)
+	allPersistentA := ContractDataRequest{Contract: contractA, Durability: xdr.ContractDataDurabilityPersistent, Limit: 10}
+	expected := []xdr.LedgerKey{keyA2, keyAX}
+	assert.Equal(t, expected, getContractData(allPersistentA))
+
+	// the index is populated from the existing entries when it's created
+	_, err = db.ExecRaw(context.Background(), "DROP TABLE contract_data")
+	require.NoError(t, err)
+	_, err = db.ExecRaw(context.Background(), "DELETE FROM gorp_migrations WHERE id = '03_contract_data.sql'")
+	require.NoError(t, err)
+	reopened, err := OpenSQLiteDB(dbPath)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, reopened.Close())
+	}()
+	reader = NewContractDataReader(reopened)
+	assert.Equal(t, expected, getContractData(allPersistentA))
+	assert.Equal(t, []xdr.LedgerKey{keyAT}, getContractData(ContractDataRequest{
+		Contract: contractA, Durability: xdr.ContractDataDurabilityTemporary, Limit: 10,
+	}))
+}
@@ -0,0 +1,27 @@
+-- +migrate Up
+-- transactions submitted through sendTransaction, tracked until they are ingested or expire
+CREATE TABLE submissions (
+    hash TEXT NOT NULL PRIMARY KEY,
+    envelope TEXT NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    ledger INTEGER NOT NULL DEFAULT 0,
+    submitted_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    next_attempt_at INTEGER NOT NULL,
+    expires_at INTEGER NOT NULL
+);
+CREATE INDEX submissions_status_index ON submissions (status);
+
+-- status changes of the submissions
+CREATE TABLE submission_status_history (
+    hash TEXT NOT NULL,
+    status TEXT NOT NULL,
+    timestamp INTEGER NOT NULL,
+    error_result_xdr TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX submission_status_history_hash_index ON submission_status_history (hash);
+
+-- +migrate Down
+drop table submission_status_history;
+drop table submissions;
@@ -0,0 +1,21 @@
+-- +migrate Up
+-- index of the contract data ledger entries by contract address and durability.
+-- The key is the compressed ledger key, which is made of the entry type (1 byte),
+-- the contract address XDR (36 bytes for contracts, 40 for accounts), the key XDR
+-- and the durability (1 byte).
+CREATE TABLE contract_data (
+    key BLOB NOT NULL PRIMARY KEY,
+    contract_id BLOB NOT NULL,
+    durability INTEGER NOT NULL
+);
+CREATE INDEX contract_data_contract_index ON contract_data (contract_id, durability, key);
+
+INSERT INTO contract_data (key, contract_id, durability)
+SELECT key,
+       substr(CAST(key AS BLOB), 2, CASE WHEN substr(CAST(key AS BLOB), 2, 4) = x'00000000' THEN 40 ELSE 36 END),
+       CASE WHEN substr(CAST(key AS BLOB), -1) = x'00' THEN 0 ELSE 1 END
+FROM ledger_entries
+WHERE substr(CAST(key AS BLOB), 1, 1) = x'06';
+
+-- +migrate Down
+drop table contract_data;
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"github.com/stellar/go/xdr"
+)
+
+// LedgerEntryOverlayTx layers in-memory ledger entry changes over a read transaction,
+// without modifying the underlying ledger state. It's used to simulate transactions
+// against hypothetical ledger states.
+type LedgerEntryOverlayTx struct {
+	LedgerEntryReadTx
+	buffer *xdr.EncodingBuffer
+	// nil entries imply deletion
+	keyToEntry map[string]*xdr.LedgerEntry
+}
+
+// NewLedgerEntryOverlayTx creates an overlay without changes over the given read transaction
+func NewLedgerEntryOverlayTx(tx LedgerEntryReadTx) *LedgerEntryOverlayTx {
+	return &LedgerEntryOverlayTx{
+		LedgerEntryReadTx: tx,
+		buffer:            xdr.NewEncodingBuffer(),
+		keyToEntry:        make(map[string]*xdr.LedgerEntry),
+	}
+}
+
+func (o *LedgerEntryOverlayTx) UpsertLedgerEntry(entry xdr.LedgerEntry) error {
+	key, err := entry.LedgerKey()
+	if err != nil {
+		return err
+	}
+	encodedKey, err := encodeLedgerKey(o.buffer, key)
+	if err != nil {
+		return err
+	}
+	o.keyToEntry[encodedKey] = &entry
+	return nil
+}
+
+func (o *LedgerEntryOverlayTx) DeleteLedgerEntry(key xdr.LedgerKey) error {
+	encodedKey, err := encodeLedgerKey(o.buffer, key)
+	if err != nil {
+		return err
+	}
+	o.keyToEntry[encodedKey] = nil
+	return nil
+}
+
+// lookup returns the overlay entry of the key, and whether the overlay has changed it
+func (o *LedgerEntryOverlayTx) lookup(key xdr.LedgerKey) (*xdr.LedgerEntry, bool, error) {
+	encodedKey, err := encodeLedgerKey(o.buffer, key)
+	if err != nil {
+		return nil, false, err
+	}
+	entry, ok := o.keyToEntry[encodedKey]
+	return entry, ok, nil
+}
+
+// liveUntilLedgerSeq returns the live-until sequence of the key, as set by the overlay if changed,
+// or otherwise by the underlying transaction. For entries obtained from the underlying transaction,
+// the underlying sequence is provided.
+func (o *LedgerEntryOverlayTx) liveUntilLedgerSeq(key xdr.LedgerKey, fromOverlay bool, underlying *uint32) (*uint32, error) {
+	if !hasTTLKey(key) {
+		return nil, nil
+	}
+	ttlKey, err := entryKeyToTTLEntryKey(key)
+	if err != nil {
+		return nil, err
+	}
+	ttlEntry, changed, err := o.lookup(ttlKey)
+	if err != nil {
+		return nil, err
+	}
+	if !changed && !fromOverlay {
+		return underlying, nil
+	}
+	if !changed {
+		// the entry was upserted by the overlay but not its TTL, keep the current one (if any)
+		ttlEntries, err := o.LedgerEntryReadTx.GetLedgerEntries(ttlKey)
+		if err != nil || len(ttlEntries) == 0 {
+			return nil, err
+		}
+		ttlEntry = &ttlEntries[0].Entry
+	}
+	if ttlEntry == nil {
+		return nil, nil
+	}
+	liveUntilSeq := uint32(ttlEntry.Data.MustTtl().LiveUntilLedgerSeq)
+	return &liveUntilSeq, nil
+}
+
+func (o *LedgerEntryOverlayTx) GetLedgerEntries(keys ...xdr.LedgerKey) ([]LedgerKeyAndEntry, error) {
+	// the entries not changed by the overlay are obtained from the underlying transaction
+	var keysToQuery []xdr.LedgerKey
+	overlayEntries := make([]*xdr.LedgerEntry, len(keys))
+	changed := make([]bool, len(keys))
+	for i, key := range keys {
+		entry, ok, err := o.lookup(key)
+		if err != nil {
+			return nil, err
+		}
+		overlayEntries[i], changed[i] = entry, ok
+		if !ok {
+			keysToQuery = append(keysToQuery, key)
+		}
+	}
+	underlyingEntries := make(map[string]LedgerKeyAndEntry, len(keysToQuery))
+	if len(keysToQuery) > 0 {
+		entries, err := o.LedgerEntryReadTx.GetLedgerEntries(keysToQuery...)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			encodedKey, err := encodeLedgerKey(o.buffer, entry.Key)
+			if err != nil {
+				return nil, err
+			}
+			underlyingEntries[encodedKey] = entry
+		}
+	}
+
+	result := make([]LedgerKeyAndEntry, 0, len(keys))
+	for i, key := range keys {
+		var keyAndEntry LedgerKeyAndEntry
+		if changed[i] {
+			if overlayEntries[i] == nil {
+				continue
+			}
+			keyAndEntry = LedgerKeyAndEntry{Key: key, Entry: *overlayEntries[i]}
+		} else {
+			encodedKey, err := encodeLedgerKey(o.buffer, key)
+			if err != nil {
+				return nil, err
+			}
+			var ok bool
+			if keyAndEntry, ok = underlyingEntries[encodedKey]; !ok {
+				continue
+			}
+		}
+		liveUntilSeq, err := o.liveUntilLedgerSeq(key, changed[i], keyAndEntry.LiveUntilLedgerSeq)
+		if err != nil {
+			return nil, err
+		}
+		keyAndEntry.LiveUntilLedgerSeq = liveUntilSeq
+		result = append(result, keyAndEntry)
+	}
+	return result, nil
+}
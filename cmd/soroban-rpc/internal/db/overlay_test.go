@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+)
+
+func TestLedgerEntryOverlayTx(t *testing.T) {
+	db := NewTestDB(t)
+	tx, err := NewReadWriter(db, 150, 15).NewTx(context.Background())
+	require.NoError(t, err)
+	writer := tx.LedgerEntryWriter()
+
+	newData := func(key, val uint32) xdr.ContractDataEntry {
+		scKey, scVal := xdr.Uint32(key), xdr.Uint32(val)
+		return xdr.ContractDataEntry{
+			Contract: xdr.ScAddress{
+				Type:       xdr.ScAddressTypeScAddressTypeContract,
+				ContractId: &xdr.Hash{0xca, 0xfe},
+			},
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &scKey},
+			Durability: xdr.ContractDataDurabilityPersistent,
+			Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &scVal},
+		}
+	}
+	// keep is left untouched, update is upserted and remove is deleted by the overlay
+	keepKey, keepEntry := getContractDataLedgerEntry(t, newData(1, 10))
+	updateKey, updateEntry := getContractDataLedgerEntry(t, newData(2, 20))
+	removeKey, removeEntry := getContractDataLedgerEntry(t, newData(3, 30))
+	for _, keyAndEntry := range []LedgerKeyAndEntry{
+		{Key: keepKey, Entry: keepEntry},
+		{Key: updateKey, Entry: updateEntry},
+		{Key: removeKey, Entry: removeEntry},
+	} {
+		require.NoError(t, writer.UpsertLedgerEntry(keyAndEntry.Entry))
+		ttlKey, err := entryKeyToTTLEntryKey(keyAndEntry.Key)
+		require.NoError(t, err)
+		require.NoError(t, writer.UpsertLedgerEntry(getTTLLedgerEntry(ttlKey)))
+	}
+	require.NoError(t, tx.Commit(23))
+
+	readTx, err := NewLedgerEntryReader(db).NewTx(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, readTx.Done())
+	}()
+	overlay := NewLedgerEntryOverlayTx(readTx)
+
+	_, updatedEntry := getContractDataLedgerEntry(t, newData(2, 21))
+	require.NoError(t, overlay.UpsertLedgerEntry(updatedEntry))
+	require.NoError(t, overlay.DeleteLedgerEntry(removeKey))
+	createKey, createEntry := getContractDataLedgerEntry(t, newData(4, 40))
+	require.NoError(t, overlay.UpsertLedgerEntry(createEntry))
+	createTTLKey, err := entryKeyToTTLEntryKey(createKey)
+	require.NoError(t, err)
+	createTTLEntry := getTTLLedgerEntry(createTTLKey)
+	createTTLEntry.Data.Ttl.LiveUntilLedgerSeq = 200
+	require.NoError(t, overlay.UpsertLedgerEntry(createTTLEntry))
+
+	entries, err := overlay.GetLedgerEntries(keepKey, updateKey, removeKey, createKey)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, keepEntry, entries[0].Entry)
+	assert.Equal(t, updatedEntry, entries[1].Entry)
+	assert.Equal(t, createEntry, entries[2].Entry)
+	for i, liveUntilSeq := range []uint32{100, 100, 200} {
+		require.NotNil(t, entries[i].LiveUntilLedgerSeq)
+		assert.Equal(t, liveUntilSeq, *entries[i].LiveUntilLedgerSeq)
+	}
+
+	// the underlying ledger state is not modified
+	present, entry, _, err := GetLedgerEntry(readTx, updateKey)
+	require.NoError(t, err)
+	assert.True(t, present)
+	assert.Equal(t, updateEntry, entry)
+	present, _, _, err = GetLedgerEntry(readTx, removeKey)
+	require.NoError(t, err)
+	assert.True(t, present)
+	present, _, _, err = GetLedgerEntry(readTx, createKey)
+	require.NoError(t, err)
+	assert.False(t, present)
+}
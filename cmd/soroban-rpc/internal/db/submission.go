@@ -0,0 +1,172 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/support/db"
+)
+
+const (
+	submissionsTableName             = "submissions"
+	submissionStatusHistoryTableName = "submission_status_history"
+)
+
+// Submission is a transaction submitted through sendTransaction. Times are unix timestamps.
+type Submission struct {
+	// Hash is the hex-encoded transaction hash
+	Hash string `db:"hash"`
+	// Envelope is the base64-encoded TransactionEnvelope XDR, used to resubmit the transaction
+	Envelope string `db:"envelope"`
+	Status   string `db:"status"`
+	Attempts uint32 `db:"attempts"`
+	// Ledger is the ledger which included the transaction, if it was ingested
+	Ledger        uint32 `db:"ledger"`
+	SubmittedAt   int64  `db:"submitted_at"`
+	UpdatedAt     int64  `db:"updated_at"`
+	NextAttemptAt int64  `db:"next_attempt_at"`
+	// ExpiresAt is the time after which the transaction can no longer be included in a ledger
+	ExpiresAt int64 `db:"expires_at"`
+}
+
+// SubmissionStatusChange is an entry of the status history of a submission.
+type SubmissionStatusChange struct {
+	Status    string `db:"status"`
+	Timestamp int64  `db:"timestamp"`
+	// ErrorResultXDR is the TransactionResult XDR returned by stellar-core when rejecting the transaction
+	ErrorResultXDR string `db:"error_result_xdr"`
+}
+
+type SubmissionStore interface {
+	// InsertSubmission starts tracking a submission, with errorResultXDR as the error of its first status.
+	InsertSubmission(ctx context.Context, submission Submission, errorResultXDR string) error
+	// UpdateSubmission replaces the previous version of a submission by the updated one, adding its status
+	// to the history if it changed. It returns false (without updating it) if the submission was concurrently
+	// modified since previous was read.
+	UpdateSubmission(ctx context.Context, previous, updated Submission, errorResultXDR string) (bool, error)
+	// GetSubmission returns a submission and its status history, from oldest to newest.
+	GetSubmission(ctx context.Context, hash string) (Submission, []SubmissionStatusChange, bool, error)
+	// GetSubmissionsByStatus returns all the submissions in any of the given statuses.
+	GetSubmissionsByStatus(ctx context.Context, statuses []string) ([]Submission, error)
+	// TrimSubmissions removes the submissions in any of the given statuses which were last updated before the given time.
+	TrimSubmissions(ctx context.Context, statuses []string, updatedBefore int64) error
+}
+
+type submissionStore struct {
+	db *DB
+}
+
+func NewSubmissionStore(db *DB) SubmissionStore {
+	return submissionStore{db: db}
+}
+
+func (s submissionStore) inTx(ctx context.Context, f func(session db.SessionInterface) error) error {
+	session := s.db.Clone()
+	if err := session.Begin(ctx); err != nil {
+		return err
+	}
+	if err := f(session); err != nil {
+		_ = session.Rollback()
+		return err
+	}
+	return session.Commit()
+}
+
+func insertSubmissionStatus(ctx context.Context, session db.SessionInterface, submission Submission, errorResultXDR string) error {
+	_, err := session.Exec(ctx, sq.Insert(submissionStatusHistoryTableName).
+		Columns("hash", "status", "timestamp", "error_result_xdr").
+		Values(submission.Hash, submission.Status, submission.UpdatedAt, errorResultXDR))
+	return err
+}
+
+func (s submissionStore) InsertSubmission(ctx context.Context, submission Submission, errorResultXDR string) error {
+	return s.inTx(ctx, func(session db.SessionInterface) error {
+		_, err := session.Exec(ctx, sq.Insert(submissionsTableName).
+			Columns("hash", "envelope", "status", "attempts", "ledger", "submitted_at", "updated_at", "next_attempt_at", "expires_at").
+			Values(submission.Hash, submission.Envelope, submission.Status, submission.Attempts, submission.Ledger,
+				submission.SubmittedAt, submission.UpdatedAt, submission.NextAttemptAt, submission.ExpiresAt))
+		if err != nil {
+			return err
+		}
+		return insertSubmissionStatus(ctx, session, submission, errorResultXDR)
+	})
+}
+
+func (s submissionStore) UpdateSubmission(ctx context.Context, previous, updated Submission, errorResultXDR string) (bool, error) {
+	if previous.Hash != updated.Hash {
+		return false, fmt.Errorf("cannot update submission %s with submission %s", previous.Hash, updated.Hash)
+	}
+	var ok bool
+	err := s.inTx(ctx, func(session db.SessionInterface) error {
+		result, err := session.Exec(ctx, sq.Update(submissionsTableName).
+			SetMap(map[string]interface{}{
+				"status":          updated.Status,
+				"attempts":        updated.Attempts,
+				"ledger":          updated.Ledger,
+				"updated_at":      updated.UpdatedAt,
+				"next_attempt_at": updated.NextAttemptAt,
+				"expires_at":      updated.ExpiresAt,
+			}).
+			Where(sq.Eq{"hash": previous.Hash, "status": previous.Status, "attempts": previous.Attempts}))
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if ok = affected == 1; !ok || updated.Status == previous.Status {
+			return nil
+		}
+		return insertSubmissionStatus(ctx, session, updated, errorResultXDR)
+	})
+	return ok, err
+}
+
+func (s submissionStore) GetSubmission(ctx context.Context, hash string) (Submission, []SubmissionStatusChange, bool, error) {
+	var submissions []Submission
+	if err := s.db.Select(ctx, &submissions, sq.Select("*").From(submissionsTableName).Where(sq.Eq{"hash": hash})); err != nil {
+		return Submission{}, nil, false, err
+	}
+	if len(submissions) == 0 {
+		return Submission{}, nil, false, nil
+	}
+	var history []SubmissionStatusChange
+	sql := sq.Select("status", "timestamp", "error_result_xdr").
+		From(submissionStatusHistoryTableName).
+		Where(sq.Eq{"hash": hash}).
+		OrderBy("rowid asc")
+	if err := s.db.Select(ctx, &history, sql); err != nil {
+		return Submission{}, nil, false, err
+	}
+	return submissions[0], history, true, nil
+}
+
+func (s submissionStore) GetSubmissionsByStatus(ctx context.Context, statuses []string) ([]Submission, error) {
+	var submissions []Submission
+	sql := sq.Select("*").From(submissionsTableName).Where(sq.Eq{"status": statuses}).OrderBy("submitted_at asc")
+	if err := s.db.Select(ctx, &submissions, sql); err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+func (s submissionStore) TrimSubmissions(ctx context.Context, statuses []string, updatedBefore int64) error {
+	trimmed := sq.Select("hash").From(submissionsTableName).
+		Where(sq.Eq{"status": statuses}).
+		Where(sq.Lt{"updated_at": updatedBefore})
+	trimmedSQL, args, err := trimmed.ToSql()
+	if err != nil {
+		return err
+	}
+	return s.inTx(ctx, func(session db.SessionInterface) error {
+		_, err := session.Exec(ctx, sq.Delete(submissionStatusHistoryTableName).Where("hash IN ("+trimmedSQL+")", args...))
+		if err != nil {
+			return err
+		}
+		_, err = session.Exec(ctx, sq.Delete(submissionsTableName).Where(sq.Eq{"status": statuses}).Where(sq.Lt{"updated_at": updatedBefore}))
+		return err
+	})
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmissions(t *testing.T) {
+	ctx := context.Background()
+	store := NewSubmissionStore(NewTestDB(t))
+
+	_, _, ok, err := store.GetSubmission(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	pending := Submission{Hash: "a", Envelope: "AAAA", Status: "PENDING", Attempts: 1, SubmittedAt: 10, UpdatedAt: 10, ExpiresAt: 100}
+	retried := Submission{Hash: "b", Envelope: "BBBB", Status: "TRY_AGAIN_LATER", Attempts: 1, SubmittedAt: 11, UpdatedAt: 11, NextAttemptAt: 12, ExpiresAt: 100}
+	require.NoError(t, store.InsertSubmission(ctx, pending, ""))
+	require.NoError(t, store.InsertSubmission(ctx, retried, ""))
+	require.Error(t, store.InsertSubmission(ctx, pending, ""))
+
+	submissions, err := store.GetSubmissionsByStatus(ctx, []string{"TRY_AGAIN_LATER"})
+	require.NoError(t, err)
+	assert.Equal(t, []Submission{retried}, submissions)
+
+	// retrying without changing the status doesn't add it to the history
+	retriedAgain := retried
+	retriedAgain.Attempts, retriedAgain.UpdatedAt, retriedAgain.NextAttemptAt = 2, 12, 14
+	ok, err = store.UpdateSubmission(ctx, retried, retriedAgain, "")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	rejected := retriedAgain
+	rejected.Status, rejected.Attempts, rejected.UpdatedAt = "ERROR", 3, 14
+	ok, err = store.UpdateSubmission(ctx, retriedAgain, rejected, "error result")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// outdated versions are not updated
+	ok, err = store.UpdateSubmission(ctx, retried, rejected, "")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	submission, history, ok, err := store.GetSubmission(ctx, "b")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, rejected, submission)
+	assert.Equal(t, []SubmissionStatusChange{
+		{Status: "TRY_AGAIN_LATER", Timestamp: 11},
+		{Status: "ERROR", Timestamp: 14, ErrorResultXDR: "error result"},
+	}, history)
+
+	require.NoError(t, store.TrimSubmissions(ctx, []string{"ERROR"}, 15))
+	_, _, ok, err = store.GetSubmission(ctx, "b")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, history, ok, err = store.GetSubmission(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Len(t, history, 1)
+}
@@ -42,6 +42,9 @@ type MemoryStore struct {
 	eventsByLedger       *ledgerbucketwindow.LedgerBucketWindow[[]event]
 	eventsDurationMetric *prometheus.SummaryVec
 	eventCountMetric     prometheus.Summary
+	// subscriptionsLock protects the subscriptions, it's never acquired while holding lock
+	subscriptionsLock sync.Mutex
+	subscriptions     map[*Subscription]struct{}
 }
 
 // NewMemoryStore creates a new MemoryStore.
@@ -89,12 +92,15 @@ type Range struct {
 	// ClampEnd indicates whether End should be clamped down
 	// to the latest ledger available if End is too high.
 	ClampEnd bool
+	// Descending indicates whether the events are scanned from End to Start.
+	Descending bool
 }
 
 type ScanFunction func(xdr.DiagnosticEvent, Cursor, int64, *xdr.Hash) bool
 
 // Scan applies f on all the events occurring in the given range.
-// The events are processed in sorted ascending Cursor order.
+// The events are processed in sorted ascending Cursor order, or descending
+// Cursor order if the range is descending.
 // If f returns false, the scan terminates early (f will not be applied on
 // remaining events in the range). Note that a read lock is held for the
 // entire duration of the Scan function so f should be written in a way
@@ -107,6 +113,12 @@ func (m *MemoryStore) Scan(eventRange Range, f ScanFunction) (uint32, error) {
 	if err := m.validateRange(&eventRange); err != nil {
 		return 0, err
 	}
+	if eventRange.Descending {
+		lastLedgerInWindow, err := m.scanDescending(eventRange, f)
+		m.eventsDurationMetric.With(prometheus.Labels{"operation": "scan_descending"}).
+			Observe(time.Since(startTime).Seconds())
+		return lastLedgerInWindow, err
+	}
 
 	firstLedgerInRange := eventRange.Start.Ledger
 	firstLedgerInWindow := m.eventsByLedger.Get(0).LedgerSeq
@@ -139,6 +151,38 @@ func (m *MemoryStore) Scan(eventRange Range, f ScanFunction) (uint32, error) {
 	return lastLedgerInWindow, nil
 }
 
+// scanDescending applies f on the events of the range from the newest to the oldest.
+// It must be called with the read lock, on a validated range.
+func (m *MemoryStore) scanDescending(eventRange Range, f ScanFunction) (uint32, error) {
+	firstLedgerInWindow := m.eventsByLedger.Get(0).LedgerSeq
+	lastLedgerInWindow := firstLedgerInWindow + (m.eventsByLedger.Len() - 1)
+	// End is exclusive, so its ledger may be right after the window
+	lastLedgerInRange := min(eventRange.End.Ledger, lastLedgerInWindow)
+	for i := int64(lastLedgerInRange - firstLedgerInWindow); i >= int64(eventRange.Start.Ledger-firstLedgerInWindow); i-- {
+		bucket := m.eventsByLedger.Get(uint32(i))
+		events := bucket.BucketContent
+		if bucket.LedgerSeq == eventRange.Start.Ledger {
+			events = seek(events, eventRange.Start)
+		}
+		if bucket.LedgerSeq == eventRange.End.Ledger {
+			// drop the events at or after the end of the range
+			events = events[:len(events)-len(seek(events, eventRange.End))]
+		}
+		timestamp := bucket.LedgerCloseTimestamp
+		for j := len(events) - 1; j >= 0; j-- {
+			var diagnosticEvent xdr.DiagnosticEvent
+			err := xdr.SafeUnmarshal(events[j].diagnosticEventXDR, &diagnosticEvent)
+			if err != nil {
+				return 0, err
+			}
+			if !f(diagnosticEvent, events[j].cursor(bucket.LedgerSeq), timestamp, events[j].txHash) {
+				return lastLedgerInWindow, nil
+			}
+		}
+	}
+	return lastLedgerInWindow, nil
+}
+
 // validateRange checks if the range falls within the bounds
 // of the events in the memory store.
 // validateRange should be called with the read lock.
@@ -167,6 +211,10 @@ func (m *MemoryStore) validateRange(eventRange *Range) error {
 		}
 	}
 
+	if eventRange.Descending && eventRange.ClampStart && eventRange.Start == eventRange.End {
+		// paging backwards from the oldest event, there are no events left
+		return nil
+	}
 	if eventRange.Start.Cmp(eventRange.End) >= 0 {
 		return errors.New("start is not before end")
 	}
@@ -188,6 +236,8 @@ type EventInfoRaw struct {
 	Event          xdr.DiagnosticEvent
 	Cursor         Cursor
 	LedgerClosedAt string
+	TxHash         *xdr.Hash
+	OpIndex        uint32 // Soroban transactions have a single operation, so this is always 0 for now
 }
 
 // IngestEvents adds new events from the given ledger into the store.
@@ -211,12 +261,27 @@ func (m *MemoryStore) IngestEvents(ledgerCloseMeta xdr.LedgerCloseMeta) error {
 		return err
 	}
 	m.lock.Unlock()
+	// events are published after they are stored, so that subscribers can
+	// catch up with Scan without missing any ledger
+	if err = m.publish(bucket.LedgerSeq, bucket.LedgerCloseTimestamp, events); err != nil {
+		return err
+	}
 	m.eventsDurationMetric.With(prometheus.Labels{"operation": "ingest"}).
 		Observe(time.Since(startTime).Seconds())
 	m.eventCountMetric.Observe(float64(len(events)))
 	return nil
 }
 
+// GetLatestLedger returns the latest ledger in the store, or 0 if the store is empty.
+func (m *MemoryStore) GetLatestLedger() uint32 {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.eventsByLedger.Len() == 0 {
+		return 0
+	}
+	return m.eventsByLedger.Get(m.eventsByLedger.Len() - 1).LedgerSeq
+}
+
 func (m *MemoryStore) GetLastLedgerEvents() (eventsRes []EventInfoRaw) {
 	if m.eventsByLedger.Len() > 0 {
 		bucket := m.eventsByLedger.Get(m.eventsByLedger.Len() - 1)
@@ -229,6 +294,8 @@ func (m *MemoryStore) GetLastLedgerEvents() (eventsRes []EventInfoRaw) {
 				Event:          diagnosticEvent,
 				Cursor:         Cursor{Ledger: bucket.LedgerSeq, Tx: ev.txIndex, Op: 0, Event: ev.eventIndex},
 				LedgerClosedAt: time.Unix(bucket.LedgerCloseTimestamp, 0).UTC().Format(time.RFC3339),
+				TxHash:         ev.txHash,
+				OpIndex:        0,
 			})
 		}
 	}
@@ -386,6 +386,39 @@ func TestScan(t *testing.T) {
 				require.Equal(t, uint32(8), latest)
 				eventsAreEqual(t, []event{testCase.expected[0]}, events)
 			}
+
+			// the same range scanned in descending order yields the events in reverse
+			descending := input
+			descending.Descending = true
+			events = nil
+			iterateAll = true
+			latest, err = m.Scan(descending, f)
+			require.NoError(t, err)
+			require.Equal(t, uint32(8), latest)
+			reversed := make([]event, 0, len(testCase.expected))
+			for i := len(testCase.expected) - 1; i >= 0; i-- {
+				reversed = append(reversed, testCase.expected[i])
+			}
+			eventsAreEqual(t, reversed, events)
 		}
 	}
 }
+
+func TestScanDescendingFromOldestEvent(t *testing.T) {
+	m := createStore(t)
+	eventRange := Range{
+		Start:      MinCursor,
+		ClampStart: true,
+		End:        Cursor{Ledger: 5},
+		ClampEnd:   true,
+		Descending: true,
+	}
+	called := false
+	latest, err := m.Scan(eventRange, func(xdr.DiagnosticEvent, Cursor, int64, *xdr.Hash) bool {
+		called = true
+		return true
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint32(8), latest)
+	require.False(t, called)
+}
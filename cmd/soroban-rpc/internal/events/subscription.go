@@ -0,0 +1,111 @@
+package events
+
+import (
+	"errors"
+	"time"
+
+	"github.com/stellar/go/xdr"
+)
+
+// ErrSubscriptionOverflow is reported when a subscriber doesn't keep up with the ingested events
+var ErrSubscriptionOverflow = errors.New("subscription buffer is full")
+
+// Subscription receives the events of the ledgers ingested after it was created.
+// Events are delivered in ascending Cursor order.
+type Subscription struct {
+	store  *MemoryStore
+	events chan EventInfoRaw
+	// err is protected by the subscriptions lock of the store
+	err error
+}
+
+// Subscribe creates a subscription to the events of newly ingested ledgers,
+// buffering up to bufferSize events. If the buffer fills up, the subscription is
+// closed and Err returns ErrSubscriptionOverflow. The subscription must be closed
+// once it's no longer used.
+func (m *MemoryStore) Subscribe(bufferSize uint) *Subscription {
+	subscription := &Subscription{
+		store:  m,
+		events: make(chan EventInfoRaw, bufferSize),
+	}
+	m.subscriptionsLock.Lock()
+	defer m.subscriptionsLock.Unlock()
+	if m.subscriptions == nil {
+		m.subscriptions = make(map[*Subscription]struct{})
+	}
+	m.subscriptions[subscription] = struct{}{}
+	return subscription
+}
+
+// Events returns the channel the events are delivered on. It's closed
+// when the subscription is closed.
+func (s *Subscription) Events() <-chan EventInfoRaw {
+	return s.events
+}
+
+// Err returns the reason why the subscription was closed by the store, if any.
+func (s *Subscription) Err() error {
+	s.store.subscriptionsLock.Lock()
+	defer s.store.subscriptionsLock.Unlock()
+	return s.err
+}
+
+// Close stops the delivery of events.
+func (s *Subscription) Close() {
+	s.store.subscriptionsLock.Lock()
+	defer s.store.subscriptionsLock.Unlock()
+	s.closeLocked(nil)
+}
+
+// closeLocked must be called with the subscriptions lock held
+func (s *Subscription) closeLocked(err error) {
+	if _, ok := s.store.subscriptions[s]; !ok {
+		return
+	}
+	delete(s.store.subscriptions, s)
+	s.err = err
+	close(s.events)
+}
+
+// publish delivers the events of an ingested ledger to the subscriptions,
+// closing the ones which are full instead of blocking ingestion.
+func (m *MemoryStore) publish(ledgerSeq uint32, ledgerCloseTimestamp int64, events []event) error {
+	m.subscriptionsLock.Lock()
+	defer m.subscriptionsLock.Unlock()
+	if len(m.subscriptions) == 0 || len(events) == 0 {
+		return nil
+	}
+	decoded, err := decodeEvents(ledgerSeq, ledgerCloseTimestamp, events)
+	if err != nil {
+		return err
+	}
+	for subscription := range m.subscriptions {
+	delivery:
+		for _, ev := range decoded {
+			select {
+			case subscription.events <- ev:
+			default:
+				subscription.closeLocked(ErrSubscriptionOverflow)
+				break delivery
+			}
+		}
+	}
+	return nil
+}
+
+func decodeEvents(ledgerSeq uint32, ledgerCloseTimestamp int64, events []event) ([]EventInfoRaw, error) {
+	ledgerClosedAt := time.Unix(ledgerCloseTimestamp, 0).UTC().Format(time.RFC3339)
+	decoded := make([]EventInfoRaw, 0, len(events))
+	for _, ev := range events {
+		info := EventInfoRaw{
+			Cursor:         ev.cursor(ledgerSeq),
+			LedgerClosedAt: ledgerClosedAt,
+			TxHash:         ev.txHash,
+		}
+		if err := xdr.SafeUnmarshal(ev.diagnosticEventXDR, &info.Event); err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, info)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,44 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscription(t *testing.T) {
+	m := createStore(t)
+	subscription := m.Subscribe(3)
+
+	// ledgers without events are not published
+	require.NoError(t, m.publish(9, ledgerCloseTime(9), nil))
+	require.NoError(t, m.publish(9, ledgerCloseTime(9), ledger7Events))
+	event := <-subscription.Events()
+	require.Equal(t, Cursor{Ledger: 9, Tx: 1, Event: 0}, event.Cursor)
+	require.Equal(t, xdr.Uint32(500), *event.Event.Event.Body.V0.Data.U32)
+	require.NoError(t, subscription.Err())
+
+	// the subscription is closed when its buffer is full, after delivering the buffered events
+	require.NoError(t, m.publish(10, ledgerCloseTime(10), ledger8Events))
+	var received []Cursor
+	for event := range subscription.Events() {
+		received = append(received, event.Cursor)
+	}
+	require.Equal(t, []Cursor{
+		{Ledger: 10, Tx: 1, Event: 0},
+		{Ledger: 10, Tx: 2, Event: 0},
+		{Ledger: 10, Tx: 2, Event: 1},
+	}, received)
+	require.ErrorIs(t, subscription.Err(), ErrSubscriptionOverflow)
+	require.Empty(t, m.subscriptions)
+
+	// closing stops the delivery
+	subscription = m.Subscribe(10)
+	subscription.Close()
+	subscription.Close()
+	require.NoError(t, m.publish(11, ledgerCloseTime(11), ledger7Events))
+	_, ok := <-subscription.Events()
+	require.False(t, ok)
+	require.NoError(t, subscription.Err())
+}
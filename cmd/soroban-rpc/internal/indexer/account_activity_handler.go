@@ -0,0 +1,169 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model"
+)
+
+type GetAccountActivityRequest struct {
+	Address string `json:"address"`
+	Cursor  string `json:"cursor,omitempty"`
+	Limit   uint   `json:"limit,omitempty"`
+}
+
+// AccountActivity is a transaction, event or token operation the address took part in.
+// Only the record matching the activity type is set.
+type AccountActivity struct {
+	Type           string                `json:"type"`
+	PagingToken    string                `json:"pagingToken"`
+	Ledger         uint32                `json:"ledger"`
+	TxHash         string                `json:"txHash"`
+	Transaction    *model.Transaction    `json:"transaction,omitempty"`
+	Event          *model.Event          `json:"event,omitempty"`
+	TokenOperation *model.TokenOperation `json:"tokenOperation,omitempty"`
+}
+
+type GetAccountActivityResponse struct {
+	Activities []AccountActivity `json:"activities"`
+	// Cursor resumes the query after the last returned activity
+	Cursor string `json:"cursor"`
+}
+
+// activityCursor is formatted as <paging token>-<rank>
+func activityCursor(participant model.Participant) string {
+	return fmt.Sprintf("%s-%d", participant.PagingToken, participant.Rank)
+}
+
+func parseActivityCursor(cursor string) (string, int16, error) {
+	i := strings.LastIndex(cursor, "-")
+	if i < 0 {
+		return "", 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	rank, err := strconv.ParseInt(cursor[i+1:], 10, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid cursor %q: %v", cursor, err)
+	}
+	return cursor[:i], int16(rank), nil
+}
+
+// GetAccountActivity returns the transactions, events and token operations an address took part in, in ledger order.
+func (s *Service) GetAccountActivity(ctx context.Context, address string, cursor string, limit uint) (GetAccountActivityResponse, error) {
+	var pagingToken string
+	var rank int16
+	if cursor != "" {
+		var err error
+		if pagingToken, rank, err = parseActivityCursor(cursor); err != nil {
+			return GetAccountActivityResponse{}, err
+		}
+	}
+	// the queries are canceled along with the request
+	db := s.indexerDB.WithContext(ctx)
+	participants, err := model.GetParticipants(db, address, pagingToken, rank, int(limit))
+	if err != nil {
+		return GetAccountActivityResponse{}, err
+	}
+
+	ids := make(map[string][]string)
+	for _, participant := range participants {
+		ids[participant.ActivityType] = append(ids[participant.ActivityType], participant.ActivityID)
+	}
+	transactions := make(map[string]*model.Transaction)
+	if len(ids[model.ActivityTransaction]) > 0 {
+		var found []model.Transaction
+		if err := db.Where("id IN ?", ids[model.ActivityTransaction]).Find(&found).Error; err != nil {
+			return GetAccountActivityResponse{}, err
+		}
+		for i := range found {
+			transactions[found[i].ID] = &found[i]
+		}
+	}
+	events := make(map[string]*model.Event)
+	if len(ids[model.ActivityEvent]) > 0 {
+		var found []model.Event
+		if err := db.Where("id IN ?", ids[model.ActivityEvent]).Find(&found).Error; err != nil {
+			return GetAccountActivityResponse{}, err
+		}
+		for i := range found {
+			events[found[i].ID] = &found[i]
+		}
+	}
+	tokenOps := make(map[string]*model.TokenOperation)
+	if len(ids[model.ActivityTokenOperation]) > 0 {
+		var found []model.TokenOperation
+		if err := db.Where("id IN ?", ids[model.ActivityTokenOperation]).Find(&found).Error; err != nil {
+			return GetAccountActivityResponse{}, err
+		}
+		for i := range found {
+			tokenOps[found[i].ID] = &found[i]
+		}
+	}
+
+	response := GetAccountActivityResponse{
+		Activities: make([]AccountActivity, 0, len(participants)),
+		Cursor:     cursor,
+	}
+	for _, participant := range participants {
+		activity := AccountActivity{
+			Type:        participant.ActivityType,
+			PagingToken: participant.PagingToken,
+			Ledger:      participant.Ledger,
+			TxHash:      participant.TxHash,
+		}
+		switch participant.ActivityType {
+		case model.ActivityTransaction:
+			activity.Transaction = transactions[participant.ActivityID]
+		case model.ActivityEvent:
+			activity.Event = events[participant.ActivityID]
+		case model.ActivityTokenOperation:
+			activity.TokenOperation = tokenOps[participant.ActivityID]
+		}
+		response.Activities = append(response.Activities, activity)
+		response.Cursor = activityCursor(participant)
+	}
+	return response, nil
+}
+
+// NewGetAccountActivityHandler returns a json rpc handler to fetch the activity of an account or contract
+func NewGetAccountActivityHandler(service *Service, maxLimit, defaultLimit uint) jrpc2.Handler {
+	return handler.New(func(ctx context.Context, request GetAccountActivityRequest) (GetAccountActivityResponse, error) {
+		if !isAddress(request.Address) {
+			return GetAccountActivityResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: fmt.Sprintf("invalid address %q", request.Address),
+			}
+		}
+		limit := defaultLimit
+		if request.Limit > 0 {
+			if request.Limit > maxLimit {
+				return GetAccountActivityResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InvalidParams,
+					Message: fmt.Sprintf("limit must not exceed %d", maxLimit),
+				}
+			}
+			limit = request.Limit
+		}
+		if request.Cursor != "" {
+			if _, _, err := parseActivityCursor(request.Cursor); err != nil {
+				return GetAccountActivityResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InvalidParams,
+					Message: err.Error(),
+				}
+			}
+		}
+		response, err := service.GetAccountActivity(ctx, request.Address, request.Cursor, limit)
+		if err != nil {
+			return GetAccountActivityResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		return response, nil
+	})
+}
@@ -0,0 +1,184 @@
+package indexer
+
+import (
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model"
+)
+
+const (
+	instructionsIncrement = 10000
+	dataSizeFeeIncrement  = 1024
+	txBaseResultSize      = 300
+	minimumWriteFee1Kb    = 1000
+)
+
+// SorobanFeeConfig holds the network fee settings needed to break down the resource fee of Soroban transactions.
+// Protocol 20 transaction meta doesn't report the breakdown, so it is computed the same way core does.
+type SorobanFeeConfig struct {
+	FeeRatePerInstructionsIncrement int64 `json:"fee_rate_per_instructions_increment"`
+	FeeReadLedgerEntry              int64 `json:"fee_read_ledger_entry"`
+	FeeWriteLedgerEntry             int64 `json:"fee_write_ledger_entry"`
+	FeeRead1Kb                      int64 `json:"fee_read_1kb"`
+	FeeWrite1Kb                     int64 `json:"fee_write_1kb"` // derived from the average bucket list size
+	FeeHistorical1Kb                int64 `json:"fee_historical_1kb"`
+	FeeTxSize1Kb                    int64 `json:"fee_tx_size_1kb"`
+	FeeContractEvents1Kb            int64 `json:"fee_contract_events_1kb"`
+}
+
+// NewSorobanFeeConfig builds the fee config from the network config setting entries.
+func NewSorobanFeeConfig(
+	compute xdr.ConfigSettingContractComputeV0,
+	ledgerCost xdr.ConfigSettingContractLedgerCostV0,
+	historical xdr.ConfigSettingContractHistoricalDataV0,
+	events xdr.ConfigSettingContractEventsV0,
+	bandwidth xdr.ConfigSettingContractBandwidthV0,
+	bucketListSizeWindow []xdr.Uint64,
+) SorobanFeeConfig {
+	var averageBucketListSize int64
+	if len(bucketListSizeWindow) > 0 {
+		var total uint64
+		for _, size := range bucketListSizeWindow {
+			total += uint64(size)
+		}
+		averageBucketListSize = int64(total / uint64(len(bucketListSizeWindow)))
+	}
+	return SorobanFeeConfig{
+		FeeRatePerInstructionsIncrement: int64(compute.FeeRatePerInstructionsIncrement),
+		FeeReadLedgerEntry:              int64(ledgerCost.FeeReadLedgerEntry),
+		FeeWriteLedgerEntry:             int64(ledgerCost.FeeWriteLedgerEntry),
+		FeeRead1Kb:                      int64(ledgerCost.FeeRead1Kb),
+		FeeWrite1Kb:                     computeWriteFee1Kb(averageBucketListSize, ledgerCost),
+		FeeHistorical1Kb:                int64(historical.FeeHistorical1Kb),
+		FeeTxSize1Kb:                    int64(bandwidth.FeeTxSize1Kb),
+		FeeContractEvents1Kb:            int64(events.FeeContractEvents1Kb),
+	}
+}
+
+func computeWriteFee1Kb(bucketListSize int64, ledgerCost xdr.ConfigSettingContractLedgerCostV0) int64 {
+	low := int64(ledgerCost.WriteFee1KbBucketListLow)
+	high := int64(ledgerCost.WriteFee1KbBucketListHigh)
+	target := int64(ledgerCost.BucketListTargetSizeBytes)
+	multiplier := high - low
+	if multiplier < 0 {
+		multiplier = 0
+	}
+	var fee int64
+	if target <= 0 {
+		fee = high
+	} else if bucketListSize < target {
+		fee = low + divCeil(multiplier*bucketListSize, target)
+	} else {
+		growth := int64(ledgerCost.BucketListWriteFeeGrowthFactor)
+		fee = high + divCeil(multiplier*(bucketListSize-target)*growth, target)
+	}
+	if fee < minimumWriteFee1Kb {
+		return minimumWriteFee1Kb
+	}
+	return fee
+}
+
+// NonRefundableFee computes the part of the resource fee charged regardless of execution.
+func (c SorobanFeeConfig) NonRefundableFee(resources xdr.SorobanResources, txSize int64) int64 {
+	readEntries := int64(len(resources.Footprint.ReadOnly) + len(resources.Footprint.ReadWrite))
+	writeEntries := int64(len(resources.Footprint.ReadWrite))
+	return divCeil(int64(resources.Instructions)*c.FeeRatePerInstructionsIncrement, instructionsIncrement) +
+		readEntries*c.FeeReadLedgerEntry +
+		writeEntries*c.FeeWriteLedgerEntry +
+		divCeil(int64(resources.ReadBytes)*c.FeeRead1Kb, dataSizeFeeIncrement) +
+		divCeil(int64(resources.WriteBytes)*c.FeeWrite1Kb, dataSizeFeeIncrement) +
+		divCeil((txSize+txBaseResultSize)*c.FeeHistorical1Kb, dataSizeFeeIncrement) +
+		divCeil(txSize*c.FeeTxSize1Kb, dataSizeFeeIncrement)
+}
+
+// EventsFee computes the refundable fee charged for the contract events and the return value.
+func (c SorobanFeeConfig) EventsFee(eventsAndReturnValueSize int64) int64 {
+	return divCeil(eventsAndReturnValueSize*c.FeeContractEvents1Kb, dataSizeFeeIncrement)
+}
+
+func divCeil(a, b int64) int64 {
+	if a <= 0 {
+		return 0
+	}
+	return (a + b - 1) / b
+}
+
+func setSorobanFees(t *model.Transaction, envelope xdr.TransactionEnvelope, meta xdr.TransactionMeta, feeConfig *SorobanFeeConfig) {
+	sorobanData, ok := getSorobanData(envelope)
+	if !ok {
+		return
+	}
+	resourceFee := int64(sorobanData.ResourceFee)
+	t.ResourceFee = &resourceFee
+
+	v3, ok := meta.GetV3()
+	if !ok {
+		return
+	}
+	refund := getFeeRefund(envelope, v3.TxChangesAfter)
+	t.ResourceFeeRefund = &refund
+	if feeConfig == nil {
+		return
+	}
+
+	// the resource fee is computed over the inner transaction of fee bumps
+	innerEnvelope := envelope
+	if feeBump, ok := envelope.GetFeeBump(); ok {
+		innerEnvelope = xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: feeBump.Tx.InnerTx.V1}
+	}
+	txSize, err := xdrSize(innerEnvelope)
+	if err != nil {
+		return
+	}
+	nonRefundable := feeConfig.NonRefundableFee(sorobanData.Resources, txSize)
+	refundable := max(resourceFee-refund-nonRefundable, 0)
+
+	var eventsSize int64
+	if v3.SorobanMeta != nil {
+		for _, event := range v3.SorobanMeta.Events {
+			size, err := xdrSize(event)
+			if err != nil {
+				return
+			}
+			eventsSize += size
+		}
+		size, err := xdrSize(v3.SorobanMeta.ReturnValue)
+		if err != nil {
+			return
+		}
+		eventsSize += size
+	}
+	rent := max(refundable-feeConfig.EventsFee(eventsSize), 0)
+
+	t.NonRefundableResourceFee = &nonRefundable
+	t.RefundableResourceFee = &refundable
+	t.RentFee = &rent
+}
+
+// getFeeRefund sums the balance increases of the fee source account after execution
+func getFeeRefund(envelope xdr.TransactionEnvelope, txChangesAfter xdr.LedgerEntryChanges) int64 {
+	feeSource := envelope.SourceAccount()
+	if envelope.IsFeeBump() {
+		feeSource = envelope.FeeBumpAccount()
+	}
+	feeSourceID := feeSource.ToAccountId()
+
+	var refund int64
+	for _, change := range ingest.GetChangesFromLedgerEntryChanges(txChangesAfter) {
+		if change.Type != xdr.LedgerEntryTypeAccount || change.Pre == nil || change.Post == nil {
+			continue
+		}
+		pre := change.Pre.Data.MustAccount()
+		post := change.Post.Data.MustAccount()
+		if pre.AccountId.Equals(feeSourceID) && post.Balance > pre.Balance {
+			refund += int64(post.Balance - pre.Balance)
+		}
+	}
+	return refund
+}
+
+func xdrSize(v interface{ MarshalBinary() ([]byte, error) }) (int64, error) {
+	bin, err := v.MarshalBinary()
+	return int64(len(bin)), err
+}
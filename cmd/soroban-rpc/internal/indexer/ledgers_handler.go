@@ -0,0 +1,44 @@
+package indexer
+
+import (
+	"context"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model"
+)
+
+type GetIndexedLedgersRequest struct {
+	StartLedger uint32 `json:"startLedger"`
+	EndLedger   uint32 `json:"endLedger"`
+}
+
+type GetIndexedLedgersResponse struct {
+	// Ledgers are the indexed ledgers of the range in ascending order, missing sequences are gaps in the ingestion
+	Ledgers []model.Ledger `json:"ledgers"`
+}
+
+// NewGetIndexedLedgersHandler returns a json rpc handler to fetch the ledger headers indexed in Postgres
+// within a range. Unlike getLedgers, it isn't limited to the ledgers retained in the local DB.
+func NewGetIndexedLedgersHandler(service *Service) jrpc2.Handler {
+	return handler.New(func(ctx context.Context, request GetIndexedLedgersRequest) (GetIndexedLedgersResponse, error) {
+		if err := validateLedgerRange(request.StartLedger, request.EndLedger); err != nil {
+			return GetIndexedLedgersResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: err.Error(),
+			}
+		}
+		ledgers, err := service.GetLedgers(ctx, request.StartLedger, request.EndLedger)
+		if err != nil {
+			return GetIndexedLedgersResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		if ledgers == nil {
+			ledgers = []model.Ledger{}
+		}
+		return GetIndexedLedgersResponse{Ledgers: ledgers}, nil
+	})
+}
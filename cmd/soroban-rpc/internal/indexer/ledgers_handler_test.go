@@ -0,0 +1,72 @@
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	_ "github.com/mattn/go-sqlite3"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestGetIndexedLedgersHandler(t *testing.T) {
+	// the queries are simple enough to run in SQLite through the postgres dialect
+	sqlDB, err := sql.Open("sqlite3", path.Join(t.TempDir(), "indexer.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	_, err = sqlDB.Exec(`CREATE TABLE ledgers (
+		sequence INTEGER PRIMARY KEY, hash TEXT, previous_hash TEXT, close_time DATETIME, protocol_version INTEGER,
+		base_fee INTEGER, base_reserve INTEGER, max_tx_set_size INTEGER, transaction_count INTEGER,
+		successful_transaction_count INTEGER, failed_transaction_count INTEGER, operation_count INTEGER,
+		soroban_fee_config TEXT, created_at DATETIME, updated_at DATETIME
+	);
+	INSERT INTO ledgers (sequence, hash, transaction_count) VALUES (10, 'a', 1), (11, 'b', 2), (13, 'c', 3);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexerDB, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	getLedgers := func(startLedger, endLedger uint32) (GetIndexedLedgersResponse, error) {
+		handler := NewGetIndexedLedgersHandler(&Service{indexerDB: indexerDB})
+		requests, err := jrpc2.ParseRequests([]byte(fmt.Sprintf(
+			`{"jsonrpc":"2.0","id":1,"method":"getIndexedLedgers","params":{"startLedger":%d,"endLedger":%d}}`,
+			startLedger, endLedger,
+		)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := handler(context.Background(), requests[0].ToRequest())
+		if err != nil {
+			return GetIndexedLedgersResponse{}, err
+		}
+		return result.(GetIndexedLedgersResponse), nil
+	}
+
+	response, err := getLedgers(11, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Ledgers) != 2 || response.Ledgers[0].Sequence != 11 || response.Ledgers[1].Sequence != 13 ||
+		response.Ledgers[1].TransactionCount != 3 {
+		t.Fatalf("unexpected ledgers %+v", response.Ledgers)
+	}
+	response, err = getLedgers(20, 30)
+	if err != nil || response.Ledgers == nil || len(response.Ledgers) != 0 {
+		t.Fatalf("unexpected ledgers %+v (%v)", response.Ledgers, err)
+	}
+
+	for _, invalid := range [][2]uint32{{12, 11}, {1, maxLedgersRange + 1}} {
+		_, err := getLedgers(invalid[0], invalid[1])
+		if err == nil || err.(*jrpc2.Error).Code != jrpc2.InvalidParams {
+			t.Fatalf("expected invalid params for range %v, got %v", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"encoding/json"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	ContractExecutableWasm         = "wasm"
+	ContractExecutableStellarAsset = "stellar_asset"
+)
+
+type Contract struct {
+	ContractID     string  `gorm:"column:contract_id;type:varchar(56);primaryKey"`
+	ExecutableType string  `gorm:"column:executable_type;type:varchar(16)"` // wasm or stellar_asset
+	WasmHash       *string `gorm:"column:wasm_hash;type:varchar(64);index"` // hex encoded, only set for wasm contracts
+	Asset          *string `gorm:"column:asset"`                            // canonical asset, only set for stellar asset contracts
+
+	// Creation info, only known when the CreateContract host function was seen by the indexer
+	Deployer      *string `gorm:"column:deployer;index"`
+	Salt          *string `gorm:"column:salt;type:varchar(64)"` // hex encoded
+	CreatedLedger *uint32 `gorm:"column:created_ledger"`
+	CreatedTxHash *string `gorm:"column:created_tx_hash;type:varchar(64)"`
+
+	LastModifiedLedger uint32 `gorm:"column:last_modified_ledger"`
+	util.Ts
+}
+
+type ContractUpgrade struct {
+	TxHash      string  `gorm:"column:tx_hash;type:varchar(64);primaryKey"`
+	ContractID  string  `gorm:"column:contract_id;type:varchar(56);primaryKey;index"`
+	Ledger      uint32  `gorm:"column:ledger"`
+	OldWasmHash *string `gorm:"column:old_wasm_hash;type:varchar(64)"`
+	NewWasmHash *string `gorm:"column:new_wasm_hash;type:varchar(64)"`
+	util.Ts
+}
+
+func UpsertContract(db *gorm.DB, contract *Contract) error {
+	// Upgrades carry no creation info, so existing creation columns are kept when the incoming ones are empty.
+	err := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "contract_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"executable_type":      gorm.Expr("EXCLUDED.executable_type"),
+			"wasm_hash":            gorm.Expr("EXCLUDED.wasm_hash"),
+			"asset":                gorm.Expr("COALESCE(EXCLUDED.asset, contracts.asset)"),
+			"deployer":             gorm.Expr("COALESCE(EXCLUDED.deployer, contracts.deployer)"),
+			"salt":                 gorm.Expr("COALESCE(EXCLUDED.salt, contracts.salt)"),
+			"created_ledger":       gorm.Expr("COALESCE(EXCLUDED.created_ledger, contracts.created_ledger)"),
+			"created_tx_hash":      gorm.Expr("COALESCE(EXCLUDED.created_tx_hash, contracts.created_tx_hash)"),
+			"last_modified_ledger": gorm.Expr("EXCLUDED.last_modified_ledger"),
+			"updated_at":           gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(contract).Error
+
+	return err
+}
+
+func UpsertContractUpgrade(db *gorm.DB, upgrade *ContractUpgrade) error {
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "contract_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"ledger", "old_wasm_hash", "new_wasm_hash"}),
+	}).Create(upgrade).Error
+
+	return err
+}
+
+func NewContract(inp []byte) (Contract, error) {
+	var contract Contract
+	err := json.Unmarshal(inp, &contract)
+	return contract, err
+}
+
+func NewContractUpgrade(inp []byte) (ContractUpgrade, error) {
+	var upgrade ContractUpgrade
+	err := json.Unmarshal(inp, &upgrade)
+	return upgrade, err
+}
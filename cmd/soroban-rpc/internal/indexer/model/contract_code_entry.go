@@ -0,0 +1,37 @@
+package model
+
+import (
+	"github.com/stellar/go/xdr"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContractCodeEntry struct {
+	Hash                string     `gorm:"type:varchar(64);primaryKey;not null"` // hex encoded WASM hash
+	KeyHash             string     `gorm:"type:text;index;not null"`             // used to match TTL entries
+	Size                int32      `gorm:"type:int;not null"`                    // WASM size in bytes
+	ExpirationLedgerSeq xdr.Uint32 `gorm:"type:int"`
+
+	Spec       interface{} `gorm:"type:jsonb"`       // native json of the contractspecv0 section
+	SpecXdr    string      `gorm:"type:text"`        // raw contractspecv0 section in base64
+	Meta       interface{} `gorm:"type:jsonb"`       // native json of the contractmetav0 section
+	EnvMeta    interface{} `gorm:"type:jsonb"`       // native json of the contractenvmetav0 section
+	SdkVersion string      `gorm:"type:varchar(64)"` // rssdkver entry of contractmetav0, if any
+
+	LastModifiedLedgerSeq xdr.Uint32 `gorm:"type:int;not null"`
+	util.Ts
+}
+
+func UpsertContractCodeEntry(db *gorm.DB, entry *ContractCodeEntry) error {
+	// The expiration is maintained by TTL entries, so it is left untouched on conflict.
+	err := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "hash"}}, // Conflict target
+		DoUpdates: clause.AssignmentColumns([]string{
+			"key_hash", "size", "spec", "spec_xdr", "meta", "env_meta",
+			"sdk_version", "last_modified_ledger_seq",
+		}), // Fields to update in case of conflict, excluding primary key
+	}).Create(entry).Error
+
+	return err
+}
@@ -19,6 +19,9 @@ type ContractDataEntry struct {
 	ValXdr     string      `gorm:"type:text"`
 	Val        interface{} `gorm:"type:jsonb"` // native json of contract data body val
 
+	DecodedKey interface{} `gorm:"type:jsonb"` // key decoded with the contract spec, if enabled
+	DecodedVal interface{} `gorm:"type:jsonb"` // val decoded with the contract spec, if enabled
+
 	util.Ts
 }
 
@@ -29,7 +32,7 @@ func UpsertContractDataEntry(db *gorm.DB, entry *ContractDataEntry) error {
 		Columns: []clause.Column{{Name: "key_hash"}}, // Conflict target
 		DoUpdates: clause.AssignmentColumns([]string{
 			"contract_id", "key_xdr", "expiration_ledger_seq",
-			"key", "durability", "flags", "val_xdr", "val",
+			"key", "durability", "flags", "val_xdr", "val", "decoded_key", "decoded_val",
 		}), // Fields to update in case of conflict, excluding primary key
 	}).Create(entry).Error
 
@@ -11,6 +11,8 @@ import (
 type Event struct {
 	ID                       string      `gorm:"column:id;primaryKey"`
 	TxIndex                  int32       `gorm:"column:tx_index"`
+	TxHash                   string      `gorm:"column:tx_hash;type:varchar(64);index"`
+	OpIndex                  int32       `gorm:"column:op_index"`
 	EventType                string      `gorm:"column:type"`
 	Ledger                   int32       `gorm:"column:ledger"`
 	LedgerClosedAt           string      `gorm:"column:ledger_closed_at"`
@@ -18,9 +20,15 @@ type Event struct {
 	PagingToken              string      `gorm:"column:paging_token"`
 	Topic                    interface{} `gorm:"column:topic;type:jsonb"`
 	Value                    interface{} `gorm:"column:value;type:jsonb"`
+	DecodedTopic             interface{} `gorm:"column:decoded_topic;type:jsonb"` // topic decoded with the contract spec, if enabled
+	DecodedValue             interface{} `gorm:"column:decoded_value;type:jsonb"` // value decoded with the contract spec, if enabled
 	InSuccessfulContractCall bool        `gorm:"column:in_successful_contract_call"`
 	LastModifiedLedgerSeq    xdr.Uint32  `gorm:"type:int;not null"`
 	util.Ts
+
+	// raw XDR passed along the queue for spec-aware decoding, not persisted
+	TopicXdr []string `gorm:"-"`
+	ValueXdr string   `gorm:"-"`
 }
 
 func UpsertEvent(db *gorm.DB, event *Event) error {
@@ -28,8 +36,8 @@ func UpsertEvent(db *gorm.DB, event *Event) error {
 	err := db.Clauses(clause.OnConflict{
 		Columns: []clause.Column{{Name: "id"}}, // Primary key for conflict resolution
 		DoUpdates: clause.AssignmentColumns([]string{
-			"tx_index", "type", "ledger", "ledger_closed_at", "contract_id",
-			"paging_token", "topic", "value", "in_successful_contract_call", "last_modified_ledger_seq",
+			"tx_index", "tx_hash", "op_index", "type", "ledger", "ledger_closed_at", "contract_id",
+			"paging_token", "topic", "value", "decoded_topic", "decoded_value", "in_successful_contract_call", "last_modified_ledger_seq",
 		}), // Specify fields to update on conflict, except the primary key
 	}).Create(event).Error
 
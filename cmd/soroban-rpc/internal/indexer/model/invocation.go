@@ -0,0 +1,66 @@
+package model
+
+import (
+	"encoding/json"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	HostFunctionInvokeContract = "invoke_contract"
+	HostFunctionCreateContract = "create_contract"
+	HostFunctionUploadWasm     = "upload_wasm"
+)
+
+type Invocation struct {
+	ID               string  `gorm:"column:id;primaryKey"` // <tx hash>-<op index>-<invocation index>
+	TxHash           string  `gorm:"column:tx_hash;type:varchar(64);index"`
+	OpIndex          int32   `gorm:"column:op_index"`
+	InvocationIndex  int32   `gorm:"column:invocation_index"` // 0 for the host function, then auth sub-invocations depth-first
+	ParentIndex      *int32  `gorm:"column:parent_index"`
+	Ledger           uint32  `gorm:"column:ledger;index"`
+	HostFunctionType string  `gorm:"column:host_function_type;type:varchar(16)"` // invoke_contract, create_contract or upload_wasm
+	Caller           *string `gorm:"column:caller;index"`                        // operation source, or the parent contract for sub-invocations
+	Authorizer       *string `gorm:"column:authorizer"`                          // address whose auth tree the sub-invocation comes from
+
+	ContractID         *string     `gorm:"column:contract_id;index:idx_invocations_contract_function"`
+	FunctionName       *string     `gorm:"column:function_name;index:idx_invocations_contract_function"`
+	Args               interface{} `gorm:"column:args;type:jsonb"`
+	DecodedArgs        interface{} `gorm:"column:decoded_args;type:jsonb"` // named args decoded with the contract spec, if enabled
+	ReturnValue        interface{} `gorm:"column:return_value;type:jsonb"` // only known for the host function itself
+	DecodedReturnValue interface{} `gorm:"column:decoded_return_value;type:jsonb"`
+	Successful         bool        `gorm:"column:successful"` // outcome of the enclosing transaction
+
+	// resources used by the transaction, shared by all its invocations. The instructions and bytes
+	// are only known if stellar-core emits diagnostic events (with the core metrics).
+	Instructions *uint64 `gorm:"column:instructions"`
+	ReadBytes    *uint64 `gorm:"column:read_bytes"`
+	WriteBytes   *uint64 `gorm:"column:write_bytes"`
+	ResourceFee  *int64  `gorm:"column:resource_fee"` // resource fee charged, after the refund
+	util.Ts
+
+	// raw XDR passed along the queue for spec-aware decoding, not persisted
+	ArgsXdr        []string `gorm:"-"`
+	ReturnValueXdr string   `gorm:"-"`
+}
+
+func UpsertInvocation(db *gorm.DB, invocation *Invocation) error {
+	err := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"tx_hash", "op_index", "invocation_index", "parent_index", "ledger", "host_function_type",
+			"caller", "authorizer", "contract_id", "function_name", "args", "decoded_args", "return_value",
+			"decoded_return_value", "successful", "instructions", "read_bytes", "write_bytes", "resource_fee",
+		}),
+	}).Create(invocation).Error
+
+	return err
+}
+
+func NewInvocation(inp []byte) (Invocation, error) {
+	var invocation Invocation
+	err := json.Unmarshal(inp, &invocation)
+	return invocation, err
+}
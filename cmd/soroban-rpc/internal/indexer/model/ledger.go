@@ -0,0 +1,52 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Ledger struct {
+	Sequence                   uint32      `gorm:"column:sequence;primaryKey"`
+	Hash                       string      `gorm:"column:hash;type:varchar(64);uniqueIndex"`
+	PreviousHash               string      `gorm:"column:previous_hash;type:varchar(64)"`
+	CloseTime                  time.Time   `gorm:"column:close_time;index"`
+	ProtocolVersion            uint32      `gorm:"column:protocol_version"`
+	BaseFee                    uint32      `gorm:"column:base_fee"`
+	BaseReserve                uint32      `gorm:"column:base_reserve"`
+	MaxTxSetSize               uint32      `gorm:"column:max_tx_set_size"`
+	TransactionCount           int32       `gorm:"column:transaction_count"`
+	SuccessfulTransactionCount int32       `gorm:"column:successful_transaction_count"`
+	FailedTransactionCount     int32       `gorm:"column:failed_transaction_count"`
+	OperationCount             int32       `gorm:"column:operation_count"`
+	SorobanFeeConfig           interface{} `gorm:"column:soroban_fee_config;type:jsonb"` // network fee settings at the time the ledger closed, if known
+	util.Ts
+}
+
+func UpsertLedger(db *gorm.DB, ledger *Ledger) error {
+	err := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "sequence"}},
+		DoUpdates: clause.AssignmentColumns([]string{"hash", "previous_hash", "close_time", "protocol_version",
+			"base_fee", "base_reserve", "max_tx_set_size", "transaction_count", "successful_transaction_count",
+			"failed_transaction_count", "operation_count", "soroban_fee_config"}),
+	}).Create(ledger).Error
+
+	return err
+}
+
+// GetLedgers returns the stored ledgers within [startLedger, endLedger], in ascending order.
+// Missing sequences are gaps in the ingestion.
+func GetLedgers(db *gorm.DB, startLedger uint32, endLedger uint32) ([]Ledger, error) {
+	var ledgers []Ledger
+	err := db.Where("sequence BETWEEN ? AND ?", startLedger, endLedger).Order("sequence").Find(&ledgers).Error
+	return ledgers, err
+}
+
+func NewLedger(inp []byte) (Ledger, error) {
+	var ledger Ledger
+	err := json.Unmarshal(inp, &ledger)
+	return ledger, err
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Operation struct {
+	ID             string      `gorm:"column:id;primaryKey"` // <tx hash>-<op index>
+	TxHash         string      `gorm:"column:tx_hash;type:varchar(64);index"`
+	OpIndex        int32       `gorm:"column:op_index"`
+	Ledger         *uint32     `gorm:"column:ledger;index"`
+	Type           string      `gorm:"column:type;index"`           // snake cased operation type, e.g. path_payment_strict_send
+	SourceAccount  *string     `gorm:"column:source_account;index"` // operation source, or the transaction source if not set
+	MuxedAccountId *int64      `gorm:"column:muxed_account_id"`     // only set for muxed account
+	Body           interface{} `gorm:"column:body;type:jsonb"`
+	ResultCode     *string     `gorm:"column:result_code"` // only set when the transaction has operation results
+	util.Ts
+}
+
+func UpsertOperations(db *gorm.DB, ops []Operation) error {
+	if len(ops) == 0 {
+		return nil
+	}
+	err := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"tx_hash", "op_index", "ledger", "type", "source_account",
+			"muxed_account_id", "body", "result_code"}),
+	}).Create(&ops).Error
+
+	return err
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	ActivityTransaction    = "transaction"
+	ActivityEvent          = "event"
+	ActivityTokenOperation = "token_operation"
+)
+
+// activityRanks orders the activities sharing a paging token:
+// a transaction comes before its first event, and a token operation after the event it was parsed from.
+var activityRanks = map[string]int16{
+	ActivityTransaction:    0,
+	ActivityEvent:          1,
+	ActivityTokenOperation: 2,
+}
+
+// Participant links an account or contract address to a transaction, event or token operation it took part in.
+type Participant struct {
+	Address      string `gorm:"column:address;primaryKey;type:varchar(69)"`
+	PagingToken  string `gorm:"column:paging_token;primaryKey;type:varchar(30)"` // cursor of the first event of the activity, in ledger order
+	Rank         int16  `gorm:"column:rank;primaryKey"`                          // breaks ties between activities sharing a paging token
+	ActivityType string `gorm:"column:activity_type;type:varchar(16)"`           // transaction, event or token_operation
+	ActivityID   string `gorm:"column:activity_id"`                              // tx hash, or event id for events and token operations
+	TxHash       string `gorm:"column:tx_hash;type:varchar(64)"`
+	Ledger       uint32 `gorm:"column:ledger;index"`
+	util.Ts
+}
+
+func NewParticipant(address, activityType, activityID, pagingToken, txHash string, ledger uint32, ts util.Ts) Participant {
+	return Participant{
+		Address:      address,
+		PagingToken:  pagingToken,
+		Rank:         activityRanks[activityType],
+		ActivityType: activityType,
+		ActivityID:   activityID,
+		TxHash:       txHash,
+		Ledger:       ledger,
+		Ts:           ts,
+	}
+}
+
+func UpsertParticipants(db *gorm.DB, participants []Participant) error {
+	if len(participants) == 0 {
+		return nil
+	}
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "address"}, {Name: "paging_token"}, {Name: "rank"}},
+		DoUpdates: clause.AssignmentColumns([]string{"activity_type", "activity_id", "tx_hash", "ledger"}),
+	}).Create(&participants).Error
+
+	return err
+}
+
+// GetParticipants returns the activities of an address in ledger order, starting after the given position.
+func GetParticipants(db *gorm.DB, address string, afterPagingToken string, afterRank int16, limit int) ([]Participant, error) {
+	var participants []Participant
+	query := db.Where("address = ?", address)
+	if afterPagingToken != "" {
+		query = query.Where("(paging_token, rank) > (?, ?)", afterPagingToken, afterRank)
+	}
+	err := query.Order("paging_token, rank").Limit(limit).Find(&participants).Error
+	return participants, err
+}
@@ -11,6 +11,7 @@ type TokenOperation struct {
 	ID               string       `gorm:"column:id;primaryKey"`
 	Type             string       `gorm:"column:type"`
 	TxIndex          int32        `gorm:"column:tx_index"`
+	TxHash           string       `gorm:"column:tx_hash;type:varchar(64);index"`
 	Ledger           int32        `gorm:"column:ledger"`
 	LedgerClosedAt   string       `gorm:"column:ledger_closed_at"`
 	ContractID       string       `gorm:"column:contract_id"`
@@ -28,7 +29,7 @@ func UpsertTokenOperation(db *gorm.DB, tokenOp *TokenOperation) error {
 	err := db.Clauses(
 		clause.OnConflict{
 			Columns:   []clause.Column{{Name: "id"}}, // Primary Key
-			DoUpdates: clause.AssignmentColumns([]string{"type", "tx_index", "ledger", "ledger_closed_at", "contract_id", "from", "to", "amount", "authorized", "expiration_ledger", "created_at", "updated_at"}),
+			DoUpdates: clause.AssignmentColumns([]string{"type", "tx_index", "tx_hash", "ledger", "ledger_closed_at", "contract_id", "from", "to", "amount", "authorized", "expiration_ledger", "created_at", "updated_at"}),
 		}).Create(tokenOp).Error
 	return err
 }
@@ -15,23 +15,44 @@ type Transaction struct {
 	ApplicationOrder *int32              `gorm:"column:application_order"`
 	FeeBump          *bool               `gorm:"column:fee_bump"`
 	FeeBumpInfo      *util.FeeBumpInfo   `gorm:"column:fee_bump_info;type:jsonb"`
-	Fee              *int32              `gorm:"column:fee"`
-	FeeCharged       *int32              `gorm:"column:fee_charged"`
+	Fee              *int64              `gorm:"column:fee"`
+	FeeCharged       *int64              `gorm:"column:fee_charged"`
+	ResultCode       *string             `gorm:"column:result_code"`
+	InnerResultCode  *string             `gorm:"column:inner_result_code"` // only set for fee bump transactions
+	OperationCount   *int32              `gorm:"column:operation_count"`
 	Sequence         *int64              `gorm:"column:sequence"`
 	SourceAccount    *string             `gorm:"column:source_account"`
 	MuxedAccountId   *int64              `gorm:"column:muxed_account_id"` // only set for muxed account
 	Memo             *util.TypeItem      `gorm:"column:memo;type:jsonb"`
 	Preconditions    *util.Preconditions `gorm:"column:preconditions;type:jsonb"`
 	Signatures       *[]util.Signature   `gorm:"column:signatures;type:jsonb"`
+
+	// Soroban resource fees, only set for Soroban transactions
+	ResourceFee              *int64 `gorm:"column:resource_fee"`        // declared in the transaction data
+	ResourceFeeRefund        *int64 `gorm:"column:resource_fee_refund"` // refunded after execution
+	NonRefundableResourceFee *int64 `gorm:"column:non_refundable_resource_fee"`
+	RefundableResourceFee    *int64 `gorm:"column:refundable_resource_fee"` // refundable fee actually charged, rent included
+	RentFee                  *int64 `gorm:"column:rent_fee"`
+
+	// raw XDR, only stored if enabled
+	EnvelopeXdr *string `gorm:"column:envelope_xdr"`
+	ResultXdr   *string `gorm:"column:result_xdr"`
+	MetaXdr     *string `gorm:"column:meta_xdr"`
 	util.Ts
+
+	// operations and participants are queued along with their transaction and stored in their own tables
+	Operations   []Operation   `gorm:"-"`
+	Participants []Participant `gorm:"-"`
 }
 
 func UpsertTransaction(db *gorm.DB, tx *Transaction) error {
 	err := db.Clauses(clause.OnConflict{
 		Columns: []clause.Column{{Name: "id"}},
 		DoUpdates: clause.AssignmentColumns([]string{"status", "ledger", "created_at", "application_order", "fee_bump",
-			"fee_bump_info", "fee", "fee_charged", "sequence", "source_account", "muxed_account_id", "memo", "preconditions",
-			"signatures"}), // List columns to update
+			"fee_bump_info", "fee", "fee_charged", "result_code", "inner_result_code", "operation_count", "sequence",
+			"source_account", "muxed_account_id", "memo", "preconditions", "signatures", "resource_fee", "resource_fee_refund",
+			"non_refundable_resource_fee", "refundable_resource_fee", "rent_fee", "envelope_xdr", "result_xdr",
+			"meta_xdr"}), // List columns to update
 	}).Create(tx).Error
 
 	return err
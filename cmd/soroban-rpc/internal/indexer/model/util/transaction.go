@@ -33,7 +33,7 @@ type Preconditions struct {
 }
 
 type FeeBumpInfo struct {
-	Fee            int32   `json:"fee"`
+	Fee            int64   `json:"fee"`
 	SourceAccount  *string `json:"source_account"`
 	MuxedAccountId *int64  `json:"muxed_account_id"`
 }
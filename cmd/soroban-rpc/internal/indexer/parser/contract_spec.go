@@ -0,0 +1,197 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/xdr"
+)
+
+type SpecField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Doc  string `json:"doc,omitempty"`
+}
+
+type SpecCase struct {
+	Name  string   `json:"name"`
+	Value *uint32  `json:"value,omitempty"` // enum and error enum cases
+	Types []string `json:"types,omitempty"` // union tuple cases
+	Doc   string   `json:"doc,omitempty"`
+}
+
+type SpecEntry struct {
+	Kind    string      `json:"kind"` // function, struct, union, enum or error_enum
+	Name    string      `json:"name"`
+	Lib     string      `json:"lib,omitempty"`
+	Doc     string      `json:"doc,omitempty"`
+	Inputs  []SpecField `json:"inputs,omitempty"`
+	Outputs []string    `json:"outputs,omitempty"`
+	Fields  []SpecField `json:"fields,omitempty"`
+	Cases   []SpecCase  `json:"cases,omitempty"`
+}
+
+type EnvMeta struct {
+	InterfaceVersion uint64 `json:"interface_version"`
+	Protocol         uint32 `json:"protocol"`
+	PreRelease       uint32 `json:"pre_release"`
+}
+
+// DecodeContractSpec decodes the contents of a contractspecv0 section,
+// which is a plain concatenation of ScSpecEntry XDR values.
+func DecodeContractSpec(section []byte) ([]xdr.ScSpecEntry, error) {
+	var entries []xdr.ScSpecEntry
+	r := bytes.NewReader(section)
+	for r.Len() > 0 {
+		var entry xdr.ScSpecEntry
+		if _, err := xdr.Unmarshal(r, &entry); err != nil {
+			return nil, fmt.Errorf("could not decode contract spec entry %d: %w", len(entries), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// DecodeContractMeta decodes the contents of a contractmetav0 section into its key/value pairs.
+func DecodeContractMeta(section []byte) (map[string]string, error) {
+	meta := make(map[string]string)
+	r := bytes.NewReader(section)
+	for r.Len() > 0 {
+		var entry xdr.ScMetaEntry
+		if _, err := xdr.Unmarshal(r, &entry); err != nil {
+			return nil, fmt.Errorf("could not decode contract meta entry: %w", err)
+		}
+		if v0, ok := entry.GetV0(); ok {
+			meta[v0.Key] = v0.Val
+		}
+	}
+	return meta, nil
+}
+
+// DecodeContractEnvMeta decodes the contents of a contractenvmetav0 section.
+func DecodeContractEnvMeta(section []byte) (*EnvMeta, error) {
+	var envMeta *EnvMeta
+	r := bytes.NewReader(section)
+	for r.Len() > 0 {
+		var entry xdr.ScEnvMetaEntry
+		if _, err := xdr.Unmarshal(r, &entry); err != nil {
+			return nil, fmt.Errorf("could not decode contract env meta entry: %w", err)
+		}
+		if version, ok := entry.GetInterfaceVersion(); ok {
+			// the upper 32 bits are the protocol, the lower ones the pre-release version
+			envMeta = &EnvMeta{
+				InterfaceVersion: uint64(version),
+				Protocol:         uint32(version >> 32),
+				PreRelease:       uint32(version),
+			}
+		}
+	}
+	return envMeta, nil
+}
+
+func GetSpecEntries(entries []xdr.ScSpecEntry) []SpecEntry {
+	result := make([]SpecEntry, 0, len(entries))
+	for _, entry := range entries {
+		switch entry.Kind {
+		case xdr.ScSpecEntryKindScSpecEntryFunctionV0:
+			fn := entry.FunctionV0
+			item := SpecEntry{Kind: "function", Name: string(fn.Name), Doc: fn.Doc}
+			for _, input := range fn.Inputs {
+				item.Inputs = append(item.Inputs, SpecField{Name: input.Name, Type: SpecTypeName(input.Type), Doc: input.Doc})
+			}
+			for _, output := range fn.Outputs {
+				item.Outputs = append(item.Outputs, SpecTypeName(output))
+			}
+			result = append(result, item)
+		case xdr.ScSpecEntryKindScSpecEntryUdtStructV0:
+			udt := entry.UdtStructV0
+			item := SpecEntry{Kind: "struct", Name: udt.Name, Lib: udt.Lib, Doc: udt.Doc}
+			for _, field := range udt.Fields {
+				item.Fields = append(item.Fields, SpecField{Name: field.Name, Type: SpecTypeName(field.Type), Doc: field.Doc})
+			}
+			result = append(result, item)
+		case xdr.ScSpecEntryKindScSpecEntryUdtUnionV0:
+			udt := entry.UdtUnionV0
+			item := SpecEntry{Kind: "union", Name: udt.Name, Lib: udt.Lib, Doc: udt.Doc}
+			for _, c := range udt.Cases {
+				if voidCase, ok := c.GetVoidCase(); ok {
+					item.Cases = append(item.Cases, SpecCase{Name: voidCase.Name, Doc: voidCase.Doc})
+				} else if tupleCase, ok := c.GetTupleCase(); ok {
+					types := make([]string, 0, len(tupleCase.Type))
+					for _, t := range tupleCase.Type {
+						types = append(types, SpecTypeName(t))
+					}
+					item.Cases = append(item.Cases, SpecCase{Name: tupleCase.Name, Types: types, Doc: tupleCase.Doc})
+				}
+			}
+			result = append(result, item)
+		case xdr.ScSpecEntryKindScSpecEntryUdtEnumV0:
+			udt := entry.UdtEnumV0
+			item := SpecEntry{Kind: "enum", Name: udt.Name, Lib: udt.Lib, Doc: udt.Doc}
+			for _, c := range udt.Cases {
+				value := uint32(c.Value)
+				item.Cases = append(item.Cases, SpecCase{Name: c.Name, Value: &value, Doc: c.Doc})
+			}
+			result = append(result, item)
+		case xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0:
+			udt := entry.UdtErrorEnumV0
+			item := SpecEntry{Kind: "error_enum", Name: udt.Name, Lib: udt.Lib, Doc: udt.Doc}
+			for _, c := range udt.Cases {
+				value := uint32(c.Value)
+				item.Cases = append(item.Cases, SpecCase{Name: c.Name, Value: &value, Doc: c.Doc})
+			}
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+var specTypeNames = map[xdr.ScSpecType]string{
+	xdr.ScSpecTypeScSpecTypeVal:       "Val",
+	xdr.ScSpecTypeScSpecTypeBool:      "bool",
+	xdr.ScSpecTypeScSpecTypeVoid:      "void",
+	xdr.ScSpecTypeScSpecTypeError:     "Error",
+	xdr.ScSpecTypeScSpecTypeU32:       "u32",
+	xdr.ScSpecTypeScSpecTypeI32:       "i32",
+	xdr.ScSpecTypeScSpecTypeU64:       "u64",
+	xdr.ScSpecTypeScSpecTypeI64:       "i64",
+	xdr.ScSpecTypeScSpecTypeTimepoint: "Timepoint",
+	xdr.ScSpecTypeScSpecTypeDuration:  "Duration",
+	xdr.ScSpecTypeScSpecTypeU128:      "u128",
+	xdr.ScSpecTypeScSpecTypeI128:      "i128",
+	xdr.ScSpecTypeScSpecTypeU256:      "u256",
+	xdr.ScSpecTypeScSpecTypeI256:      "i256",
+	xdr.ScSpecTypeScSpecTypeBytes:     "Bytes",
+	xdr.ScSpecTypeScSpecTypeString:    "String",
+	xdr.ScSpecTypeScSpecTypeSymbol:    "Symbol",
+	xdr.ScSpecTypeScSpecTypeAddress:   "Address",
+}
+
+// SpecTypeName renders a spec type the way it is written in the Rust SDK, e.g. Map<Address, i128>
+func SpecTypeName(t xdr.ScSpecTypeDef) string {
+	if name, ok := specTypeNames[t.Type]; ok {
+		return name
+	}
+	switch t.Type {
+	case xdr.ScSpecTypeScSpecTypeOption:
+		return "Option<" + SpecTypeName(t.Option.ValueType) + ">"
+	case xdr.ScSpecTypeScSpecTypeResult:
+		return "Result<" + SpecTypeName(t.Result.OkType) + ", " + SpecTypeName(t.Result.ErrorType) + ">"
+	case xdr.ScSpecTypeScSpecTypeVec:
+		return "Vec<" + SpecTypeName(t.Vec.ElementType) + ">"
+	case xdr.ScSpecTypeScSpecTypeMap:
+		return "Map<" + SpecTypeName(t.Map.KeyType) + ", " + SpecTypeName(t.Map.ValueType) + ">"
+	case xdr.ScSpecTypeScSpecTypeTuple:
+		names := make([]string, 0, len(t.Tuple.ValueTypes))
+		for _, v := range t.Tuple.ValueTypes {
+			names = append(names, SpecTypeName(v))
+		}
+		return "(" + strings.Join(names, ", ") + ")"
+	case xdr.ScSpecTypeScSpecTypeBytesN:
+		return fmt.Sprintf("BytesN<%d>", t.BytesN.N)
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		return t.Udt.Name
+	}
+	return t.Type.String()
+}
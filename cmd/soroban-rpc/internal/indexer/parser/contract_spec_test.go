@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const helloWorldContractPath = "../../../../../wasms/test_hello_world.wasm"
+
+func TestDecodeContractSections(t *testing.T) {
+	code, err := os.ReadFile(helloWorldContractPath)
+	require.NoError(t, err)
+
+	sections, err := GetWasmCustomSections(code)
+	require.NoError(t, err)
+	require.Contains(t, sections, ContractSpecSection)
+	require.Contains(t, sections, ContractEnvMetaSection)
+
+	specEntries, err := DecodeContractSpec(sections[ContractSpecSection])
+	require.NoError(t, err)
+	spec := GetSpecEntries(specEntries)
+	require.NotEmpty(t, spec)
+
+	names := map[string]SpecEntry{}
+	for _, entry := range spec {
+		names[entry.Name] = entry
+	}
+	require.Contains(t, names, "hello")
+	assert.Equal(t, "function", names["hello"].Kind)
+	assert.Equal(t, []string{"Vec<Symbol>"}, names["hello"].Outputs)
+
+	envMeta, err := DecodeContractEnvMeta(sections[ContractEnvMetaSection])
+	require.NoError(t, err)
+	require.NotNil(t, envMeta)
+	assert.Equal(t, uint32(20), envMeta.Protocol)
+}
+
+func TestGetWasmCustomSectionsInvalid(t *testing.T) {
+	_, err := GetWasmCustomSections([]byte("not wasm"))
+	assert.Error(t, err)
+
+	// truncated custom section
+	_, err = GetWasmCustomSections([]byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x00, 0x10, 0x01})
+	assert.Error(t, err)
+}
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 
 	"github.com/shopspring/decimal"
@@ -118,6 +120,47 @@ func GetContractDataModel(entry xdr.LedgerEntry) *model.ContractDataEntry {
 	return em
 }
 
+func GetContractCodeModel(entry xdr.LedgerEntry) *model.ContractCodeEntry {
+	contractCode := entry.Data.ContractCode
+
+	em := &model.ContractCodeEntry{
+		Hash: hex.EncodeToString(contractCode.Hash[:]),
+		Size: int32(len(contractCode.Code)),
+	}
+
+	// custom sections are optional, a malformed or spec-less WASM is still indexed
+	sections, err := GetWasmCustomSections(contractCode.Code)
+	if err != nil {
+		return em
+	}
+
+	if section, ok := sections[ContractSpecSection]; ok {
+		em.SpecXdr = base64.StdEncoding.EncodeToString(section)
+		if specEntries, err := DecodeContractSpec(section); err == nil {
+			spec, _ := json.Marshal(GetSpecEntries(specEntries))
+			em.Spec = string(spec)
+		}
+	}
+
+	if section, ok := sections[ContractMetaSection]; ok {
+		if meta, err := DecodeContractMeta(section); err == nil {
+			em.SdkVersion = meta["rssdkver"]
+			metaJSON, _ := json.Marshal(meta)
+			em.Meta = string(metaJSON)
+		}
+	}
+
+	if section, ok := sections[ContractEnvMetaSection]; ok {
+		if envMeta, err := DecodeContractEnvMeta(section); err == nil && envMeta != nil {
+			envMetaJSON, _ := json.Marshal(envMeta)
+			em.EnvMeta = string(envMetaJSON)
+		}
+	}
+
+	em.LastModifiedLedgerSeq = entry.LastModifiedLedgerSeq
+	return em
+}
+
 func GetAccountEntryModel(entry xdr.LedgerEntry) *model.AccountEntry {
 	inflationDest, _ := entry.Data.Account.InflationDest.GetAddress()
 	em := &model.AccountEntry{
@@ -0,0 +1,375 @@
+package parser
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrjson"
+)
+
+// SpecDecoder renders ScVals using the user defined types of a contract spec.
+//
+// When the type of a value is known (e.g. a function input or a struct field) it is decoded
+// against that type. Otherwise the type is inferred: maps whose symbol keys match the fields
+// of a struct are rendered as that struct and vectors starting with a symbol matching a union
+// case are rendered as that case.
+//
+// The output is meant to be stored as jsonb, so integers are rendered as JSON numbers
+// regardless of their width, bytes are hex encoded and addresses are strkey encoded.
+// Union cases are externally tagged: void cases become their name, cases with a single
+// value {"Case": value} and cases with several values {"Case": [values...]}.
+type SpecDecoder struct {
+	structs         map[string]xdr.ScSpecUdtStructV0
+	unions          map[string]xdr.ScSpecUdtUnionV0
+	enums           map[string]xdr.ScSpecUdtEnumV0
+	errorEnums      map[string]xdr.ScSpecUdtErrorEnumV0
+	structsByFields map[string]string
+	functions       map[string]xdr.ScSpecFunctionV0
+}
+
+func NewSpecDecoder(entries []xdr.ScSpecEntry) *SpecDecoder {
+	d := &SpecDecoder{
+		structs:         make(map[string]xdr.ScSpecUdtStructV0),
+		unions:          make(map[string]xdr.ScSpecUdtUnionV0),
+		enums:           make(map[string]xdr.ScSpecUdtEnumV0),
+		errorEnums:      make(map[string]xdr.ScSpecUdtErrorEnumV0),
+		structsByFields: make(map[string]string),
+		functions:       make(map[string]xdr.ScSpecFunctionV0),
+	}
+	for _, entry := range entries {
+		switch entry.Kind {
+		case xdr.ScSpecEntryKindScSpecEntryFunctionV0:
+			d.functions[string(entry.FunctionV0.Name)] = *entry.FunctionV0
+		case xdr.ScSpecEntryKindScSpecEntryUdtStructV0:
+			udt := *entry.UdtStructV0
+			d.structs[udt.Name] = udt
+			names := make([]string, 0, len(udt.Fields))
+			for _, field := range udt.Fields {
+				names = append(names, field.Name)
+			}
+			d.structsByFields[fieldSetKey(names)] = udt.Name
+		case xdr.ScSpecEntryKindScSpecEntryUdtUnionV0:
+			d.unions[entry.UdtUnionV0.Name] = *entry.UdtUnionV0
+		case xdr.ScSpecEntryKindScSpecEntryUdtEnumV0:
+			d.enums[entry.UdtEnumV0.Name] = *entry.UdtEnumV0
+		case xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0:
+			d.errorEnums[entry.UdtErrorEnumV0.Name] = *entry.UdtErrorEnumV0
+		}
+	}
+	return d
+}
+
+// NewSpecDecoderFromXdr builds a decoder from a base64 encoded contractspecv0 section.
+func NewSpecDecoderFromXdr(specXdr string) (*SpecDecoder, error) {
+	section, err := base64.StdEncoding.DecodeString(specXdr)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := DecodeContractSpec(section)
+	if err != nil {
+		return nil, err
+	}
+	return NewSpecDecoder(entries), nil
+}
+
+// Function returns the spec of the given contract function, if any.
+func (d *SpecDecoder) Function(name string) (xdr.ScSpecFunctionV0, bool) {
+	fn, ok := d.functions[name]
+	return fn, ok
+}
+
+// Decode renders a value whose type is unknown.
+func (d *SpecDecoder) Decode(val xdr.ScVal) interface{} {
+	switch val.Type {
+	case xdr.ScValTypeScvVec:
+		if val.Vec == nil || *val.Vec == nil {
+			return nil
+		}
+		vec := **val.Vec
+		if decoded, ok := d.decodeInferredUnion(vec); ok {
+			return decoded
+		}
+		list := make([]interface{}, 0, len(vec))
+		for _, item := range vec {
+			list = append(list, d.Decode(item))
+		}
+		return list
+	case xdr.ScValTypeScvMap:
+		if val.Map == nil || *val.Map == nil {
+			return nil
+		}
+		scMap := **val.Map
+		if names, ok := symbolKeys(scMap); ok {
+			if name, ok := d.structsByFields[fieldSetKey(names)]; ok {
+				return d.decodeStruct(scMap, d.structs[name])
+			}
+			obj := make(map[string]interface{}, len(scMap))
+			for i, entry := range scMap {
+				obj[names[i]] = d.Decode(entry.Val)
+			}
+			return obj
+		}
+		return d.decodePairs(scMap, nil, nil)
+	case xdr.ScValTypeScvContractInstance:
+		if val.Instance == nil {
+			return nil
+		}
+		data := make(map[string]interface{})
+		data["executable"] = xdrjson.ContractExecutableToJSON(val.Instance.Executable)
+		if val.Instance.Storage != nil {
+			data["storage"] = d.Decode(xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &val.Instance.Storage})
+		}
+		return data
+	case xdr.ScValTypeScvError:
+		return d.decodeError(*val.Error, nil)
+	}
+	return decodePrimitive(val)
+}
+
+// DecodeWithType renders a value of a known spec type, falling back to Decode when they don't match.
+func (d *SpecDecoder) DecodeWithType(val xdr.ScVal, t xdr.ScSpecTypeDef) interface{} {
+	switch t.Type {
+	case xdr.ScSpecTypeScSpecTypeOption:
+		if val.Type == xdr.ScValTypeScvVoid {
+			return nil
+		}
+		return d.DecodeWithType(val, t.Option.ValueType)
+	case xdr.ScSpecTypeScSpecTypeResult:
+		if val.Type == xdr.ScValTypeScvError {
+			return d.decodeError(*val.Error, &t.Result.ErrorType)
+		}
+		return d.DecodeWithType(val, t.Result.OkType)
+	case xdr.ScSpecTypeScSpecTypeVec:
+		if vec, ok := val.GetVec(); ok && vec != nil {
+			list := make([]interface{}, 0, len(*vec))
+			for _, item := range *vec {
+				list = append(list, d.DecodeWithType(item, t.Vec.ElementType))
+			}
+			return list
+		}
+	case xdr.ScSpecTypeScSpecTypeTuple:
+		if vec, ok := val.GetVec(); ok && vec != nil && len(*vec) == len(t.Tuple.ValueTypes) {
+			list := make([]interface{}, 0, len(*vec))
+			for i, item := range *vec {
+				list = append(list, d.DecodeWithType(item, t.Tuple.ValueTypes[i]))
+			}
+			return list
+		}
+	case xdr.ScSpecTypeScSpecTypeMap:
+		if scMap, ok := val.GetMap(); ok && scMap != nil {
+			if names, ok := symbolKeys(*scMap); ok {
+				obj := make(map[string]interface{}, len(*scMap))
+				for i, entry := range *scMap {
+					obj[names[i]] = d.DecodeWithType(entry.Val, t.Map.ValueType)
+				}
+				return obj
+			}
+			return d.decodePairs(*scMap, &t.Map.KeyType, &t.Map.ValueType)
+		}
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		if decoded, ok := d.decodeUdt(val, t.Udt.Name); ok {
+			return decoded
+		}
+	}
+	return d.Decode(val)
+}
+
+func (d *SpecDecoder) decodeUdt(val xdr.ScVal, name string) (interface{}, bool) {
+	if udt, ok := d.structs[name]; ok {
+		if scMap, ok := val.GetMap(); ok && scMap != nil && hasSymbolKeys(*scMap) {
+			return d.decodeStruct(*scMap, udt), true
+		}
+		// tuple structs are encoded as vectors
+		if vec, ok := val.GetVec(); ok && vec != nil && len(*vec) == len(udt.Fields) {
+			list := make([]interface{}, 0, len(*vec))
+			for i, item := range *vec {
+				list = append(list, d.DecodeWithType(item, udt.Fields[i].Type))
+			}
+			return list, true
+		}
+		return nil, false
+	}
+	if udt, ok := d.unions[name]; ok {
+		if vec, ok := val.GetVec(); ok && vec != nil {
+			return d.decodeUnion(*vec, udt)
+		}
+		return nil, false
+	}
+	if udt, ok := d.enums[name]; ok {
+		if u32, ok := val.GetU32(); ok {
+			for _, c := range udt.Cases {
+				if c.Value == u32 {
+					return c.Name, true
+				}
+			}
+		}
+		return nil, false
+	}
+	if udt, ok := d.errorEnums[name]; ok {
+		if u32, ok := val.GetU32(); ok {
+			for _, c := range udt.Cases {
+				if c.Value == u32 {
+					return c.Name, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// hasSymbolKeys checks whether a map can be a struct, whose fields are keyed by name
+func hasSymbolKeys(scMap xdr.ScMap) bool {
+	for _, entry := range scMap {
+		if _, ok := entry.Key.GetSym(); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *SpecDecoder) decodeStruct(scMap xdr.ScMap, udt xdr.ScSpecUdtStructV0) interface{} {
+	types := make(map[string]xdr.ScSpecTypeDef, len(udt.Fields))
+	for _, field := range udt.Fields {
+		types[field.Name] = field.Type
+	}
+	obj := make(map[string]interface{}, len(scMap))
+	for _, entry := range scMap {
+		name := string(entry.Key.MustSym())
+		if t, ok := types[name]; ok {
+			obj[name] = d.DecodeWithType(entry.Val, t)
+		} else {
+			obj[name] = d.Decode(entry.Val)
+		}
+	}
+	return obj
+}
+
+func (d *SpecDecoder) decodeUnion(vec xdr.ScVec, udt xdr.ScSpecUdtUnionV0) (interface{}, bool) {
+	if len(vec) == 0 || vec[0].Type != xdr.ScValTypeScvSymbol {
+		return nil, false
+	}
+	name := string(*vec[0].Sym)
+	for _, c := range udt.Cases {
+		if voidCase, ok := c.GetVoidCase(); ok && voidCase.Name == name && len(vec) == 1 {
+			return name, true
+		}
+		if tupleCase, ok := c.GetTupleCase(); ok && tupleCase.Name == name && len(vec) == len(tupleCase.Type)+1 {
+			values := make([]interface{}, 0, len(tupleCase.Type))
+			for i, t := range tupleCase.Type {
+				values = append(values, d.DecodeWithType(vec[i+1], t))
+			}
+			if len(values) == 1 {
+				return map[string]interface{}{name: values[0]}, true
+			}
+			return map[string]interface{}{name: values}, true
+		}
+	}
+	return nil, false
+}
+
+func (d *SpecDecoder) decodeInferredUnion(vec xdr.ScVec) (interface{}, bool) {
+	if len(vec) == 0 || vec[0].Type != xdr.ScValTypeScvSymbol {
+		return nil, false
+	}
+	// iterate in a stable order, so that ambiguous values are always rendered the same way
+	names := make([]string, 0, len(d.unions))
+	for name := range d.unions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if decoded, ok := d.decodeUnion(vec, d.unions[name]); ok {
+			return decoded, true
+		}
+	}
+	return nil, false
+}
+
+func (d *SpecDecoder) decodePairs(scMap xdr.ScMap, keyType *xdr.ScSpecTypeDef, valueType *xdr.ScSpecTypeDef) interface{} {
+	pairs := make([]interface{}, 0, len(scMap))
+	for _, entry := range scMap {
+		var key, value interface{}
+		if keyType != nil {
+			key = d.DecodeWithType(entry.Key, *keyType)
+		} else {
+			key = d.Decode(entry.Key)
+		}
+		if valueType != nil {
+			value = d.DecodeWithType(entry.Val, *valueType)
+		} else {
+			value = d.Decode(entry.Val)
+		}
+		pairs = append(pairs, map[string]interface{}{"key": key, "value": value})
+	}
+	return pairs
+}
+
+func (d *SpecDecoder) decodeError(scError xdr.ScError, errorType *xdr.ScSpecTypeDef) interface{} {
+	data := map[string]interface{}{"type": scError.Type.String()}
+	if scError.Code != nil {
+		data["code"] = scError.Code.String()
+		return data
+	}
+	if scError.ContractCode == nil {
+		return data
+	}
+	code := uint32(*scError.ContractCode)
+	data["code"] = code
+	candidates := d.errorEnums
+	if errorType != nil && errorType.Type == xdr.ScSpecTypeScSpecTypeUdt {
+		if udt, ok := d.errorEnums[errorType.Udt.Name]; ok {
+			candidates = map[string]xdr.ScSpecUdtErrorEnumV0{udt.Name: udt}
+		}
+	}
+	// only name the error when it is unambiguous
+	var names []string
+	for _, udt := range candidates {
+		for _, c := range udt.Cases {
+			if uint32(c.Value) == code {
+				names = append(names, c.Name)
+			}
+		}
+	}
+	if len(names) == 1 {
+		data["name"] = names[0]
+	}
+	return data
+}
+
+// decodePrimitive renders the values which don't depend on the spec as xdrjson.ScValToJSON does,
+// without the type tags. Integers wider than 32 bits are rendered as JSON numbers.
+func decodePrimitive(val xdr.ScVal) interface{} {
+	rendered := xdrjson.ScValToJSON(val)
+	switch val.Type {
+	case xdr.ScValTypeScvU64, xdr.ScValTypeScvI64, xdr.ScValTypeScvTimepoint, xdr.ScValTypeScvDuration,
+		xdr.ScValTypeScvU128, xdr.ScValTypeScvI128, xdr.ScValTypeScvU256, xdr.ScValTypeScvI256,
+		xdr.ScValTypeScvLedgerKeyNonce:
+		return json.Number(rendered.Value.(string))
+	}
+	if rendered.Hex != "" {
+		return rendered.Hex
+	}
+	return rendered.Value
+}
+
+func symbolKeys(scMap xdr.ScMap) ([]string, bool) {
+	names := make([]string, 0, len(scMap))
+	for _, entry := range scMap {
+		sym, ok := entry.Key.GetSym()
+		if !ok {
+			return nil, false
+		}
+		names = append(names, string(sym))
+	}
+	return names, true
+}
+
+func fieldSetKey(names []string) string {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSpecEntries() []xdr.ScSpecEntry {
+	u32 := xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeU32}
+	i128 := xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeI128}
+	address := xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeAddress}
+	color := xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeUdt, Udt: &xdr.ScSpecTypeUdt{Name: "Color"}}
+	return []xdr.ScSpecEntry{
+		{
+			Kind: xdr.ScSpecEntryKindScSpecEntryUdtStructV0,
+			UdtStructV0: &xdr.ScSpecUdtStructV0{
+				Name: "Order",
+				Fields: []xdr.ScSpecUdtStructFieldV0{
+					{Name: "amount", Type: i128},
+					{Name: "color", Type: color},
+				},
+			},
+		},
+		{
+			Kind: xdr.ScSpecEntryKindScSpecEntryUdtUnionV0,
+			UdtUnionV0: &xdr.ScSpecUdtUnionV0{
+				Name: "DataKey",
+				Cases: []xdr.ScSpecUdtUnionCaseV0{
+					{
+						Kind:     xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseVoidV0,
+						VoidCase: &xdr.ScSpecUdtUnionCaseVoidV0{Name: "Admin"},
+					},
+					{
+						Kind:      xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseTupleV0,
+						TupleCase: &xdr.ScSpecUdtUnionCaseTupleV0{Name: "Balance", Type: []xdr.ScSpecTypeDef{address}},
+					},
+				},
+			},
+		},
+		{
+			Kind: xdr.ScSpecEntryKindScSpecEntryUdtEnumV0,
+			UdtEnumV0: &xdr.ScSpecUdtEnumV0{
+				Name:  "Color",
+				Cases: []xdr.ScSpecUdtEnumCaseV0{{Name: "Red", Value: 0}, {Name: "Blue", Value: 1}},
+			},
+		},
+		{
+			Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0,
+			FunctionV0: &xdr.ScSpecFunctionV0{
+				Name:    "paint",
+				Inputs:  []xdr.ScSpecFunctionInputV0{{Name: "color", Type: color}},
+				Outputs: []xdr.ScSpecTypeDef{u32},
+			},
+		},
+	}
+}
+
+func decodedJSON(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestSpecDecoder(t *testing.T) {
+	decoder := NewSpecDecoder(testSpecEntries())
+
+	sym := func(s string) xdr.ScVal {
+		v := xdr.ScSymbol(s)
+		return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &v}
+	}
+	u32 := func(n uint32) xdr.ScVal {
+		v := xdr.Uint32(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}
+	}
+	vec := func(items ...xdr.ScVal) xdr.ScVal {
+		v := xdr.ScVec(items)
+		pv := &v
+		return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &pv}
+	}
+	accountID := xdr.MustAddress("GBBD47IF6LWK7P7MDEVSCWR7DPUWV3NY3DTQEVFL4NAT4AQH3ZLLFLA5")
+	address := xdr.ScVal{
+		Type:    xdr.ScValTypeScvAddress,
+		Address: &xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &accountID},
+	}
+	amount := xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: -1, Lo: 0}}
+	order := xdr.ScMap{
+		{Key: sym("amount"), Val: amount},
+		{Key: sym("color"), Val: u32(1)},
+	}
+	pOrder := &order
+
+	// unions are inferred from their case names
+	assert.Equal(t, `"Admin"`, decodedJSON(t, decoder.Decode(vec(sym("Admin")))))
+	assert.Equal(t,
+		`{"Balance":"GBBD47IF6LWK7P7MDEVSCWR7DPUWV3NY3DTQEVFL4NAT4AQH3ZLLFLA5"}`,
+		decodedJSON(t, decoder.Decode(vec(sym("Balance"), address))),
+	)
+	// structs are inferred from their field names, and their fields are typed
+	assert.Equal(t,
+		`{"amount":-18446744073709551616,"color":"Blue"}`,
+		decodedJSON(t, decoder.Decode(xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &pOrder})),
+	)
+	// unknown vectors are kept as lists
+	assert.Equal(t, `["Other",1]`, decodedJSON(t, decoder.Decode(vec(sym("Other"), u32(1)))))
+
+	fn, ok := decoder.Function("paint")
+	require.True(t, ok)
+	assert.Equal(t, `"Red"`, decodedJSON(t, decoder.DecodeWithType(u32(0), fn.Inputs[0].Type)))
+	// values not matching the type fall back to inference
+	assert.Equal(t, `7`, decodedJSON(t, decoder.DecodeWithType(u32(7), fn.Inputs[0].Type)))
+	// including untrusted struct maps whose keys aren't field names
+	notOrder := xdr.ScMap{{Key: u32(1), Val: amount}}
+	pNotOrder := &notOrder
+	orderType := xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeUdt, Udt: &xdr.ScSpecTypeUdt{Name: "Order"}}
+	assert.NotPanics(t, func() {
+		decoded := decoder.DecodeWithType(xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &pNotOrder}, orderType)
+		assert.Equal(t, decoder.Decode(xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &pNotOrder}), decoded)
+	})
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	wasmSectionCustom = 0
+
+	ContractSpecSection    = "contractspecv0"
+	ContractMetaSection    = "contractmetav0"
+	ContractEnvMetaSection = "contractenvmetav0"
+)
+
+var wasmMagic = []byte{0x00, 0x61, 0x73, 0x6d}
+
+// GetWasmCustomSections walks the top-level sections of a WASM module and returns
+// the payloads of its custom sections, keyed by section name.
+// Sections appearing more than once are concatenated, which is how soroban
+// tooling treats repeated spec and meta sections.
+func GetWasmCustomSections(code []byte) (map[string][]byte, error) {
+	if len(code) < 8 || !bytes.Equal(code[0:4], wasmMagic) {
+		return nil, errors.New("invalid wasm module: bad magic")
+	}
+	if version := binary.LittleEndian.Uint32(code[4:8]); version != 1 {
+		return nil, fmt.Errorf("invalid wasm module: unsupported version %d", version)
+	}
+
+	sections := make(map[string][]byte)
+	pos := 8
+	for pos < len(code) {
+		id := code[pos]
+		pos++
+		size, n, err := readULEB128(code[pos:])
+		if err != nil {
+			return nil, err
+		}
+		pos += n
+		end := pos + int(size)
+		if end > len(code) || end < pos {
+			return nil, fmt.Errorf("invalid wasm module: section %d overflows module", id)
+		}
+		if id == wasmSectionCustom {
+			nameLen, n, err := readULEB128(code[pos:end])
+			if err != nil {
+				return nil, err
+			}
+			nameStart := pos + n
+			nameEnd := nameStart + int(nameLen)
+			if nameEnd > end || nameEnd < nameStart {
+				return nil, errors.New("invalid wasm module: custom section name overflows section")
+			}
+			name := string(code[nameStart:nameEnd])
+			sections[name] = append(sections[name], code[nameEnd:end]...)
+		}
+		pos = end
+	}
+	return sections, nil
+}
+
+func readULEB128(buf []byte) (uint32, int, error) {
+	var result uint32
+	var shift uint
+	for i, b := range buf {
+		if i >= 5 {
+			break
+		}
+		result |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return result, i + 1, nil
+		}
+		shift += 7
+	}
+	return 0, 0, errors.New("invalid wasm module: malformed LEB128 integer")
+}
@@ -2,14 +2,13 @@ package indexer
 
 import (
 	"encoding/base64"
-	"encoding/hex"
-	"math/big"
 	"strconv"
 
 	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/xdr"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model/util"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrjson"
 )
 
 func getInt128FromString(str string) (int128 util.Int128) {
@@ -131,7 +130,7 @@ func setCond(t *model.Transaction, cond xdr.Preconditions) {
 }
 
 func processTxV0Envelope(transaction *model.Transaction, txv0 xdr.TransactionV0Envelope) {
-	var fee = int32(txv0.Tx.Fee)
+	var fee = int64(txv0.Tx.Fee)
 	transaction.Fee = &fee
 	var sequence = int64(txv0.Tx.SeqNum)
 	transaction.Sequence = &sequence
@@ -156,7 +155,7 @@ func processTxV0Envelope(transaction *model.Transaction, txv0 xdr.TransactionV0E
 }
 
 func processTxV1Envelope(transaction *model.Transaction, txv1 xdr.TransactionV1Envelope) {
-	var fee = int32(txv1.Tx.Fee)
+	var fee = int64(txv1.Tx.Fee)
 	transaction.Fee = &fee
 	var sequence = int64(txv1.Tx.SeqNum)
 	transaction.Sequence = &sequence
@@ -175,7 +174,7 @@ func processTxFeeBumpEnvelope(transaction *model.Transaction, txFeeBump xdr.FeeB
 	processTxV1Envelope(transaction, *txFeeBump.Tx.InnerTx.V1)
 
 	var feeBumpInfo = util.FeeBumpInfo{
-		Fee: int32(txFeeBump.Tx.Fee),
+		Fee: int64(txFeeBump.Tx.Fee),
 	}
 
 	str, num := parseSourceAccount(txFeeBump.Tx.FeeSource)
@@ -185,132 +184,59 @@ func processTxFeeBumpEnvelope(transaction *model.Transaction, txFeeBump xdr.FeeB
 	transaction.FeeBumpInfo = &feeBumpInfo
 }
 
-func scMapToGo(scMap xdr.ScMap) interface{} {
-	var data []interface{}
-	for _, pair := range scMap {
-		item := make(map[string]interface{})
-		key := scValToGo(pair.Key)
-		// flatten single value slice
-		if val, ok := key.([]interface{}); ok {
-			if len(val) == 1 {
-				key = val[0]
-			}
-		}
-		value := scValToGo(pair.Val)
-		item["key"] = key
-		item["value"] = value
-		data = append(data, item)
+func getSorobanData(envelope xdr.TransactionEnvelope) (xdr.SorobanTransactionData, bool) {
+	switch envelope.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		return envelope.V1.Tx.Ext.GetSorobanData()
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		return envelope.FeeBump.Tx.InnerTx.V1.Tx.Ext.GetSorobanData()
 	}
-	return data
+	return xdr.SorobanTransactionData{}, false
 }
 
+// scValToGo renders an ScVal for indexing. It's the rendering of xdrjson.ScValToJSON
+// without the type tags, so that values are plain JSON values.
 func scValToGo(val xdr.ScVal) interface{} {
-	switch val.Type {
-	case xdr.ScValTypeScvBool:
-		return *val.B
-	case xdr.ScValTypeScvVoid:
-		return ""
-	case xdr.ScValTypeScvError:
-		data := make(map[string]interface{})
-		data["type"] = val.Error.Type.String()
-		data["code"] = val.Error.Code.String()
-		return data
-	case xdr.ScValTypeScvU32:
-		return uint32(*val.U32)
-	case xdr.ScValTypeScvI32:
-		return int32(*val.I32)
-	case xdr.ScValTypeScvU64:
-		return uint64(*val.U64)
-	case xdr.ScValTypeScvI64:
-		return int64(*val.I64)
-	case xdr.ScValTypeScvTimepoint:
-		return uint64(*val.Timepoint)
-	case xdr.ScValTypeScvDuration:
-		return uint64(*val.Duration)
-	case xdr.ScValTypeScvI128:
-		hi := big.NewInt(int64(val.I128.Hi))
-		lo := big.NewInt(0).SetUint64(uint64(val.I128.Lo))
-		hi.Lsh(hi, 64)
-		hi.Add(hi, lo)
-		return hi.String()
-	case xdr.ScValTypeScvU128:
-		hi := big.NewInt(0).SetUint64(uint64(val.U128.Hi))
-		lo := big.NewInt(0).SetUint64(uint64(val.U128.Lo))
-		hi.Lsh(hi, 64)
-		hi.Add(hi, lo)
-		return hi.String()
-	case xdr.ScValTypeScvI256:
-		hihi := big.NewInt(int64(val.I256.HiHi))
-		hilo := big.NewInt(0).SetUint64(uint64(val.I256.HiLo))
-		lohi := big.NewInt(0).SetUint64(uint64(val.I256.LoHi))
-		lolo := big.NewInt(0).SetUint64(uint64(val.I256.LoLo))
-		hihi.Lsh(hihi, 64)
-		hihi.Add(hihi, hilo)
-		hihi.Lsh(hihi, 64)
-		hihi.Add(hihi, lohi)
-		hihi.Lsh(hihi, 64)
-		hihi.Add(hihi, lolo)
-		return hihi.String()
-	case xdr.ScValTypeScvU256:
-		hihi := big.NewInt(0).SetUint64(uint64(val.U256.HiHi))
-		hilo := big.NewInt(0).SetUint64(uint64(val.U256.HiLo))
-		lohi := big.NewInt(0).SetUint64(uint64(val.U256.LoHi))
-		lolo := big.NewInt(0).SetUint64(uint64(val.U256.LoLo))
-		hihi.Lsh(hihi, 64)
-		hihi.Add(hihi, hilo)
-		hihi.Lsh(hihi, 64)
-		hihi.Add(hihi, lohi)
-		hihi.Lsh(hihi, 64)
-		hihi.Add(hihi, lolo)
-		return hihi.String()
-	case xdr.ScValTypeScvBytes:
-		return *val.Bytes
-	case xdr.ScValTypeScvString:
-		return string(*val.Str)
-	case xdr.ScValTypeScvSymbol:
-		return string(*val.Sym)
-	case xdr.ScValTypeScvVec:
-		scVec, _ := val.GetVec()
-		var list [](interface{})
-		// list := make([]interface{}, len(*scVec))
-		for _, value := range *scVec {
-			list = append(list, scValToGo(value))
+	return untagScValJSON(xdrjson.ScValToJSON(val))
+}
+
+func untagScValJSON(val xdrjson.ScValJSON) interface{} {
+	switch value := val.Value.(type) {
+	case []xdrjson.ScValJSON:
+		list := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			list = append(list, untagScValJSON(item))
 		}
 		return list
-	case xdr.ScValTypeScvMap:
-		scMap, _ := val.GetMap()
-		return scMapToGo(*scMap)
-	case xdr.ScValTypeScvAddress:
-		var address = ""
-		if val.Address.AccountId != nil {
-			address = val.Address.AccountId.Address()
-		} else if val.Address.ContractId != nil {
-			address = hex.EncodeToString(val.Address.ContractId[:])
-		}
-		return address
-	case xdr.ScValTypeScvLedgerKeyContractInstance:
-		return "ScvLedgerKeyContractInstance"
-	case xdr.ScValTypeScvLedgerKeyNonce:
-		return int64(val.NonceKey.Nonce)
-	case xdr.ScValTypeScvContractInstance:
-		if val.Instance == nil {
-			return nil
-		}
-		instance := *val.Instance
-		data := make(map[string]interface{})
-		executable := make(map[string]interface{})
-		data["executable"] = executable
-		executable["type"] = instance.Executable.Type.String()
-		if instance.Executable.WasmHash != nil {
-			executable["wasmHash"] = hex.EncodeToString(instance.Executable.WasmHash[:])
-		}
-		if instance.Storage != nil {
-			storage := scMapToGo(*instance.Storage)
-			data["storage"] = storage
+	case []xdrjson.ScMapEntryJSON:
+		return untagScMapJSON(value)
+	case xdrjson.ContractInstanceJSON:
+		data := map[string]interface{}{"executable": value.Executable}
+		if value.Storage != nil {
+			data["storage"] = untagScMapJSON(value.Storage)
 		}
-
 		return data
-	default:
-		return nil
 	}
+	if val.Hex != "" {
+		return val.Hex
+	}
+	return val.Value
+}
+
+func untagScMapJSON(entries []xdrjson.ScMapEntryJSON) interface{} {
+	data := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		key := untagScValJSON(entry.Key)
+		// flatten single value slice
+		if val, ok := key.([]interface{}); ok {
+			if len(val) == 1 {
+				key = val[0]
+			}
+		}
+		data = append(data, map[string]interface{}{
+			"key":   key,
+			"value": untagScValJSON(entry.Value),
+		})
+	}
+	return data
 }
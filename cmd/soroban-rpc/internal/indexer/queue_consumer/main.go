@@ -117,6 +117,46 @@ func main() {
 			if err != nil {
 				logger.WithError(err).Error("Error UpsertTokenOperation")
 			}
+		case indexer.Contract:
+			c, err := model.NewContract(decodedBytes)
+			if err != nil {
+				logger.WithError(err).Error("Error NewContract")
+				break
+			}
+			err = indexerService.UpsertContract(&c)
+			if err != nil {
+				logger.WithError(err).Error("Error UpsertContract")
+			}
+		case indexer.ContractUpgrade:
+			cu, err := model.NewContractUpgrade(decodedBytes)
+			if err != nil {
+				logger.WithError(err).Error("Error NewContractUpgrade")
+				break
+			}
+			err = indexerService.UpsertContractUpgrade(&cu)
+			if err != nil {
+				logger.WithError(err).Error("Error UpsertContractUpgrade")
+			}
+		case indexer.Invocation:
+			inv, err := model.NewInvocation(decodedBytes)
+			if err != nil {
+				logger.WithError(err).Error("Error NewInvocation")
+				break
+			}
+			err = indexerService.UpsertInvocation(&inv)
+			if err != nil {
+				logger.WithError(err).Error("Error UpsertInvocation")
+			}
+		case indexer.Ledger:
+			l, err := model.NewLedger(decodedBytes)
+			if err != nil {
+				logger.WithError(err).Error("Error NewLedger")
+				break
+			}
+			err = indexerService.UpsertLedger(&l)
+			if err != nil {
+				logger.WithError(err).Error("Error UpsertLedger")
+			}
 		}
 
 		processed++
@@ -24,8 +24,8 @@ func main() {
 		ApplicationOrder: new(int32),
 		FeeBump:          new(bool),
 		FeeBumpInfo:      &util.FeeBumpInfo{Fee: 1222},
-		Fee:              new(int32),
-		FeeCharged:       new(int32),
+		Fee:              new(int64),
+		FeeCharged:       new(int64),
 		Sequence:         new(int64),
 		SourceAccount:    new(string),
 		MuxedAccountId:   new(int64),
@@ -11,6 +11,7 @@ import (
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model/util"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"gorm.io/driver/postgres"
@@ -18,6 +19,7 @@ import (
 	golg "gorm.io/gorm/logger"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/parser"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/transactions"
 )
@@ -26,6 +28,15 @@ type Service struct {
 	logger    *log.Entry
 	indexerDB *gorm.DB
 	rdb       *redis.Client
+
+	// decodeWithSpec enables spec-aware decoding of events and contract data
+	decodeWithSpec bool
+	specDecoders   map[string]*parser.SpecDecoder // keyed by wasm hash
+	wasmHashes     map[string]string              // wasm hash of the contracts, keyed by contract id
+	specLock       sync.Mutex
+
+	// storeTxXdr enables storing the raw envelope, result and meta XDR of transactions
+	storeTxXdr bool
 }
 
 func (s *Service) scValXdrToJSON(str string) (string, error) {
@@ -54,16 +65,24 @@ func New(logger *log.Entry, rdb *redis.Client) *Service {
 	}
 
 	s := &Service{
-		indexerDB: db,
-		logger:    logger,
-		rdb:       rdb,
+		indexerDB:      db,
+		logger:         logger,
+		rdb:            rdb,
+		decodeWithSpec: os.Getenv("INDEXER_DECODE_WITH_SPEC") == "true",
+		specDecoders:   make(map[string]*parser.SpecDecoder),
+		wasmHashes:     make(map[string]string),
+		storeTxXdr:     os.Getenv("INDEXER_STORE_TX_XDR") == "true",
 	}
 
 	return s
 }
 
 func (s *Service) EnqueueEvent(ev events.EventInfoRaw) {
-	info, err := methods.NewEventInfoForEvent(ev.Event, ev.Cursor, ev.LedgerClosedAt, "") // don't need hash info
+	var txHash string
+	if ev.TxHash != nil {
+		txHash = ev.TxHash.HexString()
+	}
+	info, err := methods.NewEventInfoForEvent(ev.Event, ev.Cursor, ev.LedgerClosedAt, txHash)
 	if err != nil {
 		return
 	}
@@ -84,6 +103,8 @@ func (s *Service) EnqueueEvent(ev events.EventInfoRaw) {
 	event := model.Event{
 		ID:                       info.ID,
 		TxIndex:                  int32(ev.Cursor.Tx),
+		TxHash:                   info.TransactionHash,
+		OpIndex:                  int32(ev.OpIndex),
 		EventType:                info.EventType,
 		Ledger:                   info.Ledger,
 		LedgerClosedAt:           info.LedgerClosedAt,
@@ -92,16 +113,26 @@ func (s *Service) EnqueueEvent(ev events.EventInfoRaw) {
 		Topic:                    topicData,
 		Value:                    value,
 		InSuccessfulContractCall: info.InSuccessfulContractCall,
+		TopicXdr:                 info.Topic,
+		ValueXdr:                 info.Value,
 	}
 	s.enqueueTokenOperation(topic, value, event)
 	s.enqueueEvent(event)
 }
 
 func (s *Service) UpsertEvent(event *model.Event) error {
-	return model.UpsertEvent(s.indexerDB, event)
+	if s.decodeWithSpec {
+		s.decodeEvent(event)
+	}
+	if err := model.UpsertEvent(s.indexerDB, event); err != nil {
+		return err
+	}
+	return model.UpsertParticipants(s.indexerDB, getEventParticipants(event))
 }
 
-func (s *Service) MarshalTransaction(hash string, info methods.GetTransactionResponse, tx transactions.Transaction) string {
+// MarshalTransaction encodes a transaction and its operations for the queue.
+// The Soroban resource fee breakdown is only computed when feeConfig is provided.
+func (s *Service) MarshalTransaction(hash string, info methods.GetTransactionResponse, tx transactions.Transaction, feeConfig *SorobanFeeConfig) string {
 	transaction := model.Transaction{
 		ID:     hash,
 		Status: info.Status,
@@ -128,8 +159,31 @@ func (s *Service) MarshalTransaction(hash string, info methods.GetTransactionRes
 	result := xdr.TransactionResult{}
 	result.UnmarshalBinary(tx.Result)
 
-	var feeCharged = int32(result.FeeCharged)
+	var feeCharged = int64(result.FeeCharged)
 	transaction.FeeCharged = &feeCharged
+	var resultCode = result.Result.Code.String()
+	transaction.ResultCode = &resultCode
+	if innerResultPair, ok := result.Result.GetInnerResultPair(); ok {
+		var innerResultCode = innerResultPair.Result.Result.Code.String()
+		transaction.InnerResultCode = &innerResultCode
+	}
+	var operationCount = int32(len(envelope.Operations()))
+	transaction.OperationCount = &operationCount
+
+	meta := xdr.TransactionMeta{}
+	meta.UnmarshalBinary(tx.Meta)
+	setSorobanFees(&transaction, envelope, meta, feeConfig)
+
+	if s.storeTxXdr {
+		envelopeXdr := base64.StdEncoding.EncodeToString(tx.Envelope)
+		resultXdr := base64.StdEncoding.EncodeToString(tx.Result)
+		metaXdr := base64.StdEncoding.EncodeToString(tx.Meta)
+		transaction.EnvelopeXdr = &envelopeXdr
+		transaction.ResultXdr = &resultXdr
+		transaction.MetaXdr = &metaXdr
+	}
+	transaction.Operations = getOperations(&transaction, envelope, result)
+	transaction.Participants = getTransactionParticipants(&transaction, envelope)
 
 	jsonDataPretty, err := json.Marshal(transaction)
 	if err != nil {
@@ -139,7 +193,13 @@ func (s *Service) MarshalTransaction(hash string, info methods.GetTransactionRes
 }
 
 func (s *Service) UpsertTransaction(transaction *model.Transaction) error {
-	return model.UpsertTransaction(s.indexerDB, transaction)
+	if err := model.UpsertTransaction(s.indexerDB, transaction); err != nil {
+		return err
+	}
+	if err := model.UpsertOperations(s.indexerDB, transaction.Operations); err != nil {
+		return err
+	}
+	return model.UpsertParticipants(s.indexerDB, transaction.Participants)
 }
 
 func (s *Service) enqueueTokenMetadata(tm model.TokenMetadata) {
@@ -179,15 +239,22 @@ func (s *Service) enqueueTokenOp(op model.TokenOperation) {
 }
 
 func (s *Service) UpsertTokenOperation(to *model.TokenOperation) error {
-	return model.UpsertTokenOperation(s.indexerDB, to)
+	if err := model.UpsertTokenOperation(s.indexerDB, to); err != nil {
+		return err
+	}
+	return model.UpsertParticipants(s.indexerDB, getTokenOperationParticipants(to))
 }
 
 // key: "change_queue" value: "${number}:${base64encoded}"
 const (
-	QueueKey       = "change_queue"
-	LedgerEntry    = "1"
-	Tx             = "2"
-	TokenMetadata  = "3"
-	Event          = "4"
-	TokenOperation = "5"
+	QueueKey        = "change_queue"
+	LedgerEntry     = "1"
+	Tx              = "2"
+	TokenMetadata   = "3"
+	Event           = "4"
+	TokenOperation  = "5"
+	Contract        = "6"
+	ContractUpgrade = "7"
+	Invocation      = "8"
+	Ledger          = "9"
 )
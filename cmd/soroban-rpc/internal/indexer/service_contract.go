@@ -0,0 +1,213 @@
+package indexer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/transactions"
+)
+
+// EnqueueContracts derives contract deployments and executable upgrades from a successful transaction.
+// Deployments are matched with the CreateContract host functions found in the envelope (either
+// invoked directly or authorized as sub-invocations) to recover the deployer and salt. Contracts
+// deployed by other contracts without authorization are still indexed, without creation info.
+func (s *Service) EnqueueContracts(networkPassphrase string, hash string, info methods.GetTransactionResponse, tx transactions.Transaction) {
+	if info.Status != methods.TransactionStatusSuccess {
+		return
+	}
+
+	var envelope xdr.TransactionEnvelope
+	if err := envelope.UnmarshalBinary(tx.Envelope); err != nil {
+		s.logger.WithError(err).Error("error cannot unmarshal tx envelope " + hash)
+		return
+	}
+	ledgerTx := ingest.LedgerTransaction{Envelope: envelope}
+	if err := ledgerTx.UnsafeMeta.UnmarshalBinary(tx.Meta); err != nil {
+		s.logger.WithError(err).Error("error cannot unmarshal tx meta " + hash)
+		return
+	}
+	changes, err := ledgerTx.GetChanges()
+	if err != nil {
+		s.logger.WithError(err).Error("error cannot get tx changes " + hash)
+		return
+	}
+
+	preimages := getContractIdPreimages(networkPassphrase, envelope)
+	now := time.Now()
+	for _, change := range changes {
+		if change.Type != xdr.LedgerEntryTypeContractData || change.Post == nil {
+			continue
+		}
+		contractData := change.Post.Data.MustContractData()
+		if contractData.Key.Type != xdr.ScValTypeScvLedgerKeyContractInstance || contractData.Contract.ContractId == nil {
+			continue
+		}
+		contractID := strkey.MustEncode(strkey.VersionByteContract, contractData.Contract.ContractId[:])
+		executable := contractData.Val.MustInstance().Executable
+
+		contract := model.Contract{
+			ContractID:         contractID,
+			LastModifiedLedger: info.Ledger,
+		}
+		contract.UpdatedAt = now
+		contract.CreatedAt = now
+		setContractExecutable(&contract, executable)
+
+		if change.Pre == nil {
+			// deployment
+			contract.CreatedLedger = &info.Ledger
+			contract.CreatedTxHash = &hash
+			if preimage, ok := preimages[*contractData.Contract.ContractId]; ok {
+				setContractPreimage(&contract, preimage)
+			}
+			s.enqueueContract(contract)
+			continue
+		}
+
+		oldExecutable := change.Pre.Data.MustContractData().Val.MustInstance().Executable
+		if oldExecutable.Type == executable.Type && (executable.WasmHash == nil || *oldExecutable.WasmHash == *executable.WasmHash) {
+			// storage change only
+			continue
+		}
+		upgrade := model.ContractUpgrade{
+			TxHash:      hash,
+			ContractID:  contractID,
+			Ledger:      info.Ledger,
+			OldWasmHash: wasmHashToString(oldExecutable),
+			NewWasmHash: contract.WasmHash,
+		}
+		upgrade.CreatedAt = now
+		upgrade.UpdatedAt = now
+		s.enqueueContract(contract)
+		s.enqueueContractUpgrade(upgrade)
+	}
+}
+
+func (s *Service) UpsertContract(contract *model.Contract) error {
+	if err := model.UpsertContract(s.indexerDB, contract); err != nil {
+		return err
+	}
+	// upgrades change the spec of the contract
+	s.setContractWasmHash(contract)
+	return nil
+}
+
+func (s *Service) UpsertContractUpgrade(upgrade *model.ContractUpgrade) error {
+	return model.UpsertContractUpgrade(s.indexerDB, upgrade)
+}
+
+func (s *Service) enqueueContract(contract model.Contract) {
+	jsonData, err := json.Marshal(contract)
+	if err != nil {
+		s.logger.WithError(err).Error("error cannot marshal contract")
+	}
+	marshaled := base64.StdEncoding.EncodeToString(jsonData)
+	err = s.rdb.RPush(context.Background(), QueueKey, Contract+":"+marshaled).Err()
+	if err != nil {
+		s.logger.WithError(err).Error("error push contract")
+	}
+}
+
+func (s *Service) enqueueContractUpgrade(upgrade model.ContractUpgrade) {
+	jsonData, err := json.Marshal(upgrade)
+	if err != nil {
+		s.logger.WithError(err).Error("error cannot marshal contract upgrade")
+	}
+	marshaled := base64.StdEncoding.EncodeToString(jsonData)
+	err = s.rdb.RPush(context.Background(), QueueKey, ContractUpgrade+":"+marshaled).Err()
+	if err != nil {
+		s.logger.WithError(err).Error("error push contract_upgrade")
+	}
+}
+
+func setContractExecutable(contract *model.Contract, executable xdr.ContractExecutable) {
+	switch executable.Type {
+	case xdr.ContractExecutableTypeContractExecutableWasm:
+		contract.ExecutableType = model.ContractExecutableWasm
+		contract.WasmHash = wasmHashToString(executable)
+	case xdr.ContractExecutableTypeContractExecutableStellarAsset:
+		contract.ExecutableType = model.ContractExecutableStellarAsset
+	}
+}
+
+func setContractPreimage(contract *model.Contract, preimage xdr.ContractIdPreimage) {
+	switch preimage.Type {
+	case xdr.ContractIdPreimageTypeContractIdPreimageFromAddress:
+		if deployer, err := preimage.FromAddress.Address.String(); err == nil {
+			contract.Deployer = &deployer
+		}
+		salt := hex.EncodeToString(preimage.FromAddress.Salt[:])
+		contract.Salt = &salt
+	case xdr.ContractIdPreimageTypeContractIdPreimageFromAsset:
+		asset := preimage.FromAsset.StringCanonical()
+		contract.Asset = &asset
+	}
+}
+
+func wasmHashToString(executable xdr.ContractExecutable) *string {
+	if executable.WasmHash == nil {
+		return nil
+	}
+	wasmHash := hex.EncodeToString(executable.WasmHash[:])
+	return &wasmHash
+}
+
+// getContractIdPreimages collects the contract id preimages of all the CreateContract host functions
+// in the envelope, keyed by the contract id they derive.
+func getContractIdPreimages(networkPassphrase string, envelope xdr.TransactionEnvelope) map[xdr.Hash]xdr.ContractIdPreimage {
+	preimages := make(map[xdr.Hash]xdr.ContractIdPreimage)
+	add := func(preimage xdr.ContractIdPreimage) {
+		if contractID, err := contractIDFromPreimage(networkPassphrase, preimage); err == nil {
+			preimages[contractID] = preimage
+		}
+	}
+
+	var walk func(invocation xdr.SorobanAuthorizedInvocation)
+	walk = func(invocation xdr.SorobanAuthorizedInvocation) {
+		if args, ok := invocation.Function.GetCreateContractHostFn(); ok {
+			add(args.ContractIdPreimage)
+		}
+		for _, sub := range invocation.SubInvocations {
+			walk(sub)
+		}
+	}
+
+	for _, op := range envelope.Operations() {
+		invokeOp, ok := op.Body.GetInvokeHostFunctionOp()
+		if !ok {
+			continue
+		}
+		if args, ok := invokeOp.HostFunction.GetCreateContract(); ok {
+			add(args.ContractIdPreimage)
+		}
+		for _, auth := range invokeOp.Auth {
+			walk(auth.RootInvocation)
+		}
+	}
+	return preimages
+}
+
+func contractIDFromPreimage(networkPassphrase string, preimage xdr.ContractIdPreimage) (xdr.Hash, error) {
+	hashIdPreimage := xdr.HashIdPreimage{
+		Type: xdr.EnvelopeTypeEnvelopeTypeContractId,
+		ContractId: &xdr.HashIdPreimageContractId{
+			NetworkId:          sha256.Sum256([]byte(networkPassphrase)),
+			ContractIdPreimage: preimage,
+		},
+	}
+	bin, err := hashIdPreimage.MarshalBinary()
+	if err != nil {
+		return xdr.Hash{}, err
+	}
+	return sha256.Sum256(bin), nil
+}
@@ -0,0 +1,266 @@
+package indexer
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/transactions"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrjson"
+)
+
+// EnqueueInvocations parses the InvokeHostFunction operations of a transaction into invocations.
+// Each operation yields its host function call, followed by the sub-invocations found in its
+// auth entries. Auth trees rooted at the host function call itself are merged into it.
+func (s *Service) EnqueueInvocations(networkPassphrase string, hash string, info methods.GetTransactionResponse, tx transactions.Transaction) {
+	var envelope xdr.TransactionEnvelope
+	if err := envelope.UnmarshalBinary(tx.Envelope); err != nil {
+		s.logger.WithError(err).Error("error cannot unmarshal tx envelope " + hash)
+		return
+	}
+
+	var returnValue *xdr.ScVal
+	var metaV3 *xdr.TransactionMetaV3
+	var meta xdr.TransactionMeta
+	if err := meta.UnmarshalBinary(tx.Meta); err == nil {
+		if v3, ok := meta.GetV3(); ok {
+			metaV3 = &v3
+			if v3.SorobanMeta != nil {
+				returnValue = &v3.SorobanMeta.ReturnValue
+			}
+		}
+	}
+
+	now := time.Now()
+	base := model.Invocation{
+		TxHash:     hash,
+		Ledger:     info.Ledger,
+		Successful: info.Status == methods.TransactionStatusSuccess,
+	}
+	base.CreatedAt = now
+	base.UpdatedAt = now
+	if metaV3 != nil {
+		setInvocationResources(&base, envelope, *metaV3)
+	}
+
+	for opIndex, op := range envelope.Operations() {
+		invokeOp, ok := op.Body.GetInvokeHostFunctionOp()
+		if !ok {
+			continue
+		}
+		source := envelope.SourceAccount()
+		if op.SourceAccount != nil {
+			source = *op.SourceAccount
+		}
+		caller, _ := parseSourceAccount(source)
+
+		var invocations []model.Invocation
+		add := func(invocation model.Invocation, parentIndex *int32) int32 {
+			index := int32(len(invocations))
+			invocation.ID = fmt.Sprintf("%s-%d-%d", hash, opIndex, index)
+			invocation.OpIndex = int32(opIndex)
+			invocation.InvocationIndex = index
+			invocation.ParentIndex = parentIndex
+			invocations = append(invocations, invocation)
+			return index
+		}
+
+		root := base
+		root.Caller = caller
+		setHostFunction(&root, networkPassphrase, invokeOp.HostFunction)
+		if returnValue != nil {
+			root.ReturnValue = toJSON(scValToGo(*returnValue))
+			root.ReturnValueXdr, _ = xdr.MarshalBase64(*returnValue)
+		}
+		rootIndex := add(root, nil)
+
+		var walk func(authorized xdr.SorobanAuthorizedInvocation, parentIndex int32, parentContract *string, authorizer *string)
+		walk = func(authorized xdr.SorobanAuthorizedInvocation, parentIndex int32, parentContract *string, authorizer *string) {
+			invocation := base
+			invocation.Caller = parentContract
+			invocation.Authorizer = authorizer
+			setAuthorizedFunction(&invocation, networkPassphrase, authorized.Function)
+			index := add(invocation, &parentIndex)
+			for _, sub := range authorized.SubInvocations {
+				walk(sub, index, invocation.ContractID, authorizer)
+			}
+		}
+		for _, auth := range invokeOp.Auth {
+			authorizer := caller
+			if credentials, ok := auth.Credentials.GetAddress(); ok {
+				if address, err := credentials.Address.String(); err == nil {
+					authorizer = &address
+				}
+			}
+			if isHostFunction(auth.RootInvocation.Function, invokeOp.HostFunction) {
+				for _, sub := range auth.RootInvocation.SubInvocations {
+					walk(sub, rootIndex, root.ContractID, authorizer)
+				}
+			} else {
+				walk(auth.RootInvocation, rootIndex, root.ContractID, authorizer)
+			}
+		}
+
+		for _, invocation := range invocations {
+			s.enqueueInvocation(invocation)
+		}
+	}
+}
+
+// getCoreMetrics returns the core metrics of a transaction by name, which are only
+// present if stellar-core emits diagnostic events
+func getCoreMetrics(sorobanMeta *xdr.SorobanTransactionMeta) map[string]uint64 {
+	metrics := map[string]uint64{}
+	if sorobanMeta == nil {
+		return metrics
+	}
+	for _, event := range sorobanMeta.DiagnosticEvents {
+		body, ok := event.Event.Body.GetV0()
+		if !ok {
+			continue
+		}
+		if metric, value, ok := xdrjson.CoreMetric(body); ok {
+			metrics[metric] = value
+		}
+	}
+	return metrics
+}
+
+// setInvocationResources sets the resources used by the transaction, taken from its meta:
+// the instructions and ledger bytes come from the core metrics, and the resource fee
+// is the declared one minus the refund.
+func setInvocationResources(invocation *model.Invocation, envelope xdr.TransactionEnvelope, meta xdr.TransactionMetaV3) {
+	metrics := getCoreMetrics(meta.SorobanMeta)
+	if instructions, ok := metrics[xdrjson.CPUInsnMetric]; ok {
+		invocation.Instructions = &instructions
+	}
+	if readBytes, ok := metrics[xdrjson.LedgerReadByteMetric]; ok {
+		invocation.ReadBytes = &readBytes
+	}
+	if writeBytes, ok := metrics[xdrjson.LedgerWriteByteMetric]; ok {
+		invocation.WriteBytes = &writeBytes
+	}
+	if sorobanData, ok := getSorobanData(envelope); ok {
+		resourceFee := int64(sorobanData.ResourceFee) - getFeeRefund(envelope, meta.TxChangesAfter)
+		invocation.ResourceFee = &resourceFee
+	}
+}
+
+func (s *Service) UpsertInvocation(invocation *model.Invocation) error {
+	if s.decodeWithSpec {
+		s.decodeInvocation(invocation)
+	}
+	return model.UpsertInvocation(s.indexerDB, invocation)
+}
+
+func (s *Service) decodeInvocation(invocation *model.Invocation) {
+	if invocation.ContractID == nil || invocation.FunctionName == nil {
+		return
+	}
+	decoder := s.getSpecDecoder(*invocation.ContractID)
+	if decoder == nil {
+		return
+	}
+	fn, ok := decoder.Function(*invocation.FunctionName)
+	if !ok || len(fn.Inputs) != len(invocation.ArgsXdr) {
+		return
+	}
+	args := make(map[string]interface{}, len(fn.Inputs))
+	for i, input := range fn.Inputs {
+		var arg xdr.ScVal
+		if err := xdr.SafeUnmarshalBase64(invocation.ArgsXdr[i], &arg); err != nil {
+			return
+		}
+		args[input.Name] = decoder.DecodeWithType(arg, input.Type)
+	}
+	invocation.DecodedArgs = toJSON(args)
+
+	if invocation.ReturnValueXdr != "" && len(fn.Outputs) == 1 {
+		var returnValue xdr.ScVal
+		if err := xdr.SafeUnmarshalBase64(invocation.ReturnValueXdr, &returnValue); err == nil {
+			invocation.DecodedReturnValue = toJSON(decoder.DecodeWithType(returnValue, fn.Outputs[0]))
+		}
+	}
+}
+
+func (s *Service) enqueueInvocation(invocation model.Invocation) {
+	jsonData, err := json.Marshal(invocation)
+	if err != nil {
+		s.logger.WithError(err).Error("error cannot marshal invocation")
+	}
+	marshaled := base64.StdEncoding.EncodeToString(jsonData)
+	err = s.rdb.RPush(context.Background(), QueueKey, Invocation+":"+marshaled).Err()
+	if err != nil {
+		s.logger.WithError(err).Error("error push invocation")
+	}
+}
+
+func setHostFunction(invocation *model.Invocation, networkPassphrase string, hostFunction xdr.HostFunction) {
+	switch hostFunction.Type {
+	case xdr.HostFunctionTypeHostFunctionTypeInvokeContract:
+		setInvokeContractArgs(invocation, *hostFunction.InvokeContract)
+	case xdr.HostFunctionTypeHostFunctionTypeCreateContract:
+		setCreateContractArgs(invocation, networkPassphrase, *hostFunction.CreateContract)
+	case xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm:
+		invocation.HostFunctionType = model.HostFunctionUploadWasm
+	}
+}
+
+func setAuthorizedFunction(invocation *model.Invocation, networkPassphrase string, function xdr.SorobanAuthorizedFunction) {
+	switch function.Type {
+	case xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn:
+		setInvokeContractArgs(invocation, *function.ContractFn)
+	case xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeCreateContractHostFn:
+		setCreateContractArgs(invocation, networkPassphrase, *function.CreateContractHostFn)
+	}
+}
+
+func setInvokeContractArgs(invocation *model.Invocation, args xdr.InvokeContractArgs) {
+	invocation.HostFunctionType = model.HostFunctionInvokeContract
+	if contractID, err := args.ContractAddress.String(); err == nil {
+		invocation.ContractID = &contractID
+	}
+	functionName := string(args.FunctionName)
+	invocation.FunctionName = &functionName
+
+	values := make([]interface{}, 0, len(args.Args))
+	argsXdr := make([]string, 0, len(args.Args))
+	for _, arg := range args.Args {
+		values = append(values, scValToGo(arg))
+		argXdr, _ := xdr.MarshalBase64(arg)
+		argsXdr = append(argsXdr, argXdr)
+	}
+	invocation.Args = toJSON(values)
+	invocation.ArgsXdr = argsXdr
+}
+
+func setCreateContractArgs(invocation *model.Invocation, networkPassphrase string, args xdr.CreateContractArgs) {
+	invocation.HostFunctionType = model.HostFunctionCreateContract
+	if contractID, err := contractIDFromPreimage(networkPassphrase, args.ContractIdPreimage); err == nil {
+		encoded := strkey.MustEncode(strkey.VersionByteContract, contractID[:])
+		invocation.ContractID = &encoded
+	}
+}
+
+// isHostFunction tells whether an authorized function is the call made by the host function itself
+func isHostFunction(function xdr.SorobanAuthorizedFunction, hostFunction xdr.HostFunction) bool {
+	var a, b interface{}
+	switch {
+	case function.ContractFn != nil && hostFunction.InvokeContract != nil:
+		a, b = function.ContractFn, hostFunction.InvokeContract
+	case function.CreateContractHostFn != nil && hostFunction.CreateContract != nil:
+		a, b = function.CreateContractHostFn, hostFunction.CreateContract
+	default:
+		return false
+	}
+	aXdr, errA := xdr.MarshalBase64(a)
+	bXdr, errB := xdr.MarshalBase64(b)
+	return errA == nil && errB == nil && aXdr == bXdr
+}
@@ -0,0 +1,93 @@
+package indexer
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model/util"
+)
+
+// maxLedgersRange caps the amount of ledgers returned by a single GetLedgers query
+const maxLedgersRange = 10000
+
+// EnqueueLedger queues the header and transaction counts of a closed ledger.
+// The Soroban fee config is only stored when provided.
+func (s *Service) EnqueueLedger(ledgerCloseMeta xdr.LedgerCloseMeta, feeConfig *SorobanFeeConfig) {
+	s.enqueueLedger(newLedger(ledgerCloseMeta, feeConfig))
+}
+
+func newLedger(ledgerCloseMeta xdr.LedgerCloseMeta, feeConfig *SorobanFeeConfig) model.Ledger {
+	header := ledgerCloseMeta.LedgerHeaderHistoryEntry().Header
+	closeTime := time.Unix(int64(header.ScpValue.CloseTime), 0).UTC()
+	ledger := model.Ledger{
+		Sequence:        ledgerCloseMeta.LedgerSequence(),
+		Hash:            ledgerCloseMeta.LedgerHash().HexString(),
+		PreviousHash:    ledgerCloseMeta.PreviousLedgerHash().HexString(),
+		CloseTime:       closeTime,
+		ProtocolVersion: ledgerCloseMeta.ProtocolVersion(),
+		BaseFee:         uint32(header.BaseFee),
+		BaseReserve:     uint32(header.BaseReserve),
+		MaxTxSetSize:    uint32(header.MaxTxSetSize),
+		Ts: util.Ts{
+			CreatedAt: closeTime,
+			UpdatedAt: time.Now(),
+		},
+	}
+
+	for i := 0; i < ledgerCloseMeta.CountTransactions(); i++ {
+		ledger.TransactionCount++
+		if ledgerCloseMeta.TransactionResultPair(i).Result.Successful() {
+			ledger.SuccessfulTransactionCount++
+		} else {
+			ledger.FailedTransactionCount++
+		}
+	}
+	for _, envelope := range ledgerCloseMeta.TransactionEnvelopes() {
+		ledger.OperationCount += int32(len(envelope.Operations()))
+	}
+	if feeConfig != nil {
+		ledger.SorobanFeeConfig = toJSON(feeConfig)
+	}
+	return ledger
+}
+
+func (s *Service) enqueueLedger(ledger model.Ledger) {
+	jsonData, err := json.Marshal(ledger)
+	if err != nil {
+		s.logger.WithError(err).Error("error cannot marshal ledger")
+	}
+	marshaled := base64.StdEncoding.EncodeToString(jsonData)
+	err = s.rdb.RPush(context.Background(), QueueKey, Ledger+":"+marshaled).Err()
+	if err != nil {
+		s.logger.WithError(err).Error("error push ledger")
+	}
+}
+
+func (s *Service) UpsertLedger(ledger *model.Ledger) error {
+	return model.UpsertLedger(s.indexerDB, ledger)
+}
+
+// validateLedgerRange checks a [startLedger, endLedger] range of a GetLedgers query
+func validateLedgerRange(startLedger uint32, endLedger uint32) error {
+	if startLedger > endLedger {
+		return fmt.Errorf("start ledger (%d) must not be after end ledger (%d)", startLedger, endLedger)
+	}
+	if endLedger-startLedger >= maxLedgersRange {
+		return fmt.Errorf("ledger range must not exceed %d ledgers", maxLedgersRange)
+	}
+	return nil
+}
+
+// GetLedgers returns the indexed ledgers within [startLedger, endLedger], in ascending order.
+func (s *Service) GetLedgers(ctx context.Context, startLedger uint32, endLedger uint32) ([]model.Ledger, error) {
+	if err := validateLedgerRange(startLedger, endLedger); err != nil {
+		return nil, err
+	}
+	return model.GetLedgers(s.indexerDB.WithContext(ctx), startLedger, endLedger)
+}
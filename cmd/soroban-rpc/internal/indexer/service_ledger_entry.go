@@ -98,6 +98,9 @@ func (s *Service) UpsertLedgerEntry(entry xdr.LedgerEntry) error {
 		if err := s.indexerDB.Model(&model.ContractDataEntry{}).Where(&model.ContractDataEntry{KeyHash: searchKey}).UpdateColumn("expiration_ledger_seq", entry.Data.Ttl.LiveUntilLedgerSeq).Error; err != nil {
 			return errors.Wrap(err, "failed to update ContractData Expiry: "+searchKey)
 		}
+		if err := s.indexerDB.Model(&model.ContractCodeEntry{}).Where(&model.ContractCodeEntry{KeyHash: searchKey}).UpdateColumn("expiration_ledger_seq", entry.Data.Ttl.LiveUntilLedgerSeq).Error; err != nil {
+			return errors.Wrap(err, "failed to update ContractCode Expiry: "+searchKey)
+		}
 	}
 
 	if key.ContractData != nil {
@@ -113,6 +116,12 @@ func (s *Service) UpsertLedgerEntry(entry xdr.LedgerEntry) error {
 			em.Key = key
 			val, _ := s.scValToJSON(entry.Data.ContractData.Val)
 			em.Val = val
+			if s.decodeWithSpec {
+				if decoder := s.getSpecDecoder(em.ContractId); decoder != nil {
+					em.DecodedKey = toJSON(decoder.Decode(entry.Data.ContractData.Key))
+					em.DecodedVal = toJSON(decoder.Decode(entry.Data.ContractData.Val))
+				}
+			}
 
 			s.UpsertTokenBalance(em.ContractId, key, val)
 			s.UpsertTokenMetadata(em.ContractId, key, val)
@@ -127,6 +136,17 @@ func (s *Service) UpsertLedgerEntry(entry xdr.LedgerEntry) error {
 		}
 	}
 
+	if key.ContractCode != nil {
+		em := parser.GetContractCodeModel(entry)
+		em.KeyHash = hexKey
+		if em.CreatedAt == (time.Time{}) {
+			em.CreatedAt = time.Now()
+		}
+		if err := model.UpsertContractCodeEntry(s.indexerDB, em); err != nil {
+			return errors.Wrap(err, "failed to upsert ContractCodeEntry")
+		}
+	}
+
 	if key.Account != nil {
 		em := parser.GetAccountEntryModel(entry)
 		if err := model.UpsertAccountEntry(s.indexerDB, em); err != nil {
@@ -0,0 +1,156 @@
+package indexer
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model"
+)
+
+func getOperations(transaction *model.Transaction, envelope xdr.TransactionEnvelope, result xdr.TransactionResult) []model.Operation {
+	opResults, hasResults := result.OperationResults()
+	ops := envelope.Operations()
+	operations := make([]model.Operation, 0, len(ops))
+	for i, op := range ops {
+		source := envelope.SourceAccount()
+		if op.SourceAccount != nil {
+			source = *op.SourceAccount
+		}
+		sourceAccount, muxedAccountId := parseSourceAccount(source)
+		operation := model.Operation{
+			ID:             fmt.Sprintf("%s-%d", transaction.ID, i),
+			TxHash:         transaction.ID,
+			OpIndex:        int32(i),
+			Ledger:         transaction.Ledger,
+			Type:           operationTypeName(op.Body.Type),
+			SourceAccount:  sourceAccount,
+			MuxedAccountId: muxedAccountId,
+			Body:           toJSON(xdrToGo(reflect.ValueOf(op.Body))),
+			Ts:             transaction.Ts,
+		}
+		if hasResults && i < len(opResults) {
+			code := operationResultCode(opResults[i])
+			operation.ResultCode = &code
+		}
+		operations = append(operations, operation)
+	}
+	return operations
+}
+
+// operationTypeName turns e.g. OperationTypePathPaymentStrictSend into path_payment_strict_send
+func operationTypeName(t xdr.OperationType) string {
+	return toSnakeCase(strings.TrimPrefix(t.String(), "OperationType"))
+}
+
+// operationResultCode returns the operation specific result code (e.g. PaymentResultCodePaymentUnderfunded)
+// for executed operations, and the generic one (e.g. OperationResultCodeOpNoAccount) otherwise.
+func operationResultCode(result xdr.OperationResult) string {
+	if result.Code != xdr.OperationResultCodeOpInner || result.Tr == nil {
+		return result.Code.String()
+	}
+	// the operation result union has a single non-nil arm, which holds the code
+	tr := reflect.ValueOf(*result.Tr)
+	for i := 0; i < tr.NumField(); i++ {
+		arm := tr.Field(i)
+		if arm.Kind() != reflect.Ptr || arm.IsNil() {
+			continue
+		}
+		if code := arm.Elem().FieldByName("Code"); code.IsValid() {
+			if stringer, ok := code.Interface().(fmt.Stringer); ok {
+				return stringer.String()
+			}
+		}
+	}
+	return result.Code.String()
+}
+
+// xdrToGo renders an XDR value as plain JSON-friendly data.
+// Account ids, assets, addresses and signer keys are rendered in their string form,
+// byte arrays as hex, enums by name and unions only include their active arm.
+func xdrToGo(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch value := v.Interface().(type) {
+	case xdr.AccountId:
+		return value.Address()
+	case xdr.MuxedAccount:
+		return value.Address()
+	case xdr.SignerKey:
+		return value.Address()
+	case xdr.Asset:
+		return value.StringCanonical()
+	case xdr.ChangeTrustAsset:
+		if value.Type != xdr.AssetTypeAssetTypePoolShare {
+			return value.ToAsset().StringCanonical()
+		}
+	case xdr.TrustLineAsset:
+		if value.Type != xdr.AssetTypeAssetTypePoolShare {
+			return value.ToAsset().StringCanonical()
+		}
+	case xdr.ScVal:
+		return scValToGo(value)
+	case xdr.ScAddress:
+		if address, err := value.String(); err == nil {
+			return address
+		}
+	case xdr.AssetCode4:
+		return strings.TrimRight(string(value[:]), "\x00")
+	case xdr.AssetCode12:
+		return strings.TrimRight(string(value[:]), "\x00")
+	case fmt.Stringer:
+		// enums
+		if v.Kind() == reflect.Int32 {
+			return value.String()
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		data := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			if field.Kind() == reflect.Ptr && field.IsNil() {
+				continue
+			}
+			data[toSnakeCase(v.Type().Field(i).Name)] = xdrToGo(field)
+		}
+		return data
+	case reflect.Array, reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			bytes := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(bytes), v)
+			return hex.EncodeToString(bytes)
+		}
+		list := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			list = append(list, xdrToGo(v.Index(i)))
+		}
+		return list
+	}
+	return v.Interface()
+}
+
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// split before an upper case letter, unless it continues an acronym
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
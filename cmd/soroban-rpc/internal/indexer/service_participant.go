@@ -0,0 +1,149 @@
+package indexer
+
+import (
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model"
+)
+
+// participantSet collects the distinct addresses taking part in an activity, in the order they are found
+type participantSet struct {
+	addresses []string
+	seen      map[string]bool
+}
+
+func (p *participantSet) add(address string) {
+	if address == "" || p.seen[address] {
+		return
+	}
+	if p.seen == nil {
+		p.seen = make(map[string]bool)
+	}
+	p.seen[address] = true
+	p.addresses = append(p.addresses, address)
+}
+
+func (p *participantSet) addAccount(account xdr.MuxedAccount) {
+	// muxed accounts are indexed under their underlying account
+	accountID := account.ToAccountId()
+	p.add(accountID.Address())
+}
+
+func (p *participantSet) addScAddress(address xdr.ScAddress) {
+	if str, err := address.String(); err == nil {
+		p.add(str)
+	}
+}
+
+// getTransactionParticipants finds the source accounts, fee bump source, operation destinations
+// and the addresses of the Soroban auth entries of a transaction.
+func getTransactionParticipants(transaction *model.Transaction, envelope xdr.TransactionEnvelope) []model.Participant {
+	var set participantSet
+	if envelope.IsFeeBump() {
+		set.addAccount(envelope.FeeBumpAccount())
+	}
+	set.addAccount(envelope.SourceAccount())
+	for _, op := range envelope.Operations() {
+		if op.SourceAccount != nil {
+			set.addAccount(*op.SourceAccount)
+		}
+		addOperationDestinations(&set, op.Body)
+	}
+
+	var ledger uint32
+	if transaction.Ledger != nil {
+		ledger = *transaction.Ledger
+	}
+	var applicationOrder uint32
+	if transaction.ApplicationOrder != nil {
+		applicationOrder = uint32(*transaction.ApplicationOrder)
+	}
+	pagingToken := events.Cursor{Ledger: ledger, Tx: applicationOrder}.String()
+
+	participants := make([]model.Participant, 0, len(set.addresses))
+	for _, address := range set.addresses {
+		participants = append(participants, model.NewParticipant(address, model.ActivityTransaction,
+			transaction.ID, pagingToken, transaction.ID, ledger, transaction.Ts))
+	}
+	return participants
+}
+
+func addOperationDestinations(set *participantSet, body xdr.OperationBody) {
+	switch body.Type {
+	case xdr.OperationTypeCreateAccount:
+		set.add(body.CreateAccountOp.Destination.Address())
+	case xdr.OperationTypePayment:
+		set.addAccount(body.PaymentOp.Destination)
+	case xdr.OperationTypePathPaymentStrictReceive:
+		set.addAccount(body.PathPaymentStrictReceiveOp.Destination)
+	case xdr.OperationTypePathPaymentStrictSend:
+		set.addAccount(body.PathPaymentStrictSendOp.Destination)
+	case xdr.OperationTypeAccountMerge:
+		set.addAccount(*body.Destination)
+	case xdr.OperationTypeAllowTrust:
+		set.add(body.AllowTrustOp.Trustor.Address())
+	case xdr.OperationTypeSetTrustLineFlags:
+		set.add(body.SetTrustLineFlagsOp.Trustor.Address())
+	case xdr.OperationTypeClawback:
+		set.addAccount(body.ClawbackOp.From)
+	case xdr.OperationTypeBeginSponsoringFutureReserves:
+		set.add(body.BeginSponsoringFutureReservesOp.SponsoredId.Address())
+	case xdr.OperationTypeCreateClaimableBalance:
+		for _, claimant := range body.CreateClaimableBalanceOp.Claimants {
+			if v0, ok := claimant.GetV0(); ok {
+				set.add(v0.Destination.Address())
+			}
+		}
+	case xdr.OperationTypeInvokeHostFunction:
+		for _, auth := range body.InvokeHostFunctionOp.Auth {
+			if credentials, ok := auth.Credentials.GetAddress(); ok {
+				set.addScAddress(credentials.Address)
+			}
+			addAuthorizedInvocationContracts(set, auth.RootInvocation)
+		}
+	}
+}
+
+func addAuthorizedInvocationContracts(set *participantSet, invocation xdr.SorobanAuthorizedInvocation) {
+	if contractFn, ok := invocation.Function.GetContractFn(); ok {
+		set.addScAddress(contractFn.ContractAddress)
+	}
+	for _, sub := range invocation.SubInvocations {
+		addAuthorizedInvocationContracts(set, sub)
+	}
+}
+
+func getEventParticipants(event *model.Event) []model.Participant {
+	if event.ContractID == "" {
+		return nil
+	}
+	return []model.Participant{model.NewParticipant(event.ContractID, model.ActivityEvent,
+		event.ID, event.PagingToken, event.TxHash, uint32(event.Ledger), event.Ts)}
+}
+
+func getTokenOperationParticipants(tokenOp *model.TokenOperation) []model.Participant {
+	var set participantSet
+	// token operation addresses are parsed from event topics, make sure they are addresses
+	if isAddress(tokenOp.From) {
+		set.add(tokenOp.From)
+	}
+	if tokenOp.To != nil && isAddress(*tokenOp.To) {
+		set.add(*tokenOp.To)
+	}
+	participants := make([]model.Participant, 0, len(set.addresses))
+	for _, address := range set.addresses {
+		participants = append(participants, model.NewParticipant(address, model.ActivityTokenOperation,
+			tokenOp.ID, tokenOp.ID, tokenOp.TxHash, uint32(tokenOp.Ledger), tokenOp.Ts))
+	}
+	return participants
+}
+
+func isAddress(address string) bool {
+	if _, err := strkey.Decode(strkey.VersionByteAccountID, address); err == nil {
+		return true
+	}
+	_, err := strkey.Decode(strkey.VersionByteContract, address)
+	return err == nil
+}
@@ -0,0 +1,87 @@
+package indexer
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/parser"
+)
+
+// setContractWasmHash updates the cached wasm hash of a contract, once the contract is upserted
+func (s *Service) setContractWasmHash(contract *model.Contract) {
+	s.specLock.Lock()
+	defer s.specLock.Unlock()
+	if contract.WasmHash != nil {
+		s.wasmHashes[contract.ContractID] = *contract.WasmHash
+	} else {
+		delete(s.wasmHashes, contract.ContractID)
+	}
+}
+
+// getSpecDecoder returns a decoder for the spec of the contract's current WASM,
+// or nil if the contract or its code haven't been indexed (e.g. stellar asset contracts).
+func (s *Service) getSpecDecoder(contractID string) *parser.SpecDecoder {
+	if contractID == "" {
+		return nil
+	}
+	s.specLock.Lock()
+	defer s.specLock.Unlock()
+	wasmHash, ok := s.wasmHashes[contractID]
+	if !ok {
+		var contract model.Contract
+		if err := s.indexerDB.Select("wasm_hash").Where("contract_id = ?", contractID).Take(&contract).Error; err != nil || contract.WasmHash == nil {
+			return nil
+		}
+		wasmHash = *contract.WasmHash
+		s.wasmHashes[contractID] = wasmHash
+	}
+	if decoder, ok := s.specDecoders[wasmHash]; ok {
+		return decoder
+	}
+	// misses aren't cached, the code entry may still be in the queue
+	var code model.ContractCodeEntry
+	if err := s.indexerDB.Select("spec_xdr").Where("hash = ?", wasmHash).Take(&code).Error; err != nil || code.SpecXdr == "" {
+		return nil
+	}
+	decoder, err := parser.NewSpecDecoderFromXdr(code.SpecXdr)
+	if err != nil {
+		s.logger.WithError(err).Error("error cannot decode contract spec of " + wasmHash)
+		return nil
+	}
+	s.specDecoders[wasmHash] = decoder
+	return decoder
+}
+
+func (s *Service) decodeEvent(event *model.Event) {
+	decoder := s.getSpecDecoder(event.ContractID)
+	if decoder == nil {
+		return
+	}
+	topic := make([]interface{}, 0, len(event.TopicXdr))
+	for _, segment := range event.TopicXdr {
+		var scVal xdr.ScVal
+		if err := xdr.SafeUnmarshalBase64(segment, &scVal); err != nil {
+			s.logger.WithError(err).Error("error failed to parse segment " + segment)
+			return
+		}
+		topic = append(topic, decoder.Decode(scVal))
+	}
+	event.DecodedTopic = toJSON(topic)
+
+	var value xdr.ScVal
+	if err := xdr.SafeUnmarshalBase64(event.ValueXdr, &value); err == nil {
+		event.DecodedValue = toJSON(decoder.Decode(value))
+	}
+}
+
+// toJSON renders a decoded value for a jsonb column, which doesn't accept null characters.
+func toJSON(v interface{}) interface{} {
+	jsonData, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return strings.Replace(string(jsonData), "\\u0000", "", -1)
+}
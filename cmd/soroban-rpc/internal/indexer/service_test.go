@@ -3,10 +3,15 @@ package indexer
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer/model"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrjson"
 )
 
 func TestScValToJSON(t *testing.T) {
@@ -18,3 +23,395 @@ func TestScValToJSON(t *testing.T) {
 	jsonString := string(jsonData)
 	fmt.Println(strings.Replace(jsonString, "\\u0000", "", -1))
 }
+
+func TestScValToGo(t *testing.T) {
+	u64 := xdr.Uint64(math.MaxUint64)
+	bytes := xdr.ScBytes{0xca, 0xfe}
+	sym := xdr.ScSymbol("balance")
+	contractID := xdr.Hash{0x1}
+	vec := &xdr.ScVec{
+		{Type: xdr.ScValTypeScvU64, U64: &u64},
+		{Type: xdr.ScValTypeScvBytes, Bytes: &bytes},
+		{Type: xdr.ScValTypeScvAddress, Address: &xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID}},
+	}
+	scMap := &xdr.ScMap{{Key: xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, Val: xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vec}}}
+	jsonData, err := json.Marshal(scValToGo(xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &scMap}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"key":"balance","value":["18446744073709551615","cafe","` +
+		strkey.MustEncode(strkey.VersionByteContract, contractID[:]) + `"]}]`
+	if string(jsonData) != expected {
+		t.Fatalf("expected %s, got %s", expected, jsonData)
+	}
+}
+
+func TestGetContractIdPreimages(t *testing.T) {
+	passphrase := "Test SDF Network ; September 2015"
+	asset := xdr.MustNewCreditAsset("USDC", "GBBD47IF6LWK7P7MDEVSCWR7DPUWV3NY3DTQEVFL4NAT4AQH3ZLLFLA5")
+	preimage := xdr.ContractIdPreimage{
+		Type:      xdr.ContractIdPreimageTypeContractIdPreimageFromAsset,
+		FromAsset: &asset,
+	}
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				Operations: []xdr.Operation{{
+					Body: xdr.OperationBody{
+						Type: xdr.OperationTypeInvokeHostFunction,
+						InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+							HostFunction: xdr.HostFunction{
+								Type: xdr.HostFunctionTypeHostFunctionTypeCreateContract,
+								CreateContract: &xdr.CreateContractArgs{
+									ContractIdPreimage: preimage,
+									Executable:         xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableStellarAsset},
+								},
+							},
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	expected, err := asset.ContractID(passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preimages := getContractIdPreimages(passphrase, envelope)
+	if len(preimages) != 1 {
+		t.Fatalf("expected 1 preimage, got %d", len(preimages))
+	}
+	if _, ok := preimages[expected]; !ok {
+		t.Fatalf("preimage not keyed by the asset contract id")
+	}
+}
+
+func TestIsHostFunction(t *testing.T) {
+	contractID := xdr.Hash{1}
+	args := xdr.InvokeContractArgs{
+		ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+		FunctionName:    "transfer",
+	}
+	hostFunction := xdr.HostFunction{Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract, InvokeContract: &args}
+
+	same := args
+	if !isHostFunction(xdr.SorobanAuthorizedFunction{
+		Type:       xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+		ContractFn: &same,
+	}, hostFunction) {
+		t.Fatal("expected the auth root to match the host function")
+	}
+
+	other := args
+	other.FunctionName = "approve"
+	if isHostFunction(xdr.SorobanAuthorizedFunction{
+		Type:       xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+		ContractFn: &other,
+	}, hostFunction) {
+		t.Fatal("expected a different function not to match the host function")
+	}
+}
+
+func TestGetOperations(t *testing.T) {
+	source := xdr.MustMuxedAddress("GBBD47IF6LWK7P7MDEVSCWR7DPUWV3NY3DTQEVFL4NAT4AQH3ZLLFLA5")
+	destination := xdr.MustMuxedAddress("GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ")
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: source,
+				Operations: []xdr.Operation{{
+					Body: xdr.OperationBody{
+						Type: xdr.OperationTypePathPaymentStrictSend,
+						PathPaymentStrictSendOp: &xdr.PathPaymentStrictSendOp{
+							SendAsset:   xdr.MustNewNativeAsset(),
+							SendAmount:  100,
+							Destination: destination,
+							DestAsset:   xdr.MustNewCreditAsset("USDC", "GBBD47IF6LWK7P7MDEVSCWR7DPUWV3NY3DTQEVFL4NAT4AQH3ZLLFLA5"),
+							DestMin:     90,
+						},
+					},
+				}},
+			},
+		},
+	}
+	result := xdr.TransactionResult{
+		Result: xdr.TransactionResultResult{
+			Code: xdr.TransactionResultCodeTxFailed,
+			Results: &[]xdr.OperationResult{{
+				Code: xdr.OperationResultCodeOpInner,
+				Tr: &xdr.OperationResultTr{
+					Type: xdr.OperationTypePathPaymentStrictSend,
+					PathPaymentStrictSendResult: &xdr.PathPaymentStrictSendResult{
+						Code: xdr.PathPaymentStrictSendResultCodePathPaymentStrictSendUnderDestmin,
+					},
+				},
+			}},
+		},
+	}
+
+	ops := getOperations(&model.Transaction{ID: "abc"}, envelope, result)
+	if len(ops) != 1 {
+		t.Fatalf("expected 1 operation, got %d", len(ops))
+	}
+	op := ops[0]
+	if op.ID != "abc-0" || op.Type != "path_payment_strict_send" {
+		t.Fatalf("unexpected operation %s of type %s", op.ID, op.Type)
+	}
+	if *op.SourceAccount != "GBBD47IF6LWK7P7MDEVSCWR7DPUWV3NY3DTQEVFL4NAT4AQH3ZLLFLA5" {
+		t.Fatalf("unexpected source account %s", *op.SourceAccount)
+	}
+	if *op.ResultCode != "PathPaymentStrictSendResultCodePathPaymentStrictSendUnderDestmin" {
+		t.Fatalf("unexpected result code %s", *op.ResultCode)
+	}
+	var body struct {
+		Type    string                 `json:"type"`
+		Payment map[string]interface{} `json:"path_payment_strict_send_op"`
+	}
+	if err := json.Unmarshal([]byte(op.Body.(string)), &body); err != nil {
+		t.Fatal(err)
+	}
+	payment := body.Payment
+	if payment["send_asset"] != "native" || payment["dest_asset"] != "USDC:GBBD47IF6LWK7P7MDEVSCWR7DPUWV3NY3DTQEVFL4NAT4AQH3ZLLFLA5" ||
+		payment["destination"] != "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ" || payment["send_amount"] != float64(100) {
+		t.Fatalf("unexpected body %s", op.Body)
+	}
+}
+
+func TestGetTransactionParticipants(t *testing.T) {
+	feeSource := xdr.MustMuxedAddress("GBBD47IF6LWK7P7MDEVSCWR7DPUWV3NY3DTQEVFL4NAT4AQH3ZLLFLA5")
+	destination := xdr.MustMuxedAddress("GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ")
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
+		FeeBump: &xdr.FeeBumpTransactionEnvelope{
+			Tx: xdr.FeeBumpTransaction{
+				FeeSource: feeSource,
+				InnerTx: xdr.FeeBumpTransactionInnerTx{
+					Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+					V1: &xdr.TransactionV1Envelope{
+						Tx: xdr.Transaction{
+							SourceAccount: feeSource,
+							Operations: []xdr.Operation{{
+								Body: xdr.OperationBody{
+									Type: xdr.OperationTypePayment,
+									PaymentOp: &xdr.PaymentOp{
+										Destination: destination,
+										Asset:       xdr.MustNewNativeAsset(),
+										Amount:      100,
+									},
+								},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ledger := uint32(2)
+	applicationOrder := int32(3)
+	participants := getTransactionParticipants(&model.Transaction{ID: "abc", Ledger: &ledger, ApplicationOrder: &applicationOrder}, envelope)
+	if len(participants) != 2 {
+		t.Fatalf("expected 2 participants, got %d", len(participants))
+	}
+	if participants[0].Address != feeSource.Address() || participants[1].Address != destination.Address() {
+		t.Fatalf("unexpected participants %s and %s", participants[0].Address, participants[1].Address)
+	}
+	for _, participant := range participants {
+		if participant.ActivityType != model.ActivityTransaction || participant.ActivityID != "abc" || participant.Ledger != 2 {
+			t.Fatalf("unexpected participant %+v", participant)
+		}
+	}
+
+	pagingToken, rank, err := parseActivityCursor(activityCursor(participants[0]))
+	if err != nil || pagingToken != participants[0].PagingToken || rank != participants[0].Rank {
+		t.Fatalf("unexpected cursor %s %d: %v", pagingToken, rank, err)
+	}
+}
+
+func TestSorobanFeeConfig(t *testing.T) {
+	ledgerCost := xdr.ConfigSettingContractLedgerCostV0{
+		FeeReadLedgerEntry:             10,
+		FeeWriteLedgerEntry:            100,
+		FeeRead1Kb:                     1024,
+		BucketListTargetSizeBytes:      1000,
+		WriteFee1KbBucketListLow:       1000,
+		WriteFee1KbBucketListHigh:      3000,
+		BucketListWriteFeeGrowthFactor: 2,
+	}
+	feeConfig := NewSorobanFeeConfig(
+		xdr.ConfigSettingContractComputeV0{FeeRatePerInstructionsIncrement: 25},
+		ledgerCost,
+		xdr.ConfigSettingContractHistoricalDataV0{FeeHistorical1Kb: 1024},
+		xdr.ConfigSettingContractEventsV0{FeeContractEvents1Kb: 2048},
+		xdr.ConfigSettingContractBandwidthV0{FeeTxSize1Kb: 1024},
+		[]xdr.Uint64{400, 600},
+	)
+	// the average bucket list size is half the target
+	if feeConfig.FeeWrite1Kb != 2000 {
+		t.Fatalf("unexpected write fee %d", feeConfig.FeeWrite1Kb)
+	}
+	// above the target the fee grows with the growth factor
+	if fee := computeWriteFee1Kb(1500, ledgerCost); fee != 5000 {
+		t.Fatalf("unexpected write fee above target %d", fee)
+	}
+
+	resources := xdr.SorobanResources{
+		Footprint: xdr.LedgerFootprint{
+			ReadOnly:  make([]xdr.LedgerKey, 2),
+			ReadWrite: make([]xdr.LedgerKey, 1),
+		},
+		Instructions: 10001,
+		ReadBytes:    10,
+		WriteBytes:   1,
+	}
+	// instructions 26 + read entries 30 + write entries 100 + read bytes 10 + write bytes 2 + historical 400 + bandwidth 100
+	if fee := feeConfig.NonRefundableFee(resources, 100); fee != 668 {
+		t.Fatalf("unexpected non refundable fee %d", fee)
+	}
+	if fee := feeConfig.EventsFee(3); fee != 6 {
+		t.Fatalf("unexpected events fee %d", fee)
+	}
+}
+
+func TestNewLedger(t *testing.T) {
+	source := xdr.MustMuxedAddress("GBBD47IF6LWK7P7MDEVSCWR7DPUWV3NY3DTQEVFL4NAT4AQH3ZLLFLA5")
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: source,
+				Operations: []xdr.Operation{
+					{Body: xdr.OperationBody{Type: xdr.OperationTypeInflation}},
+					{Body: xdr.OperationBody{Type: xdr.OperationTypeInflation}},
+				},
+			},
+		},
+	}
+	result := func(code xdr.TransactionResultCode) xdr.TransactionResultMeta {
+		return xdr.TransactionResultMeta{
+			Result: xdr.TransactionResultPair{
+				Result: xdr.TransactionResult{Result: xdr.TransactionResultResult{Code: code, Results: &[]xdr.OperationResult{}}},
+			},
+		}
+	}
+	ledgerCloseMeta := xdr.LedgerCloseMeta{
+		V: 0,
+		V0: &xdr.LedgerCloseMetaV0{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Hash: xdr.Hash{1},
+				Header: xdr.LedgerHeader{
+					LedgerVersion:      20,
+					PreviousLedgerHash: xdr.Hash{2},
+					LedgerSeq:          7,
+					BaseFee:            100,
+					BaseReserve:        5000000,
+					ScpValue:           xdr.StellarValue{CloseTime: 1700000000},
+				},
+			},
+			TxSet:        xdr.TransactionSet{Txs: []xdr.TransactionEnvelope{envelope, envelope}},
+			TxProcessing: []xdr.TransactionResultMeta{result(xdr.TransactionResultCodeTxSuccess), result(xdr.TransactionResultCodeTxFailed)},
+		},
+	}
+
+	feeConfig := SorobanFeeConfig{FeeRead1Kb: 10}
+	ledger := newLedger(ledgerCloseMeta, &feeConfig)
+	if ledger.Sequence != 7 || ledger.ProtocolVersion != 20 || ledger.BaseFee != 100 || ledger.BaseReserve != 5000000 {
+		t.Fatalf("unexpected ledger header %+v", ledger)
+	}
+	if ledger.Hash != (xdr.Hash{1}).HexString() || ledger.PreviousHash != (xdr.Hash{2}).HexString() || ledger.CloseTime.Unix() != 1700000000 {
+		t.Fatalf("unexpected ledger hashes or close time %+v", ledger)
+	}
+	if ledger.TransactionCount != 2 || ledger.SuccessfulTransactionCount != 1 || ledger.FailedTransactionCount != 1 || ledger.OperationCount != 4 {
+		t.Fatalf("unexpected ledger counts %+v", ledger)
+	}
+	if !strings.Contains(ledger.SorobanFeeConfig.(string), `"fee_read_1kb":10`) {
+		t.Fatalf("unexpected fee config %v", ledger.SorobanFeeConfig)
+	}
+}
+
+func TestSetInvocationResources(t *testing.T) {
+	source := xdr.MustAddress("GBBD47IF6LWK7P7MDEVSCWR7DPUWV3NY3DTQEVFL4NAT4AQH3ZLLFLA5")
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: source.ToMuxedAccount(),
+				Ext: xdr.TransactionExt{
+					V: 1,
+					SorobanData: &xdr.SorobanTransactionData{
+						Resources:   xdr.SorobanResources{Instructions: 1000000, ReadBytes: 5000, WriteBytes: 1000},
+						ResourceFee: 900,
+					},
+				},
+			},
+		},
+	}
+	metric := func(name string, value uint64) xdr.DiagnosticEvent {
+		topic, metricName, data := xdr.ScSymbol(xdrjson.CoreMetricsTopic), xdr.ScSymbol(name), xdr.Uint64(value)
+		return xdr.DiagnosticEvent{
+			Event: xdr.ContractEvent{
+				Type: xdr.ContractEventTypeDiagnostic,
+				Body: xdr.ContractEventBody{
+					V: 0,
+					V0: &xdr.ContractEventV0{
+						Topics: []xdr.ScVal{
+							{Type: xdr.ScValTypeScvSymbol, Sym: &topic},
+							{Type: xdr.ScValTypeScvSymbol, Sym: &metricName},
+						},
+						Data: xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &data},
+					},
+				},
+			},
+		}
+	}
+	account := func(balance xdr.Int64) *xdr.LedgerEntry {
+		return &xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+			Type:    xdr.LedgerEntryTypeAccount,
+			Account: &xdr.AccountEntry{AccountId: source, Balance: balance},
+		}}
+	}
+	meta := xdr.TransactionMetaV3{
+		TxChangesAfter: xdr.LedgerEntryChanges{
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: account(100)},
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: account(350)},
+		},
+		SorobanMeta: &xdr.SorobanTransactionMeta{
+			DiagnosticEvents: []xdr.DiagnosticEvent{
+				metric(xdrjson.CPUInsnMetric, 123456),
+				metric(xdrjson.LedgerReadByteMetric, 2048),
+				metric(xdrjson.LedgerWriteByteMetric, 512),
+				metric("mem_byte", 1),
+			},
+		},
+	}
+
+	var invocation model.Invocation
+	setInvocationResources(&invocation, envelope, meta)
+	// the used resources differ from the declared ones
+	if invocation.Instructions == nil || *invocation.Instructions != 123456 {
+		t.Fatalf("unexpected instructions %v", invocation.Instructions)
+	}
+	if invocation.ReadBytes == nil || *invocation.ReadBytes != 2048 {
+		t.Fatalf("unexpected read bytes %v", invocation.ReadBytes)
+	}
+	if invocation.WriteBytes == nil || *invocation.WriteBytes != 512 {
+		t.Fatalf("unexpected write bytes %v", invocation.WriteBytes)
+	}
+	if invocation.ResourceFee == nil || *invocation.ResourceFee != 650 {
+		t.Fatalf("unexpected resource fee %v", invocation.ResourceFee)
+	}
+
+	// without diagnostic events only the fee is known
+	meta.SorobanMeta.DiagnosticEvents = nil
+	invocation = model.Invocation{}
+	setInvocationResources(&invocation, envelope, meta)
+	if invocation.Instructions != nil || invocation.ReadBytes != nil || invocation.WriteBytes != nil {
+		t.Fatalf("unexpected resources without core metrics")
+	}
+	if invocation.ResourceFee == nil || *invocation.ResourceFee != 650 {
+		t.Fatalf("unexpected resource fee %v", invocation.ResourceFee)
+	}
+}
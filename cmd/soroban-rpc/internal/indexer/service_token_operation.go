@@ -101,6 +101,7 @@ func initTokenOpFromEvent(opType string, from string, event model.Event) model.T
 		ID:             event.ID,
 		Type:           opType,
 		TxIndex:        event.TxIndex,
+		TxHash:         event.TxHash,
 		Ledger:         event.Ledger,
 		LedgerClosedAt: event.LedgerClosedAt,
 		ContractID:     event.ContractID,
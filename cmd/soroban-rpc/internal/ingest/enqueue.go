@@ -21,8 +21,8 @@ func (s *Service) enqueueChangePost(changePost xdr.LedgerEntry) {
 	}
 }
 
-func (s *Service) enqueueTransaction(hash string, info methods.GetTransactionResponse, tx transactions.Transaction) {
-	marshaledTx := s.indexerService.MarshalTransaction(hash, info, tx)
+func (s *Service) enqueueTransaction(hash string, info methods.GetTransactionResponse, tx transactions.Transaction, feeConfig *indexer.SorobanFeeConfig) {
+	marshaledTx := s.indexerService.MarshalTransaction(hash, info, tx, feeConfig)
 	err := s.rdb.RPush(context.Background(), indexer.QueueKey, indexer.Tx+":"+marshaledTx).Err()
 	if err != nil {
 		s.logger.WithError(err).Error("error push tx_queue")
@@ -0,0 +1,63 @@
+package ingest
+
+import (
+	"context"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/indexer"
+)
+
+var sorobanFeeConfigSettings = []xdr.ConfigSettingId{
+	xdr.ConfigSettingIdConfigSettingContractComputeV0,
+	xdr.ConfigSettingIdConfigSettingContractLedgerCostV0,
+	xdr.ConfigSettingIdConfigSettingContractHistoricalDataV0,
+	xdr.ConfigSettingIdConfigSettingContractEventsV0,
+	xdr.ConfigSettingIdConfigSettingContractBandwidthV0,
+	xdr.ConfigSettingIdConfigSettingBucketlistSizeWindow,
+}
+
+// getSorobanFeeConfig reads the network fee settings from the ledger entries,
+// returning nil if they are not available (e.g. before protocol 20).
+func (s *Service) getSorobanFeeConfig(ctx context.Context) (*indexer.SorobanFeeConfig, error) {
+	if s.ledgerEntryReader == nil {
+		return nil, nil
+	}
+	tx, err := s.ledgerEntryReader.NewTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Done()
+	}()
+
+	keys := make([]xdr.LedgerKey, 0, len(sorobanFeeConfigSettings))
+	for _, id := range sorobanFeeConfigSettings {
+		keys = append(keys, xdr.LedgerKey{
+			Type:          xdr.LedgerEntryTypeConfigSetting,
+			ConfigSetting: &xdr.LedgerKeyConfigSetting{ConfigSettingId: id},
+		})
+	}
+	entries, err := tx.GetLedgerEntries(keys...)
+	if err != nil {
+		return nil, err
+	}
+	settings := make(map[xdr.ConfigSettingId]xdr.ConfigSettingEntry, len(entries))
+	for _, entry := range entries {
+		setting := entry.Entry.Data.MustConfigSetting()
+		settings[setting.ConfigSettingId] = setting
+	}
+	if len(settings) != len(sorobanFeeConfigSettings) {
+		return nil, nil
+	}
+
+	feeConfig := indexer.NewSorobanFeeConfig(
+		*settings[xdr.ConfigSettingIdConfigSettingContractComputeV0].ContractCompute,
+		*settings[xdr.ConfigSettingIdConfigSettingContractLedgerCostV0].ContractLedgerCost,
+		*settings[xdr.ConfigSettingIdConfigSettingContractHistoricalDataV0].ContractHistoricalData,
+		*settings[xdr.ConfigSettingIdConfigSettingContractEventsV0].ContractEvents,
+		*settings[xdr.ConfigSettingIdConfigSettingContractBandwidthV0].ContractBandwidth,
+		*settings[xdr.ConfigSettingIdConfigSettingBucketlistSizeWindow].BucketListSizeWindow,
+	)
+	return &feeConfig, nil
+}
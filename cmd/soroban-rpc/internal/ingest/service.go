@@ -48,6 +48,7 @@ type Config struct {
 	IndexerService    *indexer.Service
 	LedgerEntryReader db.LedgerEntryReader
 	Redis             *redis.Client
+	SimulationCache   *methods.SimulationCache
 }
 
 func NewService(cfg Config) *Service {
@@ -100,7 +101,8 @@ func newService(cfg Config) *Service {
 			latestLedgerMetric:      latestLedgerMetric,
 			ledgerStatsMetric:       ledgerStatsMetric,
 		},
-		rdb: cfg.Redis,
+		rdb:             cfg.Redis,
+		simulationCache: cfg.SimulationCache,
 	}
 
 	return service
@@ -156,6 +158,7 @@ type Service struct {
 	indexerService    *indexer.Service
 	ledgerEntryReader db.LedgerEntryReader
 	rdb               *redis.Client
+	simulationCache   *methods.SimulationCache
 }
 
 func (s *Service) Close() error {
@@ -309,9 +312,18 @@ func (s *Service) ingest(ctx context.Context, sequence uint32) error {
 		return err
 	}
 	s.logger.Debugf("Ingested ledger %d", sequence)
+	// the ledger state changed, so the cached simulations are stale
+	s.simulationCache.Purge()
 
+	feeConfig, err := s.getSorobanFeeConfig(ctx)
+	if err != nil {
+		s.logger.WithError(err).Warn("could not read the soroban fee config")
+	}
+	s.indexerService.EnqueueLedger(ledgerCloseMeta, feeConfig)
+	// transactions go first, so that contracts deployed in this ledger are
+	// indexed before their events are decoded
+	s.processTransactions(feeConfig)
 	s.processEvents()
-	s.processTransactions()
 
 	s.metrics.ingestionDurationMetric.
 		With(prometheus.Labels{"type": "total"}).Observe(time.Since(startTime).Seconds())
@@ -345,7 +357,7 @@ func (s *Service) processEvents() {
 	}
 }
 
-func (s *Service) processTransactions() {
+func (s *Service) processTransactions(feeConfig *indexer.SorobanFeeConfig) {
 	hashes := s.transactionStore.GetLastLedgerTransactions()
 
 	for _, hash := range hashes {
@@ -362,6 +374,8 @@ func (s *Service) processTransactions() {
 			continue
 		}
 
-		s.enqueueTransaction(hash, info, tx)
+		s.enqueueTransaction(hash, info, tx, feeConfig)
+		s.indexerService.EnqueueContracts(s.networkPassPhrase, hash, info, tx)
+		s.indexerService.EnqueueInvocations(s.networkPassPhrase, hash, info, tx)
 	}
 }
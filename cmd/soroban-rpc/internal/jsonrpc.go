@@ -47,14 +47,17 @@ func (h Handler) Close() {
 }
 
 type HandlerParams struct {
-	EventStore        *events.MemoryStore
-	TransactionStore  *transactions.MemoryStore
-	LedgerEntryReader db.LedgerEntryReader
-	LedgerReader      db.LedgerReader
-	Logger            *log.Entry
-	PreflightGetter   methods.PreflightGetter
-	Daemon            interfaces.Daemon
-	IndexerService    *indexer.Service
+	EventStore         *events.MemoryStore
+	TransactionStore   *transactions.MemoryStore
+	LedgerEntryReader  db.LedgerEntryReader
+	ContractDataReader db.ContractDataReader
+	LedgerReader       db.LedgerReader
+	Logger             *log.Entry
+	PreflightGetter    methods.PreflightGetter
+	SimulationCache    *methods.SimulationCache
+	SubmissionTracker  *methods.SubmissionTracker
+	Daemon             interfaces.Daemon
+	IndexerService     *indexer.Service
 }
 
 func decorateHandlers(daemon interfaces.Daemon, logger *log.Entry, m handler.Map) handler.Map {
@@ -175,6 +178,13 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 			queueLimit:           cfg.RequestBacklogGetLatestLedgerQueueLimit,
 			requestDurationLimit: cfg.MaxGetLatestLedgerExecutionDuration,
 		},
+		{
+			methodName:           "getLedgers",
+			underlyingHandler:    methods.NewGetLedgersHandler(params.LedgerReader, cfg.MaxLedgersLimit, cfg.DefaultLedgersLimit),
+			longName:             "get_ledgers",
+			queueLimit:           cfg.RequestBacklogGetLedgersQueueLimit,
+			requestDurationLimit: cfg.MaxGetLedgersExecutionDuration,
+		},
 		{
 			methodName:           "getLedgerEntry",
 			underlyingHandler:    methods.NewGetLedgerEntryHandler(params.Logger, params.LedgerEntryReader),
@@ -184,11 +194,18 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 		},
 		{
 			methodName:           "getLedgerEntries",
-			underlyingHandler:    methods.NewGetLedgerEntriesHandler(params.Logger, params.LedgerEntryReader),
+			underlyingHandler:    methods.NewGetLedgerEntriesHandler(params.Logger, params.LedgerEntryReader, params.LedgerReader, cfg.MaxHistoricalLedgerDepth),
 			longName:             "get_ledger_entries",
 			queueLimit:           cfg.RequestBacklogGetLedgerEntriesQueueLimit,
 			requestDurationLimit: cfg.MaxGetLedgerEntriesExecutionDuration,
 		},
+		{
+			methodName:           "getContractData",
+			underlyingHandler:    methods.NewGetContractDataHandler(params.Logger, params.ContractDataReader, cfg.MaxContractDataLimit, cfg.DefaultContractDataLimit),
+			longName:             "get_contract_data",
+			queueLimit:           cfg.RequestBacklogGetContractDataQueueLimit,
+			requestDurationLimit: cfg.MaxGetContractDataExecutionDuration,
+		},
 		{
 			methodName:           "getTransaction",
 			underlyingHandler:    methods.NewGetTransactionHandler(params.TransactionStore),
@@ -196,20 +213,76 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 			queueLimit:           cfg.RequestBacklogGetTransactionQueueLimit,
 			requestDurationLimit: cfg.MaxGetTransactionExecutionDuration,
 		},
+		{
+			methodName:           "getTransactions",
+			underlyingHandler:    methods.NewGetTransactionsHandler(params.TransactionStore, cfg.MaxTransactionsLimit, cfg.DefaultTransactionsLimit),
+			longName:             "get_transactions",
+			queueLimit:           cfg.RequestBacklogGetTransactionsQueueLimit,
+			requestDurationLimit: cfg.MaxGetTransactionsExecutionDuration,
+		},
+		{
+			methodName:           "waitForTransaction",
+			underlyingHandler:    methods.NewWaitForTransactionHandler(params.TransactionStore, cfg.MaxWaitForTransactionTimeout),
+			longName:             "wait_for_transaction",
+			queueLimit:           cfg.RequestBacklogWaitForTransactionQueueLimit,
+			requestDurationLimit: cfg.MaxWaitForTransactionExecutionDuration,
+		},
+		{
+			methodName:           "getFeeStats",
+			underlyingHandler:    methods.NewGetFeeStatsHandler(params.TransactionStore, cfg.FeeStatsLedgerWindow),
+			longName:             "get_fee_stats",
+			queueLimit:           cfg.RequestBacklogGetFeeStatsQueueLimit,
+			requestDurationLimit: cfg.MaxGetFeeStatsExecutionDuration,
+		},
 		{
 			methodName:           "sendTransaction",
-			underlyingHandler:    methods.NewSendTransactionHandler(params.Daemon, params.Logger, params.TransactionStore, cfg.NetworkPassphrase),
+			underlyingHandler:    methods.NewSendTransactionHandler(params.Daemon, params.Logger, params.TransactionStore, cfg.NetworkPassphrase, params.SubmissionTracker),
 			longName:             "send_transaction",
 			queueLimit:           cfg.RequestBacklogSendTransactionQueueLimit,
 			requestDurationLimit: cfg.MaxSendTransactionExecutionDuration,
 		},
+		{
+			methodName:           "getSubmissionStatus",
+			underlyingHandler:    methods.NewGetSubmissionStatusHandler(params.Logger, params.SubmissionTracker),
+			longName:             "get_submission_status",
+			queueLimit:           cfg.RequestBacklogGetSubmissionStatusQueueLimit,
+			requestDurationLimit: cfg.MaxGetSubmissionStatusExecutionDuration,
+		},
 		{
 			methodName:           "simulateTransaction",
-			underlyingHandler:    methods.NewSimulateTransactionHandler(params.Logger, params.LedgerEntryReader, params.LedgerReader, params.PreflightGetter),
+			underlyingHandler:    methods.NewSimulateTransactionHandler(params.Logger, params.LedgerEntryReader, params.LedgerReader, params.PreflightGetter, params.SimulationCache, cfg.MaxHistoricalLedgerDepth),
 			longName:             "simulate_transaction",
 			queueLimit:           cfg.RequestBacklogSimulateTransactionQueueLimit,
 			requestDurationLimit: cfg.MaxSimulateTransactionExecutionDuration,
 		},
+		{
+			methodName:           "simulateTransactions",
+			underlyingHandler:    methods.NewSimulateTransactionsHandler(params.Logger, params.LedgerEntryReader, params.LedgerReader, params.PreflightGetter, cfg.MaxSimulateTransactionsBundleSize),
+			longName:             "simulate_transactions",
+			queueLimit:           cfg.RequestBacklogSimulateTransactionsQueueLimit,
+			requestDurationLimit: cfg.MaxSimulateTransactionsExecutionDuration,
+		},
+		{
+			methodName:           "prepareTransaction",
+			underlyingHandler:    methods.NewPrepareTransactionHandler(params.Logger, params.LedgerEntryReader, params.LedgerReader, params.PreflightGetter, params.SimulationCache),
+			longName:             "prepare_transaction",
+			queueLimit:           cfg.RequestBacklogPrepareTransactionQueueLimit,
+			requestDurationLimit: cfg.MaxPrepareTransactionExecutionDuration,
+		},
+		{
+			methodName:           "getAccountActivity",
+			underlyingHandler:    indexer.NewGetAccountActivityHandler(params.IndexerService, cfg.MaxAccountActivityLimit, cfg.DefaultAccountActivityLimit),
+			longName:             "get_account_activity",
+			queueLimit:           cfg.RequestBacklogGetAccountActivityQueueLimit,
+			requestDurationLimit: cfg.MaxGetAccountActivityExecutionDuration,
+		},
+		{
+			methodName:           "getIndexedLedgers",
+			underlyingHandler:    indexer.NewGetIndexedLedgersHandler(params.IndexerService),
+			longName:             "get_indexed_ledgers",
+			queueLimit:           cfg.RequestBacklogGetIndexedLedgersQueueLimit,
+			requestDurationLimit: cfg.MaxGetIndexedLedgersExecutionDuration,
+		},
 	}
 	handlersMap := handler.Map{}
 	for _, handler := range handlers {
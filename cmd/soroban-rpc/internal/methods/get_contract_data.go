@@ -0,0 +1,168 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrjson"
+)
+
+const (
+	ContractDataDurabilityPersistent = "persistent"
+	ContractDataDurabilityTemporary  = "temporary"
+)
+
+// ContractDataPaginationOptions defines the available options for paginating through contract data.
+type ContractDataPaginationOptions struct {
+	// Cursor is the key of the last entry returned by a previous request.
+	Cursor string `json:"cursor,omitempty"`
+	Limit  uint   `json:"limit,omitempty"`
+}
+
+type GetContractDataRequest struct {
+	ContractID string `json:"contractId"`
+	// Durability is either ContractDataDurabilityPersistent or ContractDataDurabilityTemporary.
+	Durability string `json:"durability"`
+	// KeyType optionally restricts the entries to the keys of a type, named as in the JSON rendering
+	// of ScVals (e.g. "symbol" or "vec").
+	KeyType    string                         `json:"keyType,omitempty"`
+	Pagination *ContractDataPaginationOptions `json:"pagination,omitempty"`
+}
+
+// GetContractDataResponse is the response for the Soroban-RPC getContractData() endpoint
+type GetContractDataResponse struct {
+	// Entries are the contract data entries of the contract, ordered by key.
+	Entries []LedgerEntryResult `json:"entries"`
+	// LatestLedger is the sequence number of the latest ledger at time of request.
+	LatestLedger uint32 `json:"latestLedger"`
+	// Cursor is the key of the last returned entry, to be used for the next request.
+	Cursor string `json:"cursor"`
+}
+
+type contractDataRPCHandler struct {
+	reader       db.ContractDataReader
+	logger       *log.Entry
+	maxLimit     uint
+	defaultLimit uint
+}
+
+func (h contractDataRPCHandler) parseRequest(request GetContractDataRequest) (db.ContractDataRequest, error) {
+	contractID, err := strkey.Decode(strkey.VersionByteContract, request.ContractID)
+	if err != nil {
+		return db.ContractDataRequest{}, errors.New("contract ID invalid")
+	}
+	var hash xdr.Hash
+	copy(hash[:], contractID)
+	result := db.ContractDataRequest{
+		Contract: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &hash},
+		Limit:    h.defaultLimit,
+	}
+
+	switch request.Durability {
+	case ContractDataDurabilityPersistent:
+		result.Durability = xdr.ContractDataDurabilityPersistent
+	case ContractDataDurabilityTemporary:
+		result.Durability = xdr.ContractDataDurabilityTemporary
+	default:
+		return db.ContractDataRequest{}, fmt.Errorf(
+			"durability must be one of %q or %q", ContractDataDurabilityPersistent, ContractDataDurabilityTemporary,
+		)
+	}
+
+	if request.KeyType != "" {
+		keyType, ok := xdrjson.ScValTypeFromName(request.KeyType)
+		if !ok {
+			return db.ContractDataRequest{}, fmt.Errorf("invalid key type %q", request.KeyType)
+		}
+		result.KeyType = &keyType
+	}
+
+	if request.Pagination != nil {
+		if request.Pagination.Limit > h.maxLimit {
+			return db.ContractDataRequest{}, fmt.Errorf("limit must not exceed %d", h.maxLimit)
+		}
+		if request.Pagination.Limit > 0 {
+			result.Limit = request.Pagination.Limit
+		}
+		if request.Pagination.Cursor != "" {
+			var cursor xdr.LedgerKey
+			if err := xdr.SafeUnmarshalBase64(request.Pagination.Cursor, &cursor); err != nil ||
+				cursor.Type != xdr.LedgerEntryTypeContractData {
+				return db.ContractDataRequest{}, fmt.Errorf("invalid cursor %q", request.Pagination.Cursor)
+			}
+			result.Cursor = &cursor
+		}
+	}
+	return result, nil
+}
+
+func (h contractDataRPCHandler) getContractData(ctx context.Context, request GetContractDataRequest) (GetContractDataResponse, error) {
+	dataRequest, err := h.parseRequest(request)
+	if err != nil {
+		return GetContractDataResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+
+	keysAndEntries, latestLedger, err := h.reader.GetContractData(ctx, dataRequest)
+	if err != nil {
+		h.logger.WithError(err).WithField("request", request).
+			Info("could not obtain contract data from storage")
+		return GetContractDataResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: "could not obtain contract data from storage",
+		}
+	}
+
+	response := GetContractDataResponse{
+		Entries:      make([]LedgerEntryResult, 0, len(keysAndEntries)),
+		LatestLedger: latestLedger,
+	}
+	if request.Pagination != nil {
+		response.Cursor = request.Pagination.Cursor
+	}
+	for _, keyAndEntry := range keysAndEntries {
+		keyXDR, err := xdr.MarshalBase64(keyAndEntry.Key)
+		if err != nil {
+			return GetContractDataResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: fmt.Sprintf("could not serialize ledger key %v", keyAndEntry.Key),
+			}
+		}
+		entryXDR, err := xdr.MarshalBase64(keyAndEntry.Entry.Data)
+		if err != nil {
+			return GetContractDataResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: fmt.Sprintf("could not serialize ledger entry data for ledger entry %v", keyAndEntry.Entry),
+			}
+		}
+		response.Entries = append(response.Entries, LedgerEntryResult{
+			Key:                keyXDR,
+			XDR:                entryXDR,
+			LastModifiedLedger: uint32(keyAndEntry.Entry.LastModifiedLedgerSeq),
+			LiveUntilLedgerSeq: keyAndEntry.LiveUntilLedgerSeq,
+		})
+		response.Cursor = keyXDR
+	}
+	return response, nil
+}
+
+// NewGetContractDataHandler returns a json rpc handler to list the contract data entries of a contract
+func NewGetContractDataHandler(logger *log.Entry, reader db.ContractDataReader, maxLimit, defaultLimit uint) jrpc2.Handler {
+	contractDataHandler := contractDataRPCHandler{
+		reader:       reader,
+		logger:       logger,
+		maxLimit:     maxLimit,
+		defaultLimit: defaultLimit,
+	}
+	return handler.New(contractDataHandler.getContractData)
+}
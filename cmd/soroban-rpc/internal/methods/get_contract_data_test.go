@@ -0,0 +1,100 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// recordingContractDataReader returns the entries and records the last request
+type recordingContractDataReader struct {
+	entries []db.LedgerKeyAndEntry
+	request db.ContractDataRequest
+}
+
+func (r *recordingContractDataReader) GetContractData(ctx context.Context, request db.ContractDataRequest) ([]db.LedgerKeyAndEntry, uint32, error) {
+	r.request = request
+	return r.entries, expectedLatestLedgerSequence, nil
+}
+
+func TestGetContractData(t *testing.T) {
+	contractID := xdr.Hash{0xca, 0xfe}
+	contract := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID}
+	sym := xdr.ScSymbol("counter")
+	val := xdr.Uint32(3)
+	data := xdr.ContractDataEntry{
+		Contract:   contract,
+		Key:        xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym},
+		Durability: xdr.ContractDataDurabilityTemporary,
+		Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &val},
+	}
+	var key xdr.LedgerKey
+	require.NoError(t, key.SetContractData(data.Contract, data.Key, data.Durability))
+	keyXDR, err := xdr.MarshalBase64(key)
+	require.NoError(t, err)
+	liveUntil := uint32(2000)
+	reader := &recordingContractDataReader{entries: []db.LedgerKeyAndEntry{{
+		Key: key,
+		Entry: xdr.LedgerEntry{
+			LastModifiedLedgerSeq: 900,
+			Data:                  xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeContractData, ContractData: &data},
+		},
+		LiveUntilLedgerSeq: &liveUntil,
+	}}}
+	handler := contractDataRPCHandler{reader: reader, logger: log.DefaultLogger, maxLimit: 100, defaultLimit: 10}
+	contractStrkey := strkey.MustEncode(strkey.VersionByteContract, contractID[:])
+
+	response, err := handler.getContractData(context.Background(), GetContractDataRequest{
+		ContractID: contractStrkey,
+		Durability: ContractDataDurabilityTemporary,
+		KeyType:    "symbol",
+	})
+	require.NoError(t, err)
+	symbolType := xdr.ScValTypeScvSymbol
+	assert.Equal(t, db.ContractDataRequest{
+		Contract:   contract,
+		Durability: xdr.ContractDataDurabilityTemporary,
+		KeyType:    &symbolType,
+		Limit:      10,
+	}, reader.request)
+	require.Len(t, response.Entries, 1)
+	assert.Equal(t, keyXDR, response.Entries[0].Key)
+	assert.Equal(t, uint32(900), response.Entries[0].LastModifiedLedger)
+	assert.Equal(t, &liveUntil, response.Entries[0].LiveUntilLedgerSeq)
+	assert.Equal(t, expectedLatestLedgerSequence, response.LatestLedger)
+	assert.Equal(t, keyXDR, response.Cursor)
+
+	// the cursor is kept when there are no more entries
+	reader.entries = nil
+	response, err = handler.getContractData(context.Background(), GetContractDataRequest{
+		ContractID: contractStrkey,
+		Durability: ContractDataDurabilityPersistent,
+		Pagination: &ContractDataPaginationOptions{Cursor: keyXDR, Limit: 5},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, xdr.ContractDataDurabilityPersistent, reader.request.Durability)
+	assert.Equal(t, uint(5), reader.request.Limit)
+	assert.Equal(t, &key, reader.request.Cursor)
+	assert.Empty(t, response.Entries)
+	assert.Equal(t, keyXDR, response.Cursor)
+
+	for _, request := range []GetContractDataRequest{
+		{ContractID: "invalid", Durability: ContractDataDurabilityPersistent},
+		{ContractID: contractStrkey, Durability: "forever"},
+		{ContractID: contractStrkey, Durability: ContractDataDurabilityPersistent, KeyType: "u33"},
+		{ContractID: contractStrkey, Durability: ContractDataDurabilityPersistent, Pagination: &ContractDataPaginationOptions{Limit: 101}},
+		{ContractID: contractStrkey, Durability: ContractDataDurabilityPersistent, Pagination: &ContractDataPaginationOptions{Cursor: "invalid"}},
+	} {
+		_, err = handler.getContractData(context.Background(), request)
+		require.Error(t, err)
+		assert.Equal(t, jrpc2.InvalidParams, err.(*jrpc2.Error).Code)
+	}
+}
@@ -2,6 +2,7 @@ package methods
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -79,44 +80,125 @@ type EventInfo struct {
 	TransactionHash          string   `json:"txHash"`
 }
 
+const (
+	EventOrderAscending  = "asc"
+	EventOrderDescending = "desc"
+)
+
 type GetEventsRequest struct {
-	StartLedger uint32             `json:"startLedger,omitempty"`
-	Filters     []EventFilter      `json:"filters"`
-	Pagination  *PaginationOptions `json:"pagination,omitempty"`
+	// StartLedger is the first ledger whose events are included. It can't be set along with a cursor,
+	// except for descending requests, where it keeps bounding the pages towards older events.
+	StartLedger uint32 `json:"startLedger,omitempty"`
+	// EndLedger is the first ledger whose events are excluded, it defaults to the latest ledger.
+	EndLedger uint32 `json:"endLedger,omitempty"`
+	// Order is either "asc" (the default) or "desc". When descending, the newest events
+	// are returned first and the cursor pages towards older events.
+	Order string `json:"order,omitempty"`
+	// TxHash restricts the events to the ones of the given transaction.
+	TxHash     string             `json:"txHash,omitempty"`
+	Filters    []EventFilter      `json:"filters"`
+	Pagination *PaginationOptions `json:"pagination,omitempty"`
 }
 
 func (g *GetEventsRequest) Valid(maxLimit uint) error {
 	// Validate start
 	// Validate the paging limit (if it exists)
 	if g.Pagination != nil && g.Pagination.Cursor != nil {
-		if g.StartLedger != 0 {
+		if g.StartLedger != 0 && g.Order != EventOrderDescending {
 			return errors.New("startLedger and cursor cannot both be set")
 		}
 	} else if g.StartLedger <= 0 {
 		return errors.New("startLedger must be positive")
 	}
+	if g.EndLedger != 0 && g.EndLedger <= g.StartLedger {
+		return errors.New("endLedger must be greater than startLedger")
+	}
 	if g.Pagination != nil && g.Pagination.Limit > maxLimit {
 		return fmt.Errorf("limit must not exceed %d", maxLimit)
 	}
+	switch g.Order {
+	case "", EventOrderAscending, EventOrderDescending:
+		// ok
+	default:
+		return errors.New("if set, order must be either 'asc' or 'desc'")
+	}
+	if g.TxHash != "" {
+		if _, err := g.txHash(); err != nil {
+			return errors.Wrap(err, "txHash invalid")
+		}
+	}
+
+	return validFilters(g.Filters)
+}
+
+func (g *GetEventsRequest) txHash() (*xdr.Hash, error) {
+	if g.TxHash == "" {
+		return nil, nil
+	}
+	var txHash xdr.Hash
+	if hex.DecodedLen(len(g.TxHash)) != len(txHash) {
+		return nil, fmt.Errorf("unexpected hash length (%d)", len(g.TxHash))
+	}
+	if _, err := hex.Decode(txHash[:], []byte(g.TxHash)); err != nil {
+		return nil, err
+	}
+	return &txHash, nil
+}
+
+func (g *GetEventsRequest) Matches(event xdr.DiagnosticEvent) bool {
+	return matchesFilters(g.Filters, event)
+}
+
+// eventRange returns the range to scan, resuming after (or, when descending, before) the cursor if any
+func (g *GetEventsRequest) eventRange() events.Range {
+	eventRange := events.Range{
+		Start:      events.Cursor{Ledger: g.StartLedger},
+		ClampStart: false,
+		End:        events.MaxCursor,
+		ClampEnd:   true,
+		Descending: g.Order == EventOrderDescending,
+	}
+	if g.EndLedger != 0 {
+		eventRange.End = events.Cursor{Ledger: g.EndLedger}
+	}
+	if g.Pagination != nil && g.Pagination.Cursor != nil {
+		if eventRange.Descending {
+			// the end of the range is exclusive, so we
+			// start with the item right before the cursor
+			// and go down to the startLedger, if any
+			if g.StartLedger == 0 {
+				eventRange.Start = events.MinCursor
+			}
+			eventRange.ClampStart = true
+			eventRange.End = *g.Pagination.Cursor
+		} else {
+			eventRange.Start = *g.Pagination.Cursor
+			// increment event index because, when paginating,
+			// we start with the item right after the cursor
+			eventRange.Start.Event++
+		}
+	}
+	return eventRange
+}
 
-	// Validate filters
-	if len(g.Filters) > 5 {
+func validFilters(filters []EventFilter) error {
+	if len(filters) > 5 {
 		return errors.New("maximum 5 filters per request")
 	}
-	for i, filter := range g.Filters {
+	for i, filter := range filters {
 		if err := filter.Valid(); err != nil {
 			return errors.Wrapf(err, "filter %d invalid", i+1)
 		}
 	}
-
 	return nil
 }
 
-func (g *GetEventsRequest) Matches(event xdr.DiagnosticEvent) bool {
-	if len(g.Filters) == 0 {
+// matchesFilters returns true if the event matches any of the filters, or if there are no filters
+func matchesFilters(filters []EventFilter, event xdr.DiagnosticEvent) bool {
+	if len(filters) == 0 {
 		return true
 	}
-	for _, filter := range g.Filters {
+	for _, filter := range filters {
 		if filter.Matches(event) {
 			return true
 		}
@@ -205,36 +287,60 @@ type TopicFilter []SegmentFilter
 const minTopicCount = 1
 const maxTopicCount = 4
 
+const (
+	// singleSegmentWildcard matches exactly one topic segment
+	singleSegmentWildcard = "*"
+	// multiSegmentWildcard matches zero or more topic segments
+	multiSegmentWildcard = "**"
+)
+
 func (t *TopicFilter) Valid() error {
 	if len(*t) < minTopicCount {
 		return errors.New("topic must have at least one segment")
 	}
-	if len(*t) > maxTopicCount {
+	// multi-segment wildcards don't count towards the maximum,
+	// since they can match no segment at all
+	segmentCount := 0
+	for _, segment := range *t {
+		if !segment.isMultiSegmentWildcard() {
+			segmentCount++
+		}
+	}
+	if segmentCount > maxTopicCount {
 		return errors.New("topic cannot have more than 4 segments")
 	}
 	for i, segment := range *t {
 		if err := segment.Valid(); err != nil {
 			return errors.Wrapf(err, "segment %d invalid", i+1)
 		}
+		if i > 0 && segment.isMultiSegmentWildcard() && (*t)[i-1].isMultiSegmentWildcard() {
+			return errors.New("topic cannot have consecutive '**' segments")
+		}
 	}
 	return nil
 }
 
 // An event matches a topic filter iff:
-//   - the event has EXACTLY as many topic segments as the filter AND
+//   - the event has EXACTLY as many topic segments as the filter, once
+//     each '**' segment is expanded to zero or more segments AND
 //   - each segment either: matches exactly OR is a wildcard.
 func (t TopicFilter) Matches(event []xdr.ScVal) bool {
-	if len(event) != len(t) {
-		return false
-	}
-
 	for i, segmentFilter := range t {
-		if !segmentFilter.Matches(event[i]) {
+		if segmentFilter.isMultiSegmentWildcard() {
+			rest := t[i+1:]
+			for j := i; j <= len(event); j++ {
+				if rest.Matches(event[j:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if i >= len(event) || !segmentFilter.Matches(event[i]) {
 			return false
 		}
 	}
 
-	return true
+	return len(event) == len(t)
 }
 
 type SegmentFilter struct {
@@ -242,8 +348,12 @@ type SegmentFilter struct {
 	scval    *xdr.ScVal
 }
 
+func (s *SegmentFilter) isMultiSegmentWildcard() bool {
+	return s.wildcard != nil && *s.wildcard == multiSegmentWildcard
+}
+
 func (s *SegmentFilter) Matches(segment xdr.ScVal) bool {
-	if s.wildcard != nil && *s.wildcard == "*" {
+	if s.wildcard != nil {
 		return true
 	} else if s.scval != nil {
 		if !s.scval.Equals(segment) {
@@ -263,8 +373,8 @@ func (s *SegmentFilter) Valid() error {
 	if s.wildcard == nil && s.scval == nil {
 		return errors.New("must set either wildcard or scval")
 	}
-	if s.wildcard != nil && *s.wildcard != "*" {
-		return errors.New("wildcard must be '*'")
+	if s.wildcard != nil && *s.wildcard != singleSegmentWildcard && *s.wildcard != multiSegmentWildcard {
+		return errors.New("wildcard must be '*' or '**'")
 	}
 	return nil
 }
@@ -277,7 +387,7 @@ func (s *SegmentFilter) UnmarshalJSON(p []byte) error {
 	if err := json.Unmarshal(p, &tmp); err != nil {
 		return err
 	}
-	if tmp == "*" {
+	if tmp == singleSegmentWildcard || tmp == multiSegmentWildcard {
 		s.wildcard = &tmp
 	} else {
 		var out xdr.ScVal
@@ -317,19 +427,12 @@ func (h eventsRPCHandler) getEvents(request GetEventsRequest) (GetEventsResponse
 		}
 	}
 
-	start := events.Cursor{Ledger: uint32(request.StartLedger)}
 	limit := h.defaultLimit
-	if request.Pagination != nil {
-		if request.Pagination.Cursor != nil {
-			start = *request.Pagination.Cursor
-			// increment event index because, when paginating,
-			// we start with the item right after the cursor
-			start.Event++
-		}
-		if request.Pagination.Limit > 0 {
-			limit = request.Pagination.Limit
-		}
+	if request.Pagination != nil && request.Pagination.Limit > 0 {
+		limit = request.Pagination.Limit
 	}
+	// the hash was already validated
+	txHash, _ := request.txHash()
 
 	type entry struct {
 		cursor               events.Cursor
@@ -339,15 +442,13 @@ func (h eventsRPCHandler) getEvents(request GetEventsRequest) (GetEventsResponse
 	}
 	var found []entry
 	latestLedger, err := h.scanner.Scan(
-		events.Range{
-			Start:      start,
-			ClampStart: false,
-			End:        events.MaxCursor,
-			ClampEnd:   true,
-		},
-		func(event xdr.DiagnosticEvent, cursor events.Cursor, ledgerCloseTimestamp int64, txHash *xdr.Hash) bool {
+		request.eventRange(),
+		func(event xdr.DiagnosticEvent, cursor events.Cursor, ledgerCloseTimestamp int64, eventTxHash *xdr.Hash) bool {
+			if txHash != nil && (eventTxHash == nil || *eventTxHash != *txHash) {
+				return true
+			}
 			if request.Matches(event) {
-				found = append(found, entry{cursor, ledgerCloseTimestamp, event, txHash})
+				found = append(found, entry{cursor, ledgerCloseTimestamp, event, eventTxHash})
 			}
 			return uint(len(found)) < limit
 		},
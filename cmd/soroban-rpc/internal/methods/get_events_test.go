@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
@@ -205,6 +206,7 @@ func TestTopicFilterMatches(t *testing.T) {
 		U64:  &sixtyfour,
 	}
 	star := "*"
+	starStar := "**"
 	for _, tc := range []struct {
 		name     string
 		filter   TopicFilter
@@ -330,6 +332,84 @@ func TestTopicFilterMatches(t *testing.T) {
 				{transfer, number, transfer},
 			},
 		},
+
+		// Multi-segment wildcard
+		{
+			name: "**",
+			filter: []SegmentFilter{
+				{wildcard: &starStar},
+			},
+			includes: []xdr.ScVec{
+				{},
+				{transfer},
+				{transfer, number, transfer, number},
+			},
+		},
+		{
+			name: "transfer/**",
+			filter: []SegmentFilter{
+				{scval: &transfer},
+				{wildcard: &starStar},
+			},
+			includes: []xdr.ScVec{
+				{transfer},
+				{transfer, number},
+				{transfer, number, number},
+			},
+			excludes: []xdr.ScVec{
+				{},
+				{number, transfer},
+			},
+		},
+		{
+			name: "**/transfer",
+			filter: []SegmentFilter{
+				{wildcard: &starStar},
+				{scval: &transfer},
+			},
+			includes: []xdr.ScVec{
+				{transfer},
+				{number, transfer},
+				{number, number, transfer},
+			},
+			excludes: []xdr.ScVec{
+				{},
+				{transfer, number},
+			},
+		},
+		{
+			name: "*/**/transfer",
+			filter: []SegmentFilter{
+				{wildcard: &star},
+				{wildcard: &starStar},
+				{scval: &transfer},
+			},
+			includes: []xdr.ScVec{
+				{number, transfer},
+				{number, number, number, transfer},
+			},
+			excludes: []xdr.ScVec{
+				{transfer},
+				{number, transfer, number},
+			},
+		},
+		{
+			name: "transfer/**/transfer/**",
+			filter: []SegmentFilter{
+				{scval: &transfer},
+				{wildcard: &starStar},
+				{scval: &transfer},
+				{wildcard: &starStar},
+			},
+			includes: []xdr.ScVec{
+				{transfer, transfer},
+				{transfer, number, transfer, number},
+			},
+			excludes: []xdr.ScVec{
+				{transfer},
+				{transfer, number, number},
+			},
+		},
 	} {
 		name := tc.name
 		if name == "" {
@@ -368,6 +448,10 @@ func TestTopicFilterJSON(t *testing.T) {
 	assert.NoError(t, json.Unmarshal([]byte("[\"*\"]"), &got))
 	assert.Equal(t, TopicFilter{{wildcard: &star}}, got)
 
+	starStar := "**"
+	assert.NoError(t, json.Unmarshal([]byte("[\"**\"]"), &got))
+	assert.Equal(t, TopicFilter{{wildcard: &starStar}}, got)
+
 	sixtyfour := xdr.Uint64(64)
 	scval := xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &sixtyfour}
 	scvalstr, err := xdr.MarshalBase64(scval)
@@ -413,6 +497,14 @@ func TestGetEventsRequestValid(t *testing.T) {
 		Pagination:  &PaginationOptions{Cursor: &events.Cursor{}},
 	}).Valid(1000), "startLedger and cursor cannot both be set")
 
+	// descending pages keep the startLedger as lower bound
+	assert.NoError(t, (&GetEventsRequest{
+		StartLedger: 1,
+		Order:       EventOrderDescending,
+		Filters:     []EventFilter{},
+		Pagination:  &PaginationOptions{Cursor: &events.Cursor{Ledger: 2}},
+	}).Valid(1000))
+
 	assert.NoError(t, (&GetEventsRequest{
 		StartLedger: 1,
 		Filters:     []EventFilter{},
@@ -515,6 +607,65 @@ func TestGetEventsRequestValid(t *testing.T) {
 		},
 		Pagination: nil,
 	}).Valid(1000), "filter 1 invalid: topic 1 invalid: topic cannot have more than 4 segments")
+
+	star := "*"
+	starStar := "**"
+	assert.NoError(t, (&GetEventsRequest{
+		StartLedger: 1,
+		Filters: []EventFilter{
+			{Topics: []TopicFilter{
+				{
+					{wildcard: &starStar},
+					{wildcard: &star},
+					{wildcard: &star},
+					{wildcard: &star},
+					{wildcard: &star},
+					{wildcard: &starStar},
+				},
+			}},
+		},
+		Pagination: nil,
+	}).Valid(1000))
+
+	assert.EqualError(t, (&GetEventsRequest{
+		StartLedger: 1,
+		Filters: []EventFilter{
+			{Topics: []TopicFilter{
+				{
+					{wildcard: &star},
+					{wildcard: &starStar},
+					{wildcard: &starStar},
+				},
+			}},
+		},
+		Pagination: nil,
+	}).Valid(1000), "filter 1 invalid: topic 1 invalid: topic cannot have consecutive '**' segments")
+
+	assert.NoError(t, (&GetEventsRequest{
+		StartLedger: 1,
+		EndLedger:   2,
+		Order:       EventOrderDescending,
+		TxHash:      strings.Repeat("ab", 32),
+		Filters:     []EventFilter{},
+	}).Valid(1000))
+
+	assert.EqualError(t, (&GetEventsRequest{
+		StartLedger: 2,
+		EndLedger:   2,
+		Filters:     []EventFilter{},
+	}).Valid(1000), "endLedger must be greater than startLedger")
+
+	assert.EqualError(t, (&GetEventsRequest{
+		StartLedger: 1,
+		Order:       "random",
+		Filters:     []EventFilter{},
+	}).Valid(1000), "if set, order must be either 'asc' or 'desc'")
+
+	assert.EqualError(t, (&GetEventsRequest{
+		StartLedger: 1,
+		TxHash:      "ab",
+		Filters:     []EventFilter{},
+	}).Valid(1000), "txHash invalid: unexpected hash length (2)")
 }
 
 func TestGetEvents(t *testing.T) {
@@ -1056,6 +1207,100 @@ func TestGetEvents(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, GetEventsResponse{[]EventInfo{}, 5}, results)
 	})
+
+	t.Run("with order, endLedger and txHash", func(t *testing.T) {
+		store := events.NewMemoryStore(interfaces.MakeNoOpDeamon(), "unit-tests", 100)
+		contractID := xdr.Hash([32]byte{})
+		var txHashes []string
+		for i := uint32(5); i <= 7; i++ {
+			ledgerCloseMeta := ledgerCloseMetaWithEvents(i, now.Unix(), transactionMetaWithEvents(
+				contractEvent(contractID, xdr.ScVec{counterScVal}, counterScVal),
+				contractEvent(contractID, xdr.ScVec{counterScVal}, counterScVal),
+			))
+			assert.NoError(t, store.IngestEvents(ledgerCloseMeta))
+			txHashes = append(txHashes, ledgerCloseMeta.TransactionHash(0).HexString())
+		}
+		handler := eventsRPCHandler{
+			scanner:      store,
+			maxLimit:     10000,
+			defaultLimit: 100,
+		}
+		eventIDs := func(response GetEventsResponse) []string {
+			var ids []string
+			for _, event := range response.Events {
+				ids = append(ids, event.ID)
+			}
+			return ids
+		}
+		id := func(ledger, event uint32) string {
+			return events.Cursor{Ledger: ledger, Tx: 1, Event: event}.String()
+		}
+
+		results, err := handler.getEvents(GetEventsRequest{
+			StartLedger: 6,
+			Order:       EventOrderDescending,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{id(7, 1), id(7, 0), id(6, 1), id(6, 0)}, eventIDs(results))
+		assert.Equal(t, int64(7), results.LatestLedger)
+
+		// paging backwards from a cursor includes the ledgers before the original startLedger
+		results, err = handler.getEvents(GetEventsRequest{
+			Order: EventOrderDescending,
+			Pagination: &PaginationOptions{
+				Cursor: &events.Cursor{Ledger: 6, Tx: 1, Event: 0},
+				Limit:  1,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{id(5, 1)}, eventIDs(results))
+
+		results, err = handler.getEvents(GetEventsRequest{
+			Order: EventOrderDescending,
+			Pagination: &PaginationOptions{
+				Cursor: &events.Cursor{Ledger: 5, Tx: 1, Event: 0},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []EventInfo{}, results.Events)
+
+		// paging backwards with a startLedger stops at it
+		var pagedIDs []string
+		request := GetEventsRequest{
+			StartLedger: 6,
+			Order:       EventOrderDescending,
+			Pagination:  &PaginationOptions{Limit: 1},
+		}
+		for {
+			results, err = handler.getEvents(request)
+			require.NoError(t, err)
+			if len(results.Events) == 0 {
+				break
+			}
+			pagedIDs = append(pagedIDs, eventIDs(results)...)
+			cursor, err := events.ParseCursor(results.Events[0].PagingToken)
+			require.NoError(t, err)
+			request.Pagination.Cursor = &cursor
+		}
+		assert.Equal(t, []string{id(7, 1), id(7, 0), id(6, 1), id(6, 0)}, pagedIDs)
+
+		results, err = handler.getEvents(GetEventsRequest{
+			StartLedger: 5,
+			EndLedger:   7,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{id(5, 0), id(5, 1), id(6, 0), id(6, 1)}, eventIDs(results))
+
+		results, err = handler.getEvents(GetEventsRequest{
+			StartLedger: 5,
+			TxHash:      txHashes[1],
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{id(6, 0), id(6, 1)}, eventIDs(results))
+		for _, event := range results.Events {
+			assert.Equal(t, txHashes[1], event.TransactionHash)
+		}
+	})
 }
 
 func ledgerCloseMetaWithEvents(sequence uint32, closeTimestamp int64, txMeta ...xdr.TransactionMeta) xdr.LedgerCloseMeta {
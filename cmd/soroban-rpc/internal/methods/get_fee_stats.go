@@ -0,0 +1,84 @@
+package methods
+
+import (
+	"context"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/transactions"
+)
+
+// FeeDistribution is the distribution of the inclusion fees (per operation) over the ledger window
+type FeeDistribution struct {
+	Max              uint64 `json:"max,string"`
+	Min              uint64 `json:"min,string"`
+	Mode             uint64 `json:"mode,string"`
+	P10              uint64 `json:"p10,string"`
+	P20              uint64 `json:"p20,string"`
+	P30              uint64 `json:"p30,string"`
+	P40              uint64 `json:"p40,string"`
+	P50              uint64 `json:"p50,string"`
+	P60              uint64 `json:"p60,string"`
+	P70              uint64 `json:"p70,string"`
+	P80              uint64 `json:"p80,string"`
+	P90              uint64 `json:"p90,string"`
+	P95              uint64 `json:"p95,string"`
+	P99              uint64 `json:"p99,string"`
+	TransactionCount uint32 `json:"transactionCount,string"`
+	LedgerCount      uint32 `json:"ledgerCount"`
+}
+
+func convertFeeDistribution(distribution transactions.FeeDistribution) FeeDistribution {
+	return FeeDistribution{
+		Max:              distribution.Max,
+		Min:              distribution.Min,
+		Mode:             distribution.Mode,
+		P10:              distribution.P10,
+		P20:              distribution.P20,
+		P30:              distribution.P30,
+		P40:              distribution.P40,
+		P50:              distribution.P50,
+		P60:              distribution.P60,
+		P70:              distribution.P70,
+		P80:              distribution.P80,
+		P90:              distribution.P90,
+		P95:              distribution.P95,
+		P99:              distribution.P99,
+		TransactionCount: distribution.TransactionCount,
+		LedgerCount:      distribution.LedgerCount,
+	}
+}
+
+// GetFeeStatsResponse is the response for the Soroban-RPC getFeeStats() endpoint
+type GetFeeStatsResponse struct {
+	// SorobanInclusionFee is the distribution of the inclusion fees of Soroban transactions,
+	// excluding their resource fees.
+	SorobanInclusionFee FeeDistribution `json:"sorobanInclusionFee"`
+	// InclusionFee is the distribution of the fees charged to classic transactions.
+	InclusionFee FeeDistribution `json:"inclusionFee"`
+	// LatestLedger is the latest ledger taken into account.
+	LatestLedger uint32 `json:"latestLedger"`
+}
+
+type feeStatsGetter interface {
+	GetFeeStats(ledgerWindow uint32) transactions.FeeStats
+}
+
+// NewGetFeeStatsHandler returns a json rpc handler to fetch the inclusion fee distributions of the latest ledgers
+func NewGetFeeStatsHandler(store feeStatsGetter, ledgerWindow uint32) jrpc2.Handler {
+	return handler.New(func(ctx context.Context) (GetFeeStatsResponse, error) {
+		stats := store.GetFeeStats(ledgerWindow)
+		if stats.LatestLedger == 0 {
+			return GetFeeStatsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "no ledgers have been ingested yet",
+			}
+		}
+		return GetFeeStatsResponse{
+			SorobanInclusionFee: convertFeeDistribution(stats.SorobanInclusionFee),
+			InclusionFee:        convertFeeDistribution(stats.InclusionFee),
+			LatestLedger:        stats.LatestLedger,
+		}, nil
+	})
+}
@@ -0,0 +1,50 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/transactions"
+)
+
+type fixedFeeStats struct {
+	stats        transactions.FeeStats
+	ledgerWindow uint32
+}
+
+func (f *fixedFeeStats) GetFeeStats(ledgerWindow uint32) transactions.FeeStats {
+	f.ledgerWindow = ledgerWindow
+	return f.stats
+}
+
+func TestGetFeeStats(t *testing.T) {
+	store := &fixedFeeStats{}
+	handler := NewGetFeeStatsHandler(store, 20)
+	// nothing ingested yet
+	_, err := handler(context.Background(), &jrpc2.Request{})
+	require.Error(t, err)
+
+	store.stats = transactions.FeeStats{
+		SorobanInclusionFee: transactions.FeeDistribution{Max: 300, Min: 100, Mode: 100, P50: 200, TransactionCount: 3, LedgerCount: 20},
+		InclusionFee:        transactions.FeeDistribution{Max: 100, Min: 100, Mode: 100, P50: 100, TransactionCount: 1, LedgerCount: 20},
+		LatestLedger:        42,
+	}
+	result, err := handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	require.Equal(t, uint32(20), store.ledgerWindow)
+
+	encoded, err := json.Marshal(result)
+	require.NoError(t, err)
+	var response GetFeeStatsResponse
+	require.NoError(t, json.Unmarshal(encoded, &response))
+	require.Equal(t, uint32(42), response.LatestLedger)
+	require.Equal(t, uint64(300), response.SorobanInclusionFee.Max)
+	require.Equal(t, uint64(200), response.SorobanInclusionFee.P50)
+	require.Equal(t, uint32(3), response.SorobanInclusionFee.TransactionCount)
+	require.Equal(t, uint64(100), response.InclusionFee.Mode)
+	require.Contains(t, string(encoded), `"max":"300"`)
+}
@@ -58,6 +58,18 @@ func (ledgerReader *ConstantLedgerReader) StreamAllLedgers(ctx context.Context,
 	return nil
 }
 
+func (ledgerReader *ConstantLedgerReader) BatchGetLedgers(ctx context.Context, startSequence uint32, limit uint) ([]xdr.LedgerCloseMeta, error) {
+	return nil, nil
+}
+
+func (ledgerReader *ConstantLedgerReader) GetLedgerSequenceRange(ctx context.Context) (uint32, uint32, error) {
+	return 0, 0, nil
+}
+
+func (ledgerReader *ConstantLedgerReader) GetLedgerRange(ctx context.Context) (db.LedgerRange, error) {
+	return db.LedgerRange{}, nil
+}
+
 func createLedger(ledgerSequence uint32, protocolVersion uint32, hash byte) xdr.LedgerCloseMeta {
 	return xdr.LedgerCloseMeta{
 		V: 1,
@@ -2,6 +2,7 @@ package methods
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/creachadair/jrpc2"
@@ -16,6 +17,9 @@ var ErrLedgerTtlEntriesCannotBeQueriedDirectly = "ledger ttl entries cannot be q
 
 type GetLedgerEntriesRequest struct {
 	Keys []string `json:"keys"`
+	// Ledger is an optional past ledger (within the ledger retention window). If set, the entries
+	// are returned as they were right after that ledger closed.
+	Ledger uint32 `json:"ledger,omitempty"`
 }
 
 type LedgerEntryResult struct {
@@ -34,12 +38,15 @@ type GetLedgerEntriesResponse struct {
 	Entries []LedgerEntryResult `json:"entries"`
 	// Sequence number of the latest ledger at time of request.
 	LatestLedger uint32 `json:"latestLedger"`
+	// Sequence number of the ledger the entries reflect, the requested ledger if any or the latest one.
+	Ledger uint32 `json:"ledger"`
 }
 
 const getLedgerEntriesMaxKeys = 200
 
 // NewGetLedgerEntriesHandler returns a JSON RPC handler to retrieve the specified ledger entries from Stellar Core.
-func NewGetLedgerEntriesHandler(logger *log.Entry, ledgerEntryReader db.LedgerEntryReader) jrpc2.Handler {
+// Historical entries are reconstructed from the ledgers provided by the ledger reader.
+func NewGetLedgerEntriesHandler(logger *log.Entry, ledgerEntryReader db.LedgerEntryReader, ledgerReader db.LedgerReader, maxHistoricalLedgerDepth uint32) jrpc2.Handler {
 	return handler.New(func(ctx context.Context, request GetLedgerEntriesRequest) (GetLedgerEntriesResponse, error) {
 		if len(request.Keys) > getLedgerEntriesMaxKeys {
 			return GetLedgerEntriesResponse{}, &jrpc2.Error{
@@ -88,9 +95,38 @@ func NewGetLedgerEntriesHandler(logger *log.Entry, ledgerEntryReader db.LedgerEn
 			}
 		}
 
+		var entriesTx db.LedgerEntryReadTx = tx
+		ledger := latestLedger
+		if request.Ledger != 0 && request.Ledger != latestLedger {
+			historicalTx, err := db.NewHistoricalLedgerEntryTx(ctx, tx, ledgerReader, request.Ledger, maxHistoricalLedgerDepth)
+			var outOfRangeErr *db.LedgerOutOfRangeError
+			if errors.As(err, &outOfRangeErr) {
+				return GetLedgerEntriesResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InvalidParams,
+					Message: outOfRangeErr.Error(),
+				}
+			} else if err != nil {
+				logger.WithError(err).WithField("request", request).
+					Info("could not obtain the ledger range from storage")
+				return GetLedgerEntriesResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InternalError,
+					Message: "could not obtain the ledger range from storage",
+				}
+			}
+			entriesTx = historicalTx
+			ledger = request.Ledger
+		}
+
 		ledgerEntryResults := make([]LedgerEntryResult, 0, len(ledgerKeys))
-		ledgerKeysAndEntries, err := tx.GetLedgerEntries(ledgerKeys...)
-		if err != nil {
+		ledgerKeysAndEntries, err := entriesTx.GetLedgerEntries(ledgerKeys...)
+		if err != nil && entriesTx != tx {
+			logger.WithError(err).WithField("request", request).
+				Info("could not reconstruct historical ledger entries")
+			return GetLedgerEntriesResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: fmt.Sprintf("could not reconstruct ledger entries at ledger %d: %v", request.Ledger, err),
+			}
+		} else if err != nil {
 			logger.WithError(err).WithField("request", request).
 				Info("could not obtain ledger entries from storage")
 			return GetLedgerEntriesResponse{}, &jrpc2.Error{
@@ -131,6 +167,7 @@ func NewGetLedgerEntriesHandler(logger *log.Entry, ledgerEntryReader db.LedgerEn
 		response := GetLedgerEntriesResponse{
 			Entries:      ledgerEntryResults,
 			LatestLedger: uint32(latestLedger),
+			Ledger:       ledger,
 		}
 		return response, nil
 	})
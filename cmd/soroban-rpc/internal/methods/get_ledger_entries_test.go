@@ -0,0 +1,48 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLedgerEntriesAtLedger(t *testing.T) {
+	handler := NewGetLedgerEntriesHandler(log.DefaultLogger, &ConstantLedgerEntryReader{},
+		&retainedLedgerReader{firstLedger: expectedLatestLedgerSequence - 10}, 100)
+	key, err := xdr.MarshalBase64(xdr.LedgerKey{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress(keypair.MustRandom().Address())},
+	})
+	require.NoError(t, err)
+	getLedgerEntries := func(params string) (GetLedgerEntriesResponse, error) {
+		requests, err := jrpc2.ParseRequests([]byte(`{"jsonrpc":"2.0","id":1,"method":"getLedgerEntries","params":` + params + `}`))
+		require.NoError(t, err)
+		result, err := handler(context.Background(), requests[0].ToRequest())
+		if err != nil {
+			return GetLedgerEntriesResponse{}, err
+		}
+		return result.(GetLedgerEntriesResponse), nil
+	}
+
+	response, err := getLedgerEntries(`{"keys":["` + key + `"]}`)
+	require.NoError(t, err)
+	assert.Empty(t, response.Entries)
+	assert.Equal(t, expectedLatestLedgerSequence, response.LatestLedger)
+	assert.Equal(t, expectedLatestLedgerSequence, response.Ledger)
+
+	response, err = getLedgerEntries(`{"keys":["` + key + `"],"ledger":955}`)
+	require.NoError(t, err)
+	assert.Empty(t, response.Entries)
+	assert.Equal(t, expectedLatestLedgerSequence, response.LatestLedger)
+	assert.Equal(t, uint32(955), response.Ledger)
+
+	_, err = getLedgerEntries(`{"keys":["` + key + `"],"ledger":949}`)
+	require.Error(t, err)
+	assert.Equal(t, jrpc2.InvalidParams, err.(*jrpc2.Error).Code)
+}
@@ -0,0 +1,226 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// LedgersPaginationOptions defines the available options for paginating through ledgers.
+type LedgersPaginationOptions struct {
+	// Cursor is the paging token of the last ledger returned by a previous request.
+	Cursor string `json:"cursor,omitempty"`
+	Limit  uint   `json:"limit,omitempty"`
+}
+
+type GetLedgersRequest struct {
+	StartLedger uint32                    `json:"startLedger,omitempty"`
+	Pagination  *LedgersPaginationOptions `json:"pagination,omitempty"`
+	// IncludeMeta adds the LedgerCloseMeta XDR of each ledger.
+	IncludeMeta bool `json:"includeMeta,omitempty"`
+	// IncludeSummary adds a decoded summary of the header and transactions of each ledger.
+	IncludeSummary bool `json:"includeSummary,omitempty"`
+}
+
+func (r GetLedgersRequest) valid(maxLimit uint) error {
+	if r.Pagination != nil && r.Pagination.Cursor != "" {
+		if r.StartLedger != 0 {
+			return errors.New("startLedger and cursor cannot both be set")
+		}
+	} else if r.StartLedger <= 0 {
+		return errors.New("startLedger must be positive")
+	}
+	if r.Pagination != nil && r.Pagination.Limit > maxLimit {
+		return fmt.Errorf("limit must not exceed %d", maxLimit)
+	}
+	return nil
+}
+
+// LedgerSummary is the decoded header and transaction counts of a ledger
+type LedgerSummary struct {
+	PreviousHash               string `json:"previousHash"`
+	ProtocolVersion            uint32 `json:"protocolVersion"`
+	BaseFee                    uint32 `json:"baseFee"`
+	BaseReserve                uint32 `json:"baseReserve"`
+	MaxTxSetSize               uint32 `json:"maxTxSetSize"`
+	TotalCoins                 int64  `json:"totalCoins,string"`
+	FeePool                    int64  `json:"feePool,string"`
+	TransactionCount           int    `json:"transactionCount"`
+	SuccessfulTransactionCount int    `json:"successfulTransactionCount"`
+	FailedTransactionCount     int    `json:"failedTransactionCount"`
+	OperationCount             int    `json:"operationCount"`
+}
+
+// LedgerInfo is a ledger of the getLedgers() response
+type LedgerInfo struct {
+	// Hash of the ledger as a hex-encoded string
+	Hash     string `json:"hash"`
+	Sequence uint32 `json:"sequence"`
+	// LedgerCloseTime is the unix timestamp of when the ledger was closed.
+	LedgerCloseTime int64 `json:"ledgerCloseTime,string"`
+	// LedgerHeaderXdr is the LedgerHeaderHistoryEntry XDR value.
+	LedgerHeaderXdr string `json:"headerXdr"`
+	// LedgerMetadataXdr is the LedgerCloseMeta XDR value, only present if requested.
+	LedgerMetadataXdr string `json:"metadataXdr,omitempty"`
+	// Summary is only present if requested.
+	Summary *LedgerSummary `json:"summary,omitempty"`
+}
+
+// GetLedgersResponse is the response for the Soroban-RPC getLedgers() endpoint
+type GetLedgersResponse struct {
+	Ledgers []LedgerInfo `json:"ledgers"`
+	// LatestLedger is the latest ledger stored in Soroban-RPC.
+	LatestLedger uint32 `json:"latestLedger"`
+	// LatestLedgerCloseTime is the unix timestamp of when the latest ledger was closed.
+	LatestLedgerCloseTime int64 `json:"latestLedgerCloseTime,string"`
+	// OldestLedger is the oldest ledger stored in Soroban-RPC.
+	OldestLedger uint32 `json:"oldestLedger"`
+	// OldestLedgerCloseTime is the unix timestamp of when the oldest ledger was closed.
+	OldestLedgerCloseTime int64 `json:"oldestLedgerCloseTime,string"`
+	// Cursor is the paging token of the last returned ledger, to be used for the next request.
+	Cursor string `json:"cursor"`
+}
+
+type ledgersRPCHandler struct {
+	ledgerReader db.LedgerReader
+	maxLimit     uint
+	defaultLimit uint
+}
+
+func (h ledgersRPCHandler) getLedgers(ctx context.Context, request GetLedgersRequest) (GetLedgersResponse, error) {
+	if err := request.valid(h.maxLimit); err != nil {
+		return GetLedgersResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+
+	ledgerRange, err := h.ledgerReader.GetLedgerRange(ctx)
+	if err != nil {
+		return GetLedgersResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+
+	startLedger := request.StartLedger
+	limit := h.defaultLimit
+	if request.Pagination != nil {
+		if request.Pagination.Cursor != "" {
+			cursor, err := strconv.ParseUint(request.Pagination.Cursor, 10, 32)
+			// there are no ledgers after the last sequence number, and the next one would wrap around
+			if err != nil || cursor >= math.MaxUint32 {
+				return GetLedgersResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InvalidParams,
+					Message: fmt.Sprintf("invalid cursor %q", request.Pagination.Cursor),
+				}
+			}
+			// when paginating, we start with the ledger right after the cursor
+			startLedger = uint32(cursor) + 1
+		}
+		if request.Pagination.Limit > 0 {
+			limit = request.Pagination.Limit
+		}
+	}
+	if request.StartLedger != 0 &&
+		(request.StartLedger < ledgerRange.FirstLedger.Sequence || request.StartLedger > ledgerRange.LastLedger.Sequence) {
+		return GetLedgersResponse{}, &jrpc2.Error{
+			Code: jrpc2.InvalidParams,
+			Message: fmt.Sprintf("startLedger must be between the oldest ledger: %d and the latest ledger: %d",
+				ledgerRange.FirstLedger.Sequence, ledgerRange.LastLedger.Sequence),
+		}
+	}
+
+	ledgers, err := h.ledgerReader.BatchGetLedgers(ctx, startLedger, limit)
+	if err != nil {
+		return GetLedgersResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+
+	response := GetLedgersResponse{
+		Ledgers:               make([]LedgerInfo, 0, len(ledgers)),
+		LatestLedger:          ledgerRange.LastLedger.Sequence,
+		LatestLedgerCloseTime: ledgerRange.LastLedger.CloseTime,
+		OldestLedger:          ledgerRange.FirstLedger.Sequence,
+		OldestLedgerCloseTime: ledgerRange.FirstLedger.CloseTime,
+		Cursor:                strconv.FormatUint(uint64(startLedger-1), 10),
+	}
+	for _, ledger := range ledgers {
+		info, err := ledgerInfoForLedger(ledger, request.IncludeMeta, request.IncludeSummary)
+		if err != nil {
+			return GetLedgersResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		response.Ledgers = append(response.Ledgers, info)
+		response.Cursor = strconv.FormatUint(uint64(info.Sequence), 10)
+	}
+	return response, nil
+}
+
+func ledgerInfoForLedger(ledger xdr.LedgerCloseMeta, includeMeta bool, includeSummary bool) (LedgerInfo, error) {
+	headerEntry := ledger.LedgerHeaderHistoryEntry()
+	headerXdr, err := xdr.MarshalBase64(headerEntry)
+	if err != nil {
+		return LedgerInfo{}, errors.Wrap(err, "could not marshal ledger header")
+	}
+	info := LedgerInfo{
+		Hash:            ledger.LedgerHash().HexString(),
+		Sequence:        ledger.LedgerSequence(),
+		LedgerCloseTime: int64(headerEntry.Header.ScpValue.CloseTime),
+		LedgerHeaderXdr: headerXdr,
+	}
+	if includeMeta {
+		if info.LedgerMetadataXdr, err = xdr.MarshalBase64(ledger); err != nil {
+			return LedgerInfo{}, errors.Wrap(err, "could not marshal ledger close meta")
+		}
+	}
+	if includeSummary {
+		header := headerEntry.Header
+		summary := LedgerSummary{
+			PreviousHash:     ledger.PreviousLedgerHash().HexString(),
+			ProtocolVersion:  ledger.ProtocolVersion(),
+			BaseFee:          uint32(header.BaseFee),
+			BaseReserve:      uint32(header.BaseReserve),
+			MaxTxSetSize:     uint32(header.MaxTxSetSize),
+			TotalCoins:       int64(header.TotalCoins),
+			FeePool:          int64(header.FeePool),
+			TransactionCount: ledger.CountTransactions(),
+		}
+		for i := 0; i < summary.TransactionCount; i++ {
+			if ledger.TransactionResultPair(i).Result.Successful() {
+				summary.SuccessfulTransactionCount++
+			} else {
+				summary.FailedTransactionCount++
+			}
+		}
+		for _, envelope := range ledger.TransactionEnvelopes() {
+			summary.OperationCount += len(envelope.Operations())
+		}
+		info.Summary = &summary
+	}
+	return info, nil
+}
+
+// NewGetLedgersHandler returns a json rpc handler to fetch ledgers in sequence order
+func NewGetLedgersHandler(ledgerReader db.LedgerReader, maxLimit, defaultLimit uint) jrpc2.Handler {
+	ledgersHandler := ledgersRPCHandler{
+		ledgerReader: ledgerReader,
+		maxLimit:     maxLimit,
+		defaultLimit: defaultLimit,
+	}
+	return handler.New(func(ctx context.Context, request GetLedgersRequest) (GetLedgersResponse, error) {
+		return ledgersHandler.getLedgers(ctx, request)
+	})
+}
@@ -0,0 +1,87 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+type rangeLedgerReader struct {
+	ConstantLedgerReader
+	first, last uint32
+}
+
+func (r rangeLedgerReader) GetLedgerSequenceRange(ctx context.Context) (uint32, uint32, error) {
+	return r.first, r.last, nil
+}
+
+func (r rangeLedgerReader) GetLedgerRange(ctx context.Context) (db.LedgerRange, error) {
+	return db.LedgerRange{
+		FirstLedger: db.LedgerSeqAndCloseTime{Sequence: r.first},
+		LastLedger:  db.LedgerSeqAndCloseTime{Sequence: r.last},
+	}, nil
+}
+
+func (r rangeLedgerReader) BatchGetLedgers(ctx context.Context, startSequence uint32, limit uint) ([]xdr.LedgerCloseMeta, error) {
+	var ledgers []xdr.LedgerCloseMeta
+	for sequence := startSequence; sequence <= r.last && uint(len(ledgers)) < limit; sequence++ {
+		ledger := createLedger(sequence, expectedLatestLedgerProtocolVersion, byte(sequence))
+		ledger.V1.TxSet = xdr.GeneralizedTransactionSet{V: 1, V1TxSet: &xdr.TransactionSetV1{}}
+		ledgers = append(ledgers, ledger)
+	}
+	return ledgers, nil
+}
+
+func TestGetLedgers(t *testing.T) {
+	handler := ledgersRPCHandler{
+		ledgerReader: &rangeLedgerReader{first: 10, last: 14},
+		maxLimit:     100,
+		defaultLimit: 3,
+	}
+	ctx := context.Background()
+
+	_, err := handler.getLedgers(ctx, GetLedgersRequest{})
+	require.EqualError(t, err, "[-32602] startLedger must be positive")
+	_, err = handler.getLedgers(ctx, GetLedgersRequest{StartLedger: 15})
+	require.EqualError(t, err, "[-32602] startLedger must be between the oldest ledger: 10 and the latest ledger: 14")
+	_, err = handler.getLedgers(ctx, GetLedgersRequest{StartLedger: 10, Pagination: &LedgersPaginationOptions{Cursor: "x"}})
+	require.EqualError(t, err, "[-32602] startLedger and cursor cannot both be set")
+
+	response, err := handler.getLedgers(ctx, GetLedgersRequest{StartLedger: 10})
+	require.NoError(t, err)
+	require.Equal(t, uint32(10), response.OldestLedger)
+	require.Equal(t, uint32(14), response.LatestLedger)
+	require.Len(t, response.Ledgers, 3)
+	require.Equal(t, uint32(10), response.Ledgers[0].Sequence)
+	require.Equal(t, xdr.Hash{10}.HexString(), response.Ledgers[0].Hash)
+	require.Empty(t, response.Ledgers[0].LedgerMetadataXdr)
+	require.Nil(t, response.Ledgers[0].Summary)
+	var header xdr.LedgerHeaderHistoryEntry
+	require.NoError(t, xdr.SafeUnmarshalBase64(response.Ledgers[0].LedgerHeaderXdr, &header))
+	require.Equal(t, xdr.Uint32(10), header.Header.LedgerSeq)
+	require.Equal(t, "12", response.Cursor)
+
+	response, err = handler.getLedgers(ctx, GetLedgersRequest{
+		Pagination:     &LedgersPaginationOptions{Cursor: response.Cursor},
+		IncludeMeta:    true,
+		IncludeSummary: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Ledgers, 2)
+	require.Equal(t, uint32(13), response.Ledgers[0].Sequence)
+	var meta xdr.LedgerCloseMeta
+	require.NoError(t, xdr.SafeUnmarshalBase64(response.Ledgers[0].LedgerMetadataXdr, &meta))
+	require.Equal(t, uint32(13), meta.LedgerSequence())
+	require.Equal(t, expectedLatestLedgerProtocolVersion, response.Ledgers[0].Summary.ProtocolVersion)
+	require.Equal(t, "14", response.Cursor)
+
+	for _, cursor := range []string{"x", "4294967295"} {
+		_, err = handler.getLedgers(ctx, GetLedgersRequest{Pagination: &LedgersPaginationOptions{Cursor: cursor}})
+		require.EqualError(t, err, fmt.Sprintf("[-32602] invalid cursor %q", cursor))
+	}
+}
@@ -0,0 +1,108 @@
+package methods
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+	proto "github.com/stellar/go/protocols/stellarcore"
+	"github.com/stellar/go/support/log"
+)
+
+type GetSubmissionStatusRequest struct {
+	Hash string `json:"hash"`
+}
+
+// SubmissionStatusChange is a status of a submission, along with the time it was set
+type SubmissionStatusChange struct {
+	Status    string `json:"status"`
+	Timestamp int64  `json:"timestamp,string"`
+	// ErrorResultXDR is present only if Status is equal to proto.TXStatusError.
+	ErrorResultXDR string `json:"errorResultXdr,omitempty"`
+}
+
+// GetSubmissionStatusResponse is the response for the Soroban-RPC getSubmissionStatus() endpoint
+type GetSubmissionStatusResponse struct {
+	Hash string `json:"hash"`
+	// Status is the status returned by stellar-core for the latest attempt (proto.TXStatusPending,
+	// proto.TXStatusDuplicate, proto.TXStatusTryAgainLater or proto.TXStatusError), or the final
+	// status once the transaction is no longer tracked: TransactionStatusSuccess, TransactionStatusFailed
+	// or SubmissionStatusExpired.
+	Status string `json:"status"`
+	// Attempts is the number of times the transaction was submitted, including resubmissions by clients.
+	Attempts    uint32 `json:"attempts"`
+	SubmittedAt int64  `json:"submittedAt,string"`
+	UpdatedAt   int64  `json:"updatedAt,string"`
+	// NextAttemptAt is the time of the next resubmission, only present if Status is proto.TXStatusTryAgainLater.
+	NextAttemptAt int64 `json:"nextAttemptAt,string,omitempty"`
+	// ExpiresAt is the time after which the transaction stops being tracked.
+	ExpiresAt int64 `json:"expiresAt,string"`
+	// Ledger is the sequence of the ledger which included the transaction, if it was ingested.
+	Ledger uint32 `json:"ledger,omitempty"`
+	// History contains all the statuses of the submission, from oldest to newest.
+	History []SubmissionStatusChange `json:"history"`
+	// Transaction is the getTransaction() response for the ingested transaction, present as long as it
+	// is within the transaction retention window.
+	Transaction *GetTransactionResponse `json:"transaction,omitempty"`
+}
+
+// NewGetSubmissionStatusHandler returns a json rpc handler to get the status of the transactions
+// submitted through sendTransaction
+func NewGetSubmissionStatusHandler(logger *log.Entry, tracker *SubmissionTracker) jrpc2.Handler {
+	return handler.New(func(ctx context.Context, request GetSubmissionStatusRequest) (GetSubmissionStatusResponse, error) {
+		if tracker == nil {
+			return GetSubmissionStatusResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidRequest,
+				Message: "submission tracking is disabled",
+			}
+		}
+		txHash, err := parseTransactionHash(request.Hash)
+		if err != nil {
+			return GetSubmissionStatusResponse{}, err
+		}
+		hash := hex.EncodeToString(txHash[:])
+		submission, history, ok, err := tracker.store.GetSubmission(ctx, hash)
+		if err != nil {
+			logger.WithError(err).WithField("hash", hash).Info("could not get submission")
+			return GetSubmissionStatusResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "could not get submission",
+			}
+		}
+		if !ok {
+			return GetSubmissionStatusResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: "transaction submission not found",
+			}
+		}
+
+		response := GetSubmissionStatusResponse{
+			Hash:          submission.Hash,
+			Status:        submission.Status,
+			Attempts:      submission.Attempts,
+			SubmittedAt:   submission.SubmittedAt,
+			UpdatedAt:     submission.UpdatedAt,
+			NextAttemptAt: submission.NextAttemptAt,
+			ExpiresAt:     submission.ExpiresAt,
+			Ledger:        submission.Ledger,
+			History:       make([]SubmissionStatusChange, len(history)),
+		}
+		for i, change := range history {
+			response.History[i] = SubmissionStatusChange{
+				Status:    change.Status,
+				Timestamp: change.Timestamp,
+			}
+			if change.Status == proto.TXStatusError {
+				response.History[i].ErrorResultXDR = change.ErrorResultXDR
+			}
+		}
+		if submission.Status == TransactionStatusSuccess || submission.Status == TransactionStatusFailed {
+			if tx, found, storeRange := tracker.transactionStore.GetTransaction(txHash); found {
+				transaction := transactionResponse(tx, found, storeRange)
+				response.Transaction = &transaction
+			}
+		}
+		return response, nil
+	})
+}
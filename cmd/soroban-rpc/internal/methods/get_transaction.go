@@ -70,25 +70,35 @@ type transactionGetter interface {
 	GetTransaction(hash xdr.Hash) (transactions.Transaction, bool, transactions.StoreRange)
 }
 
-func GetTransaction(getter transactionGetter, request GetTransactionRequest) (GetTransactionResponse, error) {
-	// parse hash
-	if hex.DecodedLen(len(request.Hash)) != len(xdr.Hash{}) {
-		return GetTransactionResponse{}, &jrpc2.Error{
+func parseTransactionHash(hash string) (xdr.Hash, error) {
+	if hex.DecodedLen(len(hash)) != len(xdr.Hash{}) {
+		return xdr.Hash{}, &jrpc2.Error{
 			Code:    jrpc2.InvalidParams,
-			Message: fmt.Sprintf("unexpected hash length (%d)", len(request.Hash)),
+			Message: fmt.Sprintf("unexpected hash length (%d)", len(hash)),
 		}
 	}
 
 	var txHash xdr.Hash
-	_, err := hex.Decode(txHash[:], []byte(request.Hash))
+	_, err := hex.Decode(txHash[:], []byte(hash))
 	if err != nil {
-		return GetTransactionResponse{}, &jrpc2.Error{
+		return xdr.Hash{}, &jrpc2.Error{
 			Code:    jrpc2.InvalidParams,
 			Message: fmt.Sprintf("incorrect hash: %v", err),
 		}
 	}
+	return txHash, nil
+}
 
+func GetTransaction(getter transactionGetter, request GetTransactionRequest) (GetTransactionResponse, error) {
+	txHash, err := parseTransactionHash(request.Hash)
+	if err != nil {
+		return GetTransactionResponse{}, err
+	}
 	tx, found, storeRange := getter.GetTransaction(txHash)
+	return transactionResponse(tx, found, storeRange), nil
+}
+
+func transactionResponse(tx transactions.Transaction, found bool, storeRange transactions.StoreRange) GetTransactionResponse {
 	response := GetTransactionResponse{
 		LatestLedger:          storeRange.LastLedger.Sequence,
 		LatestLedgerCloseTime: storeRange.LastLedger.CloseTime,
@@ -97,7 +107,7 @@ func GetTransaction(getter transactionGetter, request GetTransactionRequest) (Ge
 	}
 	if !found {
 		response.Status = TransactionStatusNotFound
-		return response, nil
+		return response
 	}
 
 	response.ApplicationOrder = tx.ApplicationOrder
@@ -115,7 +125,7 @@ func GetTransaction(getter transactionGetter, request GetTransactionRequest) (Ge
 	} else {
 		response.Status = TransactionStatusFailed
 	}
-	return response, nil
+	return response
 }
 
 // NewGetTransactionHandler returns a get transaction json rpc handler
@@ -0,0 +1,187 @@
+package methods
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/toid"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/transactions"
+)
+
+// TransactionsPaginationOptions defines the available options for paginating through transactions.
+type TransactionsPaginationOptions struct {
+	// Cursor is the paging token of the last transaction returned by a previous request.
+	Cursor string `json:"cursor,omitempty"`
+	Limit  uint   `json:"limit,omitempty"`
+}
+
+type GetTransactionsRequest struct {
+	StartLedger uint32                         `json:"startLedger,omitempty"`
+	Pagination  *TransactionsPaginationOptions `json:"pagination,omitempty"`
+}
+
+func (r GetTransactionsRequest) valid(maxLimit uint) error {
+	if r.Pagination != nil && r.Pagination.Cursor != "" {
+		if r.StartLedger != 0 {
+			return errors.New("startLedger and cursor cannot both be set")
+		}
+	} else if r.StartLedger <= 0 {
+		return errors.New("startLedger must be positive")
+	}
+	if r.Pagination != nil && r.Pagination.Limit > maxLimit {
+		return fmt.Errorf("limit must not exceed %d", maxLimit)
+	}
+	return nil
+}
+
+// TransactionInfo is a transaction of the getTransactions() response
+type TransactionInfo struct {
+	// Status is one of: TransactionSuccess or TransactionFailed.
+	Status string `json:"status"`
+	// TransactionHash is the hash of the envelope, which is the outer hash for fee bump transactions.
+	TransactionHash string `json:"txHash"`
+	// ApplicationOrder is the index of the transaction among all the transactions
+	// for that ledger.
+	ApplicationOrder int32 `json:"applicationOrder"`
+	// FeeBump indicates whether the transaction is a feebump transaction
+	FeeBump bool `json:"feeBump"`
+	// EnvelopeXdr is the TransactionEnvelope XDR value.
+	EnvelopeXdr string `json:"envelopeXdr"`
+	// ResultXdr is the TransactionResult XDR value.
+	ResultXdr string `json:"resultXdr"`
+	// ResultMetaXdr is the TransactionMeta XDR value.
+	ResultMetaXdr string `json:"resultMetaXdr"`
+	// DiagnosticEventsXDR is a base64-encoded slice of xdr.DiagnosticEvent
+	DiagnosticEventsXDR []string `json:"diagnosticEventsXdr,omitempty"`
+	// Ledger is the sequence of the ledger which included the transaction.
+	Ledger uint32 `json:"ledger"`
+	// LedgerCloseTime is the unix timestamp of when the transaction was included in the ledger.
+	LedgerCloseTime int64 `json:"createdAt,string"`
+	// PagingToken can be used as a cursor to continue after this transaction.
+	PagingToken string `json:"pagingToken"`
+}
+
+// GetTransactionsResponse is the response for the Soroban-RPC getTransactions() endpoint
+type GetTransactionsResponse struct {
+	Transactions []TransactionInfo `json:"transactions"`
+	// LatestLedger is the latest ledger stored in Soroban-RPC.
+	LatestLedger uint32 `json:"latestLedger"`
+	// LatestLedgerCloseTime is the unix timestamp of when the latest ledger was closed.
+	LatestLedgerCloseTime int64 `json:"latestLedgerCloseTime,string"`
+	// OldestLedger is the oldest ledger stored in Soroban-RPC.
+	OldestLedger uint32 `json:"oldestLedger"`
+	// OldestLedgerCloseTime is the unix timestamp of when the oldest ledger was closed.
+	OldestLedgerCloseTime int64 `json:"oldestLedgerCloseTime,string"`
+	// Cursor is the paging token of the last returned transaction, to be used for the next request.
+	Cursor string `json:"cursor"`
+}
+
+type transactionScanner interface {
+	GetTransactions(startLedger uint32, startApplicationOrder int32, limit uint) ([]transactions.Transaction, transactions.StoreRange, error)
+}
+
+type transactionsRPCHandler struct {
+	scanner      transactionScanner
+	maxLimit     uint
+	defaultLimit uint
+}
+
+func transactionPagingToken(ledger uint32, applicationOrder int32) string {
+	return toid.New(int32(ledger), applicationOrder, 0).String()
+}
+
+func (h transactionsRPCHandler) getTransactions(request GetTransactionsRequest) (GetTransactionsResponse, error) {
+	if err := request.valid(h.maxLimit); err != nil {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+
+	startLedger := request.StartLedger
+	// application orders start at 1
+	startApplicationOrder := int32(1)
+	limit := h.defaultLimit
+	if request.Pagination != nil {
+		if request.Pagination.Cursor != "" {
+			id, err := strconv.ParseInt(request.Pagination.Cursor, 10, 64)
+			if err != nil {
+				return GetTransactionsResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InvalidParams,
+					Message: fmt.Sprintf("invalid cursor %q", request.Pagination.Cursor),
+				}
+			}
+			cursor := toid.Parse(id)
+			startLedger = uint32(cursor.LedgerSequence)
+			// when paginating, we start with the transaction right after the cursor
+			startApplicationOrder = cursor.TransactionOrder + 1
+		}
+		if request.Pagination.Limit > 0 {
+			limit = request.Pagination.Limit
+		}
+	}
+
+	txs, storeRange, err := h.scanner.GetTransactions(startLedger, startApplicationOrder, limit)
+	if err != nil {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	if request.StartLedger != 0 &&
+		(request.StartLedger < storeRange.FirstLedger.Sequence || request.StartLedger > storeRange.LastLedger.Sequence) {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code: jrpc2.InvalidParams,
+			Message: fmt.Sprintf("startLedger must be between the oldest ledger: %d and the latest ledger: %d",
+				storeRange.FirstLedger.Sequence, storeRange.LastLedger.Sequence),
+		}
+	}
+
+	response := GetTransactionsResponse{
+		Transactions:          make([]TransactionInfo, 0, len(txs)),
+		LatestLedger:          storeRange.LastLedger.Sequence,
+		LatestLedgerCloseTime: storeRange.LastLedger.CloseTime,
+		OldestLedger:          storeRange.FirstLedger.Sequence,
+		OldestLedgerCloseTime: storeRange.FirstLedger.CloseTime,
+		Cursor:                transactionPagingToken(startLedger, startApplicationOrder-1),
+	}
+	for _, tx := range txs {
+		info := TransactionInfo{
+			Status:              TransactionStatusFailed,
+			TransactionHash:     tx.Hash.HexString(),
+			ApplicationOrder:    tx.ApplicationOrder,
+			FeeBump:             tx.FeeBump,
+			EnvelopeXdr:         base64.StdEncoding.EncodeToString(tx.Envelope),
+			ResultXdr:           base64.StdEncoding.EncodeToString(tx.Result),
+			ResultMetaXdr:       base64.StdEncoding.EncodeToString(tx.Meta),
+			DiagnosticEventsXDR: base64EncodeSlice(tx.Events),
+			Ledger:              tx.Ledger.Sequence,
+			LedgerCloseTime:     tx.Ledger.CloseTime,
+			PagingToken:         transactionPagingToken(tx.Ledger.Sequence, tx.ApplicationOrder),
+		}
+		if tx.Successful {
+			info.Status = TransactionStatusSuccess
+		}
+		response.Transactions = append(response.Transactions, info)
+		response.Cursor = info.PagingToken
+	}
+	return response, nil
+}
+
+// NewGetTransactionsHandler returns a json rpc handler to fetch transactions in ledger order
+func NewGetTransactionsHandler(store *transactions.MemoryStore, maxLimit, defaultLimit uint) jrpc2.Handler {
+	transactionsHandler := transactionsRPCHandler{
+		scanner:      store,
+		maxLimit:     maxLimit,
+		defaultLimit: defaultLimit,
+	}
+	return handler.New(func(ctx context.Context, request GetTransactionsRequest) (GetTransactionsResponse, error) {
+		return transactionsHandler.getTransactions(request)
+	})
+}
@@ -0,0 +1,64 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/transactions"
+)
+
+func TestGetTransactions(t *testing.T) {
+	store := transactions.NewMemoryStore(interfaces.MakeNoOpDeamon(), "passphrase", 100)
+	for i := uint32(1); i <= 3; i++ {
+		require.NoError(t, store.IngestTransactions(txMeta(i, i != 2)))
+	}
+	handler := transactionsRPCHandler{
+		scanner:      store,
+		maxLimit:     100,
+		defaultLimit: 2,
+	}
+
+	_, err := handler.getTransactions(GetTransactionsRequest{})
+	require.EqualError(t, err, "[-32602] startLedger must be positive")
+	_, err = handler.getTransactions(GetTransactionsRequest{StartLedger: 1})
+	require.EqualError(t, err, "[-32602] startLedger must be between the oldest ledger: 101 and the latest ledger: 103")
+	_, err = handler.getTransactions(GetTransactionsRequest{
+		StartLedger: 101,
+		Pagination:  &TransactionsPaginationOptions{Limit: 101},
+	})
+	require.EqualError(t, err, "[-32602] limit must not exceed 100")
+
+	// the default limit applies
+	response, err := handler.getTransactions(GetTransactionsRequest{StartLedger: 101})
+	require.NoError(t, err)
+	require.Equal(t, uint32(101), response.OldestLedger)
+	require.Equal(t, uint32(103), response.LatestLedger)
+	require.Len(t, response.Transactions, 2)
+	require.Equal(t, txHash(1).HexString(), response.Transactions[0].TransactionHash)
+	require.Equal(t, TransactionStatusSuccess, response.Transactions[0].Status)
+	require.Equal(t, uint32(101), response.Transactions[0].Ledger)
+	require.Equal(t, int32(1), response.Transactions[0].ApplicationOrder)
+	require.Equal(t, txHash(2).HexString(), response.Transactions[1].TransactionHash)
+	require.Equal(t, TransactionStatusFailed, response.Transactions[1].Status)
+	require.Equal(t, response.Transactions[1].PagingToken, response.Cursor)
+
+	// continue after the cursor
+	response, err = handler.getTransactions(GetTransactionsRequest{
+		Pagination: &TransactionsPaginationOptions{Cursor: response.Cursor},
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Transactions, 1)
+	require.Equal(t, txHash(3).HexString(), response.Transactions[0].TransactionHash)
+	require.Equal(t, uint32(103), response.Transactions[0].Ledger)
+
+	// nothing left, the cursor stays put
+	cursor := response.Cursor
+	response, err = handler.getTransactions(GetTransactionsRequest{
+		Pagination: &TransactionsPaginationOptions{Cursor: cursor},
+	})
+	require.NoError(t, err)
+	require.Empty(t, response.Transactions)
+	require.Equal(t, cursor, response.Cursor)
+}
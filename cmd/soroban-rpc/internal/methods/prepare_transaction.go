@@ -0,0 +1,153 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
+)
+
+// maxResourceFeeMargin bounds the resource fee margin to ten times the minimum resource fee
+const maxResourceFeeMargin = 10
+
+type PrepareTransactionRequest struct {
+	Transaction    string                    `json:"transaction"`
+	ResourceConfig *preflight.ResourceConfig `json:"resourceConfig,omitempty"`
+	// ResourceFeeMargin is the fraction of the minimum resource fee added on top of it
+	// (e.g. 0.1 for 10%), to account for the ledger state changing before submission
+	ResourceFeeMargin float64 `json:"resourceFeeMargin,omitempty"`
+	// BypassCache forces a new simulation even if the same one is cached
+	BypassCache bool `json:"bypassCache,omitempty"`
+}
+
+// PrepareTransactionResponse is the response for the Soroban-RPC prepareTransaction() endpoint
+type PrepareTransactionResponse struct {
+	Error string `json:"error,omitempty"`
+	// Transaction is the TransactionEnvelope XDR in base64 with the simulated SorobanTransactionData,
+	// fee and auth entries. It isn't signed, so previous signatures are invalidated.
+	Transaction    string `json:"transaction,omitempty"`
+	Fee            int64  `json:"fee,string,omitempty"`
+	MinResourceFee int64  `json:"minResourceFee,string,omitempty"`
+	ResourceFee    int64  `json:"resourceFee,string,omitempty"` // the minimum resource fee plus the margin
+	// RestoreRequired indicates that some archived ledger entries need to be restored (using the
+	// RestorePreamble) before submitting the transaction.
+	RestoreRequired bool             `json:"restoreRequired"`
+	RestorePreamble *RestorePreamble `json:"restorePreamble,omitempty"`
+	LatestLedger    uint32           `json:"latestLedger"`
+}
+
+// NewPrepareTransactionHandler returns a json rpc handler which simulates a transaction and assembles
+// it with the simulation results, ready to be signed
+func NewPrepareTransactionHandler(logger *log.Entry, ledgerEntryReader db.LedgerEntryReader, ledgerReader db.LedgerReader, getter PreflightGetter, cache *SimulationCache) jrpc2.Handler {
+	return handler.New(func(ctx context.Context, request PrepareTransactionRequest) (PrepareTransactionResponse, error) {
+		var txEnvelope xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(request.Transaction, &txEnvelope); err != nil {
+			logger.WithError(err).WithField("request", request).
+				Info("could not unmarshal prepare transaction envelope")
+			return PrepareTransactionResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: "could not unmarshal transaction",
+			}
+		}
+		if txEnvelope.Type != xdr.EnvelopeTypeEnvelopeTypeTx {
+			return PrepareTransactionResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: "only v1 transaction envelopes can be prepared",
+			}
+		}
+		if request.ResourceFeeMargin < 0 || request.ResourceFeeMargin > maxResourceFeeMargin || math.IsNaN(request.ResourceFeeMargin) {
+			return PrepareTransactionResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: fmt.Sprintf("resourceFeeMargin must be a number between 0 and %d", maxResourceFeeMargin),
+			}
+		}
+
+		// transactions are always prepared against the latest ledger, so there is no historical depth to allow
+		simulation := simulateTransaction(ctx, logger, ledgerEntryReader, ledgerReader, getter, cache, 0, SimulateTransactionRequest{
+			Transaction:    request.Transaction,
+			ResourceConfig: request.ResourceConfig,
+			BypassCache:    request.BypassCache,
+		})
+		response := PrepareTransactionResponse{
+			Error:           simulation.Error,
+			MinResourceFee:  simulation.MinResourceFee,
+			RestoreRequired: simulation.RestorePreamble != nil,
+			RestorePreamble: simulation.RestorePreamble,
+			LatestLedger:    simulation.LatestLedger,
+		}
+		if simulation.Error != "" {
+			return response, nil
+		}
+		margin := math.Ceil(float64(simulation.MinResourceFee) * request.ResourceFeeMargin)
+		if margin > math.MaxUint32 {
+			return PrepareTransactionResponse{
+				Error:        fmt.Sprintf("cannot assemble transaction: resource fee margin (%.0f) out of range", margin),
+				LatestLedger: simulation.LatestLedger,
+			}, nil
+		}
+		resourceFee := simulation.MinResourceFee + int64(margin)
+		if err := assembleTransaction(&txEnvelope, simulation, resourceFee); err != nil {
+			return PrepareTransactionResponse{
+				Error:        "cannot assemble transaction: " + err.Error(),
+				LatestLedger: simulation.LatestLedger,
+			}, nil
+		}
+		assembled, err := xdr.MarshalBase64(txEnvelope)
+		if err != nil {
+			return PrepareTransactionResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		response.Transaction = assembled
+		response.Fee = int64(txEnvelope.V1.Tx.Fee)
+		response.ResourceFee = resourceFee
+		return response, nil
+	})
+}
+
+// assembleTransaction sets the simulated SorobanTransactionData (with the given resource fee) and
+// auth entries in the transaction, adding the resource fee to its inclusion fee. Auth entries are
+// only set if the operation doesn't have any. Signatures are removed, since they no longer match.
+func assembleTransaction(txEnvelope *xdr.TransactionEnvelope, simulation SimulateTransactionResponse, resourceFee int64) error {
+	tx := &txEnvelope.V1.Tx
+	var transactionData xdr.SorobanTransactionData
+	if err := xdr.SafeUnmarshalBase64(simulation.TransactionData, &transactionData); err != nil {
+		return err
+	}
+	transactionData.ResourceFee = xdr.Int64(resourceFee)
+
+	// the fee of an already prepared transaction includes its previous resource fee
+	inclusionFee := int64(tx.Fee)
+	if sorobanData, ok := tx.Ext.GetSorobanData(); ok {
+		inclusionFee -= int64(sorobanData.ResourceFee)
+	}
+	fee := inclusionFee + resourceFee
+	if resourceFee < 0 || inclusionFee < 0 || fee < 0 || fee > math.MaxUint32 {
+		return fmt.Errorf("fee (%d) out of range", fee)
+	}
+	tx.Fee = xdr.Uint32(fee)
+	tx.Ext = xdr.TransactionExt{V: 1, SorobanData: &transactionData}
+	txEnvelope.V1.Signatures = nil
+
+	invokeHostFunction, ok := tx.Operations[0].Body.GetInvokeHostFunctionOp()
+	if !ok || len(invokeHostFunction.Auth) > 0 || len(simulation.Results) == 0 {
+		return nil
+	}
+	auth := make([]xdr.SorobanAuthorizationEntry, len(simulation.Results[0].Auth))
+	for i, entry := range simulation.Results[0].Auth {
+		if err := xdr.SafeUnmarshalBase64(entry, &auth[i]); err != nil {
+			return err
+		}
+	}
+	invokeHostFunction.Auth = auth
+	tx.Operations[0].Body.InvokeHostFunctionOp = &invokeHostFunction
+	return nil
+}
@@ -0,0 +1,144 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
+)
+
+// fixedPreflightGetter returns a successful simulation, which requires a restore if restore is set
+// and has a minimum resource fee of minFee if set
+type fixedPreflightGetter struct {
+	restore bool
+	minFee  int64
+}
+
+func (g fixedPreflightGetter) GetPreflight(ctx context.Context, params preflight.PreflightGetterParameters) (preflight.Preflight, error) {
+	transactionData, err := xdr.SorobanTransactionData{
+		Resources: xdr.SorobanResources{Instructions: 100},
+		// the preflight resource fee doesn't include the margin
+		ResourceFee: 1000,
+	}.MarshalBinary()
+	if err != nil {
+		return preflight.Preflight{}, err
+	}
+	auth, err := xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{Type: xdr.SorobanCredentialsTypeSorobanCredentialsSourceAccount},
+		RootInvocation: xdr.SorobanAuthorizedInvocation{
+			Function: xdr.SorobanAuthorizedFunction{
+				Type:       xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+				ContractFn: params.OperationBody.InvokeHostFunctionOp.HostFunction.InvokeContract,
+			},
+		},
+	}.MarshalBinary()
+	if err != nil {
+		return preflight.Preflight{}, err
+	}
+	result := preflight.Preflight{
+		Result:          []byte{0, 0, 0, 1}, // void
+		Auth:            [][]byte{auth},
+		TransactionData: transactionData,
+		MinFee:          1000,
+	}
+	if g.minFee != 0 {
+		result.MinFee = g.minFee
+	}
+	if g.restore {
+		result.PreRestoreTransactionData = transactionData
+		result.PreRestoreMinFee = 500
+	}
+	return result, nil
+}
+
+func TestPrepareTransaction(t *testing.T) {
+	prepare := func(getter PreflightGetter, request string) (PrepareTransactionResponse, error) {
+		handler := NewPrepareTransactionHandler(log.DefaultLogger, &ConstantLedgerEntryReader{}, &ConstantLedgerReader{}, getter, nil)
+		requests, err := jrpc2.ParseRequests([]byte(`{"jsonrpc":"2.0","id":1,"method":"prepareTransaction","params":` + request + `}`))
+		require.NoError(t, err)
+		result, err := handler(context.Background(), requests[0].ToRequest())
+		if err != nil {
+			return PrepareTransactionResponse{}, err
+		}
+		return result.(PrepareTransactionResponse), nil
+	}
+	envelope := invokeContractEnvelope(t)
+
+	response, err := prepare(fixedPreflightGetter{}, `{"transaction":"`+envelope+`","resourceFeeMargin":0.15}`)
+	require.NoError(t, err)
+	assert.Empty(t, response.Error)
+	assert.False(t, response.RestoreRequired)
+	assert.Equal(t, int64(1000), response.MinResourceFee)
+	assert.Equal(t, int64(1150), response.ResourceFee)
+	assert.Equal(t, int64(1150), response.Fee)
+	assert.Equal(t, expectedLatestLedgerSequence, response.LatestLedger)
+
+	var assembled xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(response.Transaction, &assembled))
+	assert.Equal(t, xdr.Uint32(1150), assembled.V1.Tx.Fee)
+	sorobanData, ok := assembled.V1.Tx.Ext.GetSorobanData()
+	require.True(t, ok)
+	assert.Equal(t, xdr.Int64(1150), sorobanData.ResourceFee)
+	assert.Equal(t, xdr.Uint32(100), sorobanData.Resources.Instructions)
+	assert.Len(t, assembled.V1.Tx.Operations[0].Body.InvokeHostFunctionOp.Auth, 1)
+
+	// preparing an already prepared (and signed) transaction replaces its resource fee
+	// and removes the signatures
+	assembled.V1.Signatures = []xdr.DecoratedSignature{{Hint: xdr.SignatureHint{1, 2, 3, 4}, Signature: xdr.Signature{5, 6}}}
+	signed, err := xdr.MarshalBase64(assembled)
+	require.NoError(t, err)
+	response, err = prepare(fixedPreflightGetter{restore: true}, `{"transaction":"`+signed+`"}`)
+	require.NoError(t, err)
+	require.NoError(t, xdr.SafeUnmarshalBase64(response.Transaction, &assembled))
+	assert.Empty(t, assembled.V1.Signatures)
+	assert.Equal(t, int64(1000), response.Fee)
+	assert.True(t, response.RestoreRequired)
+	require.NotNil(t, response.RestorePreamble)
+	assert.Equal(t, int64(500), response.RestorePreamble.MinResourceFee)
+
+	for _, margin := range []string{"-1", "10.5", "1e300"} {
+		_, err = prepare(fixedPreflightGetter{}, `{"transaction":"`+envelope+`","resourceFeeMargin":`+margin+`}`)
+		require.Error(t, err)
+		assert.Equal(t, jrpc2.InvalidParams, err.(*jrpc2.Error).Code)
+	}
+
+	// the largest margin is accepted
+	response, err = prepare(fixedPreflightGetter{}, `{"transaction":"`+envelope+`","resourceFeeMargin":10}`)
+	require.NoError(t, err)
+	assert.Empty(t, response.Error)
+	assert.Equal(t, int64(11000), response.ResourceFee)
+
+	// out of range resource fees are reported instead of wrapping around
+	response, err = prepare(fixedPreflightGetter{minFee: -1}, `{"transaction":"`+envelope+`"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "cannot assemble transaction: fee (-1) out of range", response.Error)
+	assert.Empty(t, response.Transaction)
+	response, err = prepare(fixedPreflightGetter{minFee: 1 << 40}, `{"transaction":"`+envelope+`","resourceFeeMargin":1}`)
+	require.NoError(t, err)
+	assert.Contains(t, response.Error, "out of range")
+
+	var inner xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(envelope, &inner))
+	feeBump, err := xdr.MarshalBase64(xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
+		FeeBump: &xdr.FeeBumpTransactionEnvelope{
+			Tx: xdr.FeeBumpTransaction{
+				FeeSource: inner.V1.Tx.SourceAccount,
+				InnerTx: xdr.FeeBumpTransactionInnerTx{
+					Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+					V1:   inner.V1,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = prepare(fixedPreflightGetter{}, `{"transaction":"`+feeBump+`"}`)
+	require.Error(t, err)
+	assert.Equal(t, "only v1 transaction envelopes can be prepared", err.(*jrpc2.Error).Message)
+}
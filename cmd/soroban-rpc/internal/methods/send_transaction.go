@@ -51,8 +51,9 @@ type LatestLedgerStore interface {
 	GetLatestLedger() transactions.LedgerInfo
 }
 
-// NewSendTransactionHandler returns a submit transaction json rpc handler
-func NewSendTransactionHandler(daemon interfaces.Daemon, logger *log.Entry, store LatestLedgerStore, passphrase string) jrpc2.Handler {
+// NewSendTransactionHandler returns a submit transaction json rpc handler. If tracker isn't nil,
+// the submitted transactions are tracked by it.
+func NewSendTransactionHandler(daemon interfaces.Daemon, logger *log.Entry, store LatestLedgerStore, passphrase string, tracker *SubmissionTracker) jrpc2.Handler {
 	submitter := daemon.CoreClient()
 	return handler.New(func(ctx context.Context, request SendTransactionRequest) (SendTransactionResponse, error) {
 		var envelope xdr.TransactionEnvelope
@@ -95,6 +96,13 @@ func NewSendTransactionHandler(daemon interfaces.Daemon, logger *log.Entry, stor
 			}
 		}
 
+		trackSubmission := func() {
+			err := tracker.track(ctx, txHash, envelope, request.Transaction, resp.Status, resp.Error)
+			if err != nil {
+				logger.WithError(err).WithField("hash", txHash).Error("could not track transaction submission")
+			}
+		}
+
 		switch resp.Status {
 		case proto.TXStatusError:
 			events, err := proto.DiagnosticEventsToSlice(resp.DiagnosticEvents)
@@ -105,6 +113,7 @@ func NewSendTransactionHandler(daemon interfaces.Daemon, logger *log.Entry, stor
 					Message: "could not decode diagnostic events",
 				}
 			}
+			trackSubmission()
 			return SendTransactionResponse{
 				ErrorResultXDR:        resp.Error,
 				DiagnosticEventsXDR:   events,
@@ -114,6 +123,7 @@ func NewSendTransactionHandler(daemon interfaces.Daemon, logger *log.Entry, stor
 				LatestLedgerCloseTime: ledgerInfo.CloseTime,
 			}, nil
 		case proto.TXStatusPending, proto.TXStatusDuplicate, proto.TXStatusTryAgainLater:
+			trackSubmission()
 			return SendTransactionResponse{
 				Status:                resp.Status,
 				Hash:                  txHash,
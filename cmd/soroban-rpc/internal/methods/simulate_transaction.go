@@ -12,11 +12,76 @@ import (
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrjson"
 )
 
 type SimulateTransactionRequest struct {
 	Transaction    string                    `json:"transaction"`
 	ResourceConfig *preflight.ResourceConfig `json:"resourceConfig,omitempty"`
+	// LedgerEntryOverrides are layered over the ledger state for this simulation only
+	LedgerEntryOverrides *LedgerEntryOverrides `json:"ledgerEntryOverrides,omitempty"`
+	// Format "json" additionally returns the decoded return values, auth entries and events
+	Format string `json:"format,omitempty"`
+	// BypassCache forces a new simulation even if the same one is cached
+	BypassCache bool `json:"bypassCache,omitempty"`
+	// Ledger is an optional past ledger (within the ledger retention window). If set, the transaction
+	// is simulated against the ledger state right after that ledger closed.
+	Ledger uint32 `json:"ledger,omitempty"`
+}
+
+// LedgerEntryOverrides describes a hypothetical ledger state to simulate against.
+// New contract data and code entries must be accompanied by their TTL entries, which is checked
+// when applying the overrides.
+type LedgerEntryOverrides struct {
+	Upserts   []string `json:"upserts,omitempty"`   // LedgerEntry XDR in base64
+	Deletions []string `json:"deletions,omitempty"` // LedgerKey XDR in base64
+}
+
+func (o LedgerEntryOverrides) apply(overlay *db.LedgerEntryOverlayTx) error {
+	// keys of the upserted entries with TTLs, by upsert index
+	keysWithTTL := map[int]xdr.LedgerKey{}
+	for i, upsert := range o.Upserts {
+		var entry xdr.LedgerEntry
+		if err := xdr.SafeUnmarshalBase64(upsert, &entry); err != nil {
+			return fmt.Errorf("cannot unmarshal ledger entry override upsert %d", i+1)
+		}
+		if err := overlay.UpsertLedgerEntry(entry); err != nil {
+			return fmt.Errorf("invalid ledger entry override upsert %d: %v", i+1, err)
+		}
+		switch entry.Data.Type {
+		case xdr.LedgerEntryTypeContractData, xdr.LedgerEntryTypeContractCode:
+			key, err := entry.LedgerKey()
+			if err != nil {
+				return fmt.Errorf("invalid ledger entry override upsert %d: %v", i+1, err)
+			}
+			keysWithTTL[i] = key
+		}
+	}
+	for i, deletion := range o.Deletions {
+		var key xdr.LedgerKey
+		if err := xdr.SafeUnmarshalBase64(deletion, &key); err != nil {
+			return fmt.Errorf("cannot unmarshal ledger entry override deletion %d", i+1)
+		}
+		if err := overlay.DeleteLedgerEntry(key); err != nil {
+			return fmt.Errorf("invalid ledger entry override deletion %d: %v", i+1, err)
+		}
+	}
+	// the simulation requires the TTL of contract data and code entries, which must be
+	// upserted along with them unless they are already in the ledger state
+	for i := range o.Upserts {
+		key, ok := keysWithTTL[i]
+		if !ok {
+			continue
+		}
+		present, _, liveUntilLedgerSeq, err := db.GetLedgerEntry(overlay, key)
+		if err != nil {
+			return err
+		}
+		if present && liveUntilLedgerSeq == nil {
+			return fmt.Errorf("ledger entry override upsert %d has no TTL entry in the overrides or the ledger state", i+1)
+		}
+	}
+	return nil
 }
 
 type SimulateTransactionCost struct {
@@ -28,6 +93,9 @@ type SimulateTransactionCost struct {
 type SimulateHostFunctionResult struct {
 	Auth []string `json:"auth"`
 	XDR  string   `json:"xdr"`
+	// only present when the json format is requested
+	AuthJSON        []AuthorizationEntryJSON `json:"authJson,omitempty"`
+	ReturnValueJSON *xdrjson.ScValJSON       `json:"returnValueJson,omitempty"`
 }
 
 type RestorePreamble struct {
@@ -44,6 +112,8 @@ type SimulateTransactionResponse struct {
 	Cost            SimulateTransactionCost      `json:"cost,omitempty"`            // the effective cpu and memory cost of the invoked transaction execution.
 	RestorePreamble *RestorePreamble             `json:"restorePreamble,omitempty"` // If present, it indicates that a prior RestoreFootprint is required
 	LatestLedger    uint32                       `json:"latestLedger"`
+	EventsJSON      []DiagnosticEventJSON        `json:"eventsJson,omitempty"` // only present when the json format is requested
+	Trace           []*CallFrame                 `json:"trace,omitempty"`      // the call tree, only present if preflight debugging is enabled
 }
 
 type PreflightGetter interface {
@@ -51,118 +121,250 @@ type PreflightGetter interface {
 }
 
 // NewSimulateTransactionHandler returns a json rpc handler to run preflight simulations
-func NewSimulateTransactionHandler(logger *log.Entry, ledgerEntryReader db.LedgerEntryReader, ledgerReader db.LedgerReader, getter PreflightGetter) jrpc2.Handler {
-
+func NewSimulateTransactionHandler(logger *log.Entry, ledgerEntryReader db.LedgerEntryReader, ledgerReader db.LedgerReader, getter PreflightGetter, cache *SimulationCache, maxHistoricalLedgerDepth uint32) jrpc2.Handler {
 	return handler.New(func(ctx context.Context, request SimulateTransactionRequest) SimulateTransactionResponse {
-		var txEnvelope xdr.TransactionEnvelope
-		if err := xdr.SafeUnmarshalBase64(request.Transaction, &txEnvelope); err != nil {
-			logger.WithError(err).WithField("request", request).
-				Info("could not unmarshal simulate transaction envelope")
-			return SimulateTransactionResponse{
-				Error: "Could not unmarshal transaction",
-			}
+		return simulateTransaction(ctx, logger, ledgerEntryReader, ledgerReader, getter, cache, maxHistoricalLedgerDepth, request)
+	})
+}
+
+func simulateTransaction(
+	ctx context.Context,
+	logger *log.Entry,
+	ledgerEntryReader db.LedgerEntryReader,
+	ledgerReader db.LedgerReader,
+	getter PreflightGetter,
+	cache *SimulationCache,
+	maxHistoricalLedgerDepth uint32,
+	request SimulateTransactionRequest,
+) SimulateTransactionResponse {
+	var txEnvelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(request.Transaction, &txEnvelope); err != nil {
+		logger.WithError(err).WithField("request", request).
+			Info("could not unmarshal simulate transaction envelope")
+		return SimulateTransactionResponse{
+			Error: "Could not unmarshal transaction",
 		}
-		if len(txEnvelope.Operations()) != 1 {
-			return SimulateTransactionResponse{
-				Error: "Transaction contains more than one operation",
-			}
+	}
+	if err := validateFormat(request.Format); err != nil {
+		return SimulateTransactionResponse{
+			Error: err.Error(),
 		}
-		op := txEnvelope.Operations()[0]
-
-		var sourceAccount xdr.AccountId
-		if opSourceAccount := op.SourceAccount; opSourceAccount != nil {
-			sourceAccount = opSourceAccount.ToAccountId()
-		} else {
-			sourceAccount = txEnvelope.SourceAccount().ToAccountId()
+	}
+	op, footprint, errMessage := simulationOperation(txEnvelope)
+	if errMessage != "" {
+		return SimulateTransactionResponse{
+			Error: errMessage,
 		}
+	}
 
-		footprint := xdr.LedgerFootprint{}
-		switch op.Body.Type {
-		case xdr.OperationTypeInvokeHostFunction:
-		case xdr.OperationTypeExtendFootprintTtl, xdr.OperationTypeRestoreFootprint:
-			if txEnvelope.Type != xdr.EnvelopeTypeEnvelopeTypeTx && txEnvelope.V1.Tx.Ext.V != 1 {
-				return SimulateTransactionResponse{
-					Error: "To perform a SimulateTransaction for ExtendFootprintTtl or RestoreFootprint operations, SorobanTransactionData must be provided",
-				}
-			}
-			footprint = txEnvelope.V1.Tx.Ext.SorobanData.Resources.Footprint
-		default:
-			return SimulateTransactionResponse{
-				Error: "Transaction contains unsupported operation type: " + op.Body.Type.String(),
-			}
+	readTx, err := ledgerEntryReader.NewCachedTx(ctx)
+	if err != nil {
+		return SimulateTransactionResponse{
+			Error: "Cannot create read transaction",
 		}
-
-		readTx, err := ledgerEntryReader.NewCachedTx(ctx)
+	}
+	defer func() {
+		_ = readTx.Done()
+	}()
+	latestLedger, err := readTx.GetLatestLedgerSequence()
+	if err != nil {
+		return SimulateTransactionResponse{
+			Error: err.Error(),
+		}
+	}
+	// the ledger whose state is simulated, and the time at which its next ledger closed (now if unknown)
+	simulationLedger := latestLedger
+	var simulationTimestamp uint64
+	var simulationTx db.LedgerEntryReadTx = readTx
+	if request.Ledger != 0 && request.Ledger != latestLedger {
+		historicalTx, err := db.NewHistoricalLedgerEntryTx(ctx, readTx, ledgerReader, request.Ledger, maxHistoricalLedgerDepth)
 		if err != nil {
 			return SimulateTransactionResponse{
-				Error: "Cannot create read transaction",
+				Error:        err.Error(),
+				LatestLedger: latestLedger,
 			}
 		}
-		defer func() {
-			_ = readTx.Done()
-		}()
-		latestLedger, err := readTx.GetLatestLedgerSequence()
+		nextLedger, ok, err := ledgerReader.GetLedger(ctx, request.Ledger+1)
 		if err != nil {
 			return SimulateTransactionResponse{
-				Error: err.Error(),
+				Error:        err.Error(),
+				LatestLedger: latestLedger,
 			}
 		}
-		bucketListSize, err := getBucketListSize(ctx, ledgerReader, latestLedger)
-		if err != nil {
+		if ok {
+			simulationTimestamp = uint64(nextLedger.LedgerHeaderHistoryEntry().Header.ScpValue.CloseTime)
+		}
+		simulationLedger = request.Ledger
+		simulationTx = historicalTx
+	}
+	resource_config := preflight.DefaultResourceConfig()
+	if request.ResourceConfig != nil {
+		resource_config = *request.ResourceConfig
+	}
+	// simulations against overridden ledger states aren't cached
+	useCache := cache != nil && !request.BypassCache && request.LedgerEntryOverrides == nil
+	var cacheKey simulationCacheKey
+	if useCache {
+		if cacheKey, err = newSimulationCacheKey(txEnvelope, resource_config, simulationLedger); err != nil {
 			return SimulateTransactionResponse{
 				Error: err.Error(),
 			}
 		}
-
-		resource_config := preflight.DefaultResourceConfig()
-		if request.ResourceConfig != nil {
-			resource_config = *request.ResourceConfig
+		if response, ok := cache.get(cacheKey); ok {
+			return formatSimulationResponse(response, request.Format)
 		}
-		params := preflight.PreflightGetterParameters{
-			LedgerEntryReadTx: readTx,
-			BucketListSize:    bucketListSize,
-			SourceAccount:     sourceAccount,
-			OperationBody:     op.Body,
-			Footprint:         footprint,
-			ResourceConfig:    resource_config,
+	}
+	bucketListSize, err := getBucketListSize(ctx, ledgerReader, simulationLedger)
+	if err != nil {
+		return SimulateTransactionResponse{
+			Error: err.Error(),
 		}
-		result, err := getter.GetPreflight(ctx, params)
-		if err != nil {
+	}
+
+	if request.LedgerEntryOverrides != nil {
+		overlay := db.NewLedgerEntryOverlayTx(simulationTx)
+		if err := request.LedgerEntryOverrides.apply(overlay); err != nil {
 			return SimulateTransactionResponse{
 				Error:        err.Error(),
 				LatestLedger: latestLedger,
 			}
 		}
+		simulationTx = overlay
+	}
 
-		var results []SimulateHostFunctionResult
-		if len(result.Result) != 0 {
-			results = append(results, SimulateHostFunctionResult{
-				XDR:  base64.StdEncoding.EncodeToString(result.Result),
-				Auth: base64EncodeSlice(result.Auth),
-			})
+	params := preflight.PreflightGetterParameters{
+		LedgerEntryReadTx: simulationTx,
+		BucketListSize:    bucketListSize,
+		SourceAccount:     simulationSourceAccount(txEnvelope, op),
+		OperationBody:     op.Body,
+		Footprint:         footprint,
+		ResourceConfig:    resource_config,
+		Timestamp:         simulationTimestamp,
+	}
+	result, err := getter.GetPreflight(ctx, params)
+	if err != nil {
+		return SimulateTransactionResponse{
+			Error:        err.Error(),
+			LatestLedger: latestLedger,
 		}
-		var restorePreamble *RestorePreamble = nil
-		if len(result.PreRestoreTransactionData) != 0 {
-			restorePreamble = &RestorePreamble{
-				TransactionData: base64.StdEncoding.EncodeToString(result.PreRestoreTransactionData),
-				MinResourceFee:  result.PreRestoreMinFee,
-			}
+	}
+	response := simulateTransactionResponse(result, latestLedger)
+	if useCache {
+		cache.add(cacheKey, response)
+	}
+	return formatSimulationResponse(response, request.Format)
+}
+
+// formatSimulationResponse returns the response in the requested format, without modifying it
+func formatSimulationResponse(response SimulateTransactionResponse, format string) SimulateTransactionResponse {
+	if format == FormatJSON {
+		response.Results = append([]SimulateHostFunctionResult(nil), response.Results...)
+		addSimulationJSON(&response)
+	}
+	return response
+}
+
+// simulationOperation returns the operation to simulate and its footprint,
+// or an error message if the transaction cannot be simulated
+func simulationOperation(txEnvelope xdr.TransactionEnvelope) (xdr.Operation, xdr.LedgerFootprint, string) {
+	if len(txEnvelope.Operations()) != 1 {
+		return xdr.Operation{}, xdr.LedgerFootprint{}, "Transaction contains more than one operation"
+	}
+	op := txEnvelope.Operations()[0]
+
+	footprint := xdr.LedgerFootprint{}
+	switch op.Body.Type {
+	case xdr.OperationTypeInvokeHostFunction:
+	case xdr.OperationTypeExtendFootprintTtl, xdr.OperationTypeRestoreFootprint:
+		if txEnvelope.Type != xdr.EnvelopeTypeEnvelopeTypeTx && txEnvelope.V1.Tx.Ext.V != 1 {
+			return xdr.Operation{}, xdr.LedgerFootprint{}, "To perform a SimulateTransaction for ExtendFootprintTtl or RestoreFootprint operations, SorobanTransactionData must be provided"
 		}
+		footprint = txEnvelope.V1.Tx.Ext.SorobanData.Resources.Footprint
+	default:
+		return xdr.Operation{}, xdr.LedgerFootprint{}, "Transaction contains unsupported operation type: " + op.Body.Type.String()
+	}
+	return op, footprint, ""
+}
 
-		return SimulateTransactionResponse{
-			Error:           result.Error,
-			Results:         results,
-			Events:          base64EncodeSlice(result.Events),
-			TransactionData: base64.StdEncoding.EncodeToString(result.TransactionData),
-			MinResourceFee:  result.MinFee,
-			Cost: SimulateTransactionCost{
-				CPUInstructions: result.CPUInstructions,
-				MemoryBytes:     result.MemoryBytes,
-			},
-			LatestLedger:    latestLedger,
-			RestorePreamble: restorePreamble,
+func simulationSourceAccount(txEnvelope xdr.TransactionEnvelope, op xdr.Operation) xdr.AccountId {
+	if opSourceAccount := op.SourceAccount; opSourceAccount != nil {
+		return opSourceAccount.ToAccountId()
+	}
+	return txEnvelope.SourceAccount().ToAccountId()
+}
+
+func simulateTransactionResponse(result preflight.Preflight, latestLedger uint32) SimulateTransactionResponse {
+	var results []SimulateHostFunctionResult
+	if len(result.Result) != 0 {
+		results = append(results, SimulateHostFunctionResult{
+			XDR:  base64.StdEncoding.EncodeToString(result.Result),
+			Auth: base64EncodeSlice(result.Auth),
+		})
+	}
+	var restorePreamble *RestorePreamble = nil
+	if len(result.PreRestoreTransactionData) != 0 {
+		restorePreamble = &RestorePreamble{
+			TransactionData: base64.StdEncoding.EncodeToString(result.PreRestoreTransactionData),
+			MinResourceFee:  result.PreRestoreMinFee,
 		}
-	})
+	}
+
+	return SimulateTransactionResponse{
+		Error:           result.Error,
+		Results:         results,
+		Events:          base64EncodeSlice(result.Events),
+		TransactionData: base64.StdEncoding.EncodeToString(result.TransactionData),
+		MinResourceFee:  result.MinFee,
+		Cost: SimulateTransactionCost{
+			CPUInstructions: result.CPUInstructions,
+			MemoryBytes:     result.MemoryBytes,
+		},
+		LatestLedger:    latestLedger,
+		RestorePreamble: restorePreamble,
+		Trace:           simulationTrace(result.Events),
+	}
+}
+
+// addSimulationJSON decodes the XDR results, auth entries and events of a simulation
+func addSimulationJSON(response *SimulateTransactionResponse) {
+	if err := simulationJSON(response); err != nil {
+		*response = SimulateTransactionResponse{
+			Error:        "cannot decode simulation results: " + err.Error(),
+			LatestLedger: response.LatestLedger,
+		}
+	}
+}
+
+func simulationJSON(response *SimulateTransactionResponse) error {
+	for i := range response.Results {
+		result := &response.Results[i]
+		var returnValue xdr.ScVal
+		if err := xdr.SafeUnmarshalBase64(result.XDR, &returnValue); err != nil {
+			return err
+		}
+		returnValueJSON := xdrjson.ScValToJSON(returnValue)
+		result.ReturnValueJSON = &returnValueJSON
+		result.AuthJSON = make([]AuthorizationEntryJSON, 0, len(result.Auth))
+		for _, auth := range result.Auth {
+			var entry xdr.SorobanAuthorizationEntry
+			if err := xdr.SafeUnmarshalBase64(auth, &entry); err != nil {
+				return err
+			}
+			result.AuthJSON = append(result.AuthJSON, authorizationEntryToJSON(entry))
+		}
+	}
+	for _, eventXDR := range response.Events {
+		var event xdr.DiagnosticEvent
+		if err := xdr.SafeUnmarshalBase64(eventXDR, &event); err != nil {
+			return err
+		}
+		eventJSON, err := diagnosticEventToJSON(event)
+		if err != nil {
+			return err
+		}
+		response.EventsJSON = append(response.EventsJSON, eventJSON)
+	}
+	return nil
 }
 
 func base64EncodeSlice(in [][]byte) []string {
@@ -0,0 +1,94 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
+)
+
+// retainedLedgerReader stores the ledgers from firstLedger to expectedLatestLedgerSequence, without changes
+type retainedLedgerReader struct {
+	ConstantLedgerReader
+	firstLedger uint32
+}
+
+func (r *retainedLedgerReader) ledger(sequence uint32) xdr.LedgerCloseMeta {
+	ledger := createLedger(sequence, expectedLatestLedgerProtocolVersion, expectedLatestLedgerHashBytes)
+	ledger.V1.LedgerHeader.Header.ScpValue.CloseTime = xdr.TimePoint(5 * sequence)
+	return ledger
+}
+
+func (r *retainedLedgerReader) GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, bool, error) {
+	if sequence < r.firstLedger || sequence > expectedLatestLedgerSequence {
+		return xdr.LedgerCloseMeta{}, false, nil
+	}
+	return r.ledger(sequence), true, nil
+}
+
+func (r *retainedLedgerReader) BatchGetLedgers(ctx context.Context, startSequence uint32, limit uint) ([]xdr.LedgerCloseMeta, error) {
+	var ledgers []xdr.LedgerCloseMeta
+	for sequence := startSequence; sequence <= expectedLatestLedgerSequence && uint(len(ledgers)) < limit; sequence++ {
+		ledgers = append(ledgers, r.ledger(sequence))
+	}
+	return ledgers, nil
+}
+
+func (r *retainedLedgerReader) GetLedgerSequenceRange(ctx context.Context) (uint32, uint32, error) {
+	return r.firstLedger, expectedLatestLedgerSequence, nil
+}
+
+func (r *retainedLedgerReader) GetLedgerRange(ctx context.Context) (db.LedgerRange, error) {
+	return db.LedgerRange{
+		FirstLedger: db.LedgerSeqAndCloseTime{Sequence: r.firstLedger, CloseTime: int64(5 * r.firstLedger)},
+		LastLedger:  db.LedgerSeqAndCloseTime{Sequence: expectedLatestLedgerSequence, CloseTime: int64(5 * expectedLatestLedgerSequence)},
+	}, nil
+}
+
+// recordingPreflightGetter records the simulated ledger and timestamp
+type recordingPreflightGetter struct {
+	fixedPreflightGetter
+	ledger    uint32
+	timestamp uint64
+}
+
+func (g *recordingPreflightGetter) GetPreflight(ctx context.Context, params preflight.PreflightGetterParameters) (preflight.Preflight, error) {
+	ledger, err := params.LedgerEntryReadTx.GetLatestLedgerSequence()
+	if err != nil {
+		return preflight.Preflight{}, err
+	}
+	g.ledger, g.timestamp = ledger, params.Timestamp
+	return g.fixedPreflightGetter.GetPreflight(ctx, params)
+}
+
+func TestSimulateTransactionAtLedger(t *testing.T) {
+	getter := &recordingPreflightGetter{}
+	simulate := func(ledger uint32) SimulateTransactionResponse {
+		return simulateTransaction(context.Background(), log.DefaultLogger, &ConstantLedgerEntryReader{},
+			&retainedLedgerReader{firstLedger: expectedLatestLedgerSequence - 10}, getter, nil, 100,
+			SimulateTransactionRequest{Transaction: invokeContractEnvelope(t), Ledger: ledger})
+	}
+
+	response := simulate(0)
+	require.Empty(t, response.Error)
+	assert.Equal(t, expectedLatestLedgerSequence, getter.ledger)
+	assert.Zero(t, getter.timestamp)
+
+	// past simulations happen when the next ledger closed
+	response = simulate(expectedLatestLedgerSequence - 10)
+	require.Empty(t, response.Error)
+	assert.Equal(t, expectedLatestLedgerSequence, response.LatestLedger)
+	assert.Equal(t, expectedLatestLedgerSequence-10, getter.ledger)
+	assert.Equal(t, uint64(5*(expectedLatestLedgerSequence-9)), getter.timestamp)
+
+	response = simulate(expectedLatestLedgerSequence - 11)
+	assert.Equal(t, "ledger 949 is outside the retention window [950, 960]", response.Error)
+	response = simulate(expectedLatestLedgerSequence + 1)
+	assert.Equal(t, "ledger 961 is outside the retention window [950, 960]", response.Error)
+}
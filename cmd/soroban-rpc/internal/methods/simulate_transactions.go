@@ -0,0 +1,195 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
+)
+
+type SimulateTransactionsRequest struct {
+	// Transactions are simulated in order, each one on top of the ledger changes of the previous ones
+	Transactions   []string                  `json:"transactions"`
+	ResourceConfig *preflight.ResourceConfig `json:"resourceConfig,omitempty"`
+	// LedgerEntryOverrides are layered over the ledger state before simulating the first transaction
+	LedgerEntryOverrides *LedgerEntryOverrides `json:"ledgerEntryOverrides,omitempty"`
+	// Format "json" additionally returns the decoded return values, auth entries and events
+	Format string `json:"format,omitempty"`
+}
+
+// SimulateTransactionsResponse is the response for the Soroban-RPC simulateTransactions() endpoint
+type SimulateTransactionsResponse struct {
+	// Results contains the simulation of each transaction, in the same order. Once a simulation fails,
+	// the following transactions aren't simulated and their results contain an error.
+	Results      []SimulateTransactionResponse `json:"results"`
+	LatestLedger uint32                        `json:"latestLedger"`
+}
+
+// applyLedgerChanges applies the LedgerEntryChange XDRs reported by a simulation
+func applyLedgerChanges(writer db.LedgerEntryWriter, changes [][]byte) error {
+	for _, changeXDR := range changes {
+		var change xdr.LedgerEntryChange
+		if err := xdr.SafeUnmarshal(changeXDR, &change); err != nil {
+			return err
+		}
+		var err error
+		switch change.Type {
+		case xdr.LedgerEntryChangeTypeLedgerEntryCreated:
+			err = writer.UpsertLedgerEntry(*change.Created)
+		case xdr.LedgerEntryChangeTypeLedgerEntryUpdated:
+			err = writer.UpsertLedgerEntry(*change.Updated)
+		case xdr.LedgerEntryChangeTypeLedgerEntryRemoved:
+			err = writer.DeleteLedgerEntry(*change.Removed)
+		default:
+			// the state before the change, nothing to apply
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewSimulateTransactionsHandler returns a json rpc handler to run the preflight simulations of
+// dependent transactions. Only the ledger changes of InvokeHostFunction operations carry over to the
+// following transactions.
+func NewSimulateTransactionsHandler(logger *log.Entry, ledgerEntryReader db.LedgerEntryReader, ledgerReader db.LedgerReader, getter PreflightGetter, maxBundleSize uint) jrpc2.Handler {
+	return handler.New(func(ctx context.Context, request SimulateTransactionsRequest) (SimulateTransactionsResponse, error) {
+		if len(request.Transactions) == 0 {
+			return SimulateTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: "at least one transaction must be provided",
+			}
+		}
+		if uint(len(request.Transactions)) > maxBundleSize {
+			return SimulateTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: fmt.Sprintf("maximum %d transactions per request", maxBundleSize),
+			}
+		}
+		if err := validateFormat(request.Format); err != nil {
+			return SimulateTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: err.Error(),
+			}
+		}
+		txEnvelopes := make([]xdr.TransactionEnvelope, len(request.Transactions))
+		for i, transaction := range request.Transactions {
+			if err := xdr.SafeUnmarshalBase64(transaction, &txEnvelopes[i]); err != nil {
+				logger.WithError(err).WithField("transaction", transaction).
+					Info("could not unmarshal simulate transactions envelope")
+				return SimulateTransactionsResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InvalidParams,
+					Message: fmt.Sprintf("could not unmarshal transaction %d", i+1),
+				}
+			}
+		}
+
+		readTx, err := ledgerEntryReader.NewCachedTx(ctx)
+		if err != nil {
+			return SimulateTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "cannot create read transaction",
+			}
+		}
+		defer func() {
+			_ = readTx.Done()
+		}()
+		latestLedger, err := readTx.GetLatestLedgerSequence()
+		if err != nil {
+			return SimulateTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		bucketListSize, err := getBucketListSize(ctx, ledgerReader, latestLedger)
+		if err != nil {
+			return SimulateTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+
+		overlay := db.NewLedgerEntryOverlayTx(readTx)
+		if request.LedgerEntryOverrides != nil {
+			if err := request.LedgerEntryOverrides.apply(overlay); err != nil {
+				return SimulateTransactionsResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InvalidParams,
+					Message: err.Error(),
+				}
+			}
+		}
+		resourceConfig := preflight.DefaultResourceConfig()
+		if request.ResourceConfig != nil {
+			resourceConfig = *request.ResourceConfig
+		}
+
+		response := SimulateTransactionsResponse{
+			Results:      make([]SimulateTransactionResponse, 0, len(txEnvelopes)),
+			LatestLedger: latestLedger,
+		}
+		failed := -1
+		for i, txEnvelope := range txEnvelopes {
+			if failed >= 0 {
+				response.Results = append(response.Results, SimulateTransactionResponse{
+					Error:        fmt.Sprintf("not simulated, transaction %d failed", failed+1),
+					LatestLedger: latestLedger,
+				})
+				continue
+			}
+			result := simulateBundledTransaction(ctx, getter, overlay, latestLedger, bucketListSize, resourceConfig, txEnvelope)
+			if request.Format == FormatJSON {
+				addSimulationJSON(&result)
+			}
+			if result.Error != "" {
+				failed = i
+			}
+			response.Results = append(response.Results, result)
+		}
+		return response, nil
+	})
+}
+
+// simulateBundledTransaction simulates the transaction on top of the overlay,
+// applying its ledger changes to the overlay if it succeeds.
+func simulateBundledTransaction(
+	ctx context.Context,
+	getter PreflightGetter,
+	overlay *db.LedgerEntryOverlayTx,
+	latestLedger uint32,
+	bucketListSize uint64,
+	resourceConfig preflight.ResourceConfig,
+	txEnvelope xdr.TransactionEnvelope,
+) SimulateTransactionResponse {
+	op, footprint, errMessage := simulationOperation(txEnvelope)
+	if errMessage != "" {
+		return SimulateTransactionResponse{Error: errMessage, LatestLedger: latestLedger}
+	}
+	result, err := getter.GetPreflight(ctx, preflight.PreflightGetterParameters{
+		LedgerEntryReadTx:   overlay,
+		BucketListSize:      bucketListSize,
+		SourceAccount:       simulationSourceAccount(txEnvelope, op),
+		OperationBody:       op.Body,
+		Footprint:           footprint,
+		ResourceConfig:      resourceConfig,
+		RecordLedgerChanges: true,
+	})
+	if err != nil {
+		return SimulateTransactionResponse{Error: err.Error(), LatestLedger: latestLedger}
+	}
+	if result.Error == "" {
+		if err := applyLedgerChanges(overlay, result.LedgerChanges); err != nil {
+			return SimulateTransactionResponse{
+				Error:        "cannot apply ledger changes: " + err.Error(),
+				LatestLedger: latestLedger,
+			}
+		}
+	}
+	return simulateTransactionResponse(result, latestLedger)
+}
@@ -0,0 +1,206 @@
+package methods
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
+)
+
+// counterPreflightGetter increments a counter entry, failing once it reaches failAt
+type counterPreflightGetter struct {
+	key    xdr.LedgerKey
+	failAt uint32
+}
+
+func (g counterPreflightGetter) counterEntry(value uint32) xdr.LedgerEntry {
+	data := *g.key.ContractData
+	val := xdr.Uint32(value)
+	return xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract:   data.Contract,
+				Key:        data.Key,
+				Durability: data.Durability,
+				Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &val},
+			},
+		},
+	}
+}
+
+func (g counterPreflightGetter) GetPreflight(ctx context.Context, params preflight.PreflightGetterParameters) (preflight.Preflight, error) {
+	present, entry, _, err := db.GetLedgerEntry(params.LedgerEntryReadTx, g.key)
+	if err != nil {
+		return preflight.Preflight{}, err
+	}
+	counter := uint32(0)
+	if present {
+		counter = uint32(*entry.Data.ContractData.Val.U32)
+	}
+	if counter == g.failAt {
+		return preflight.Preflight{Error: "counter overflow"}, nil
+	}
+	result, err := xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: (*xdr.Uint32)(&counter)}.MarshalBinary()
+	if err != nil {
+		return preflight.Preflight{}, err
+	}
+	updated := g.counterEntry(counter + 1)
+	change, err := xdr.LedgerEntryChange{
+		Type:    xdr.LedgerEntryChangeTypeLedgerEntryUpdated,
+		Updated: &updated,
+	}.MarshalBinary()
+	if err != nil {
+		return preflight.Preflight{}, err
+	}
+	var changes [][]byte
+	if params.RecordLedgerChanges {
+		changes = [][]byte{change}
+	}
+	return preflight.Preflight{Result: result, LedgerChanges: changes}, nil
+}
+
+func invokeContractEnvelope(t *testing.T) string {
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress(keypair.MustRandom().Address()),
+				Operations: []xdr.Operation{
+					{
+						Body: xdr.OperationBody{
+							Type: xdr.OperationTypeInvokeHostFunction,
+							InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+								HostFunction: xdr.HostFunction{
+									Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+									InvokeContract: &xdr.InvokeContractArgs{
+										ContractAddress: xdr.ScAddress{
+											Type:       xdr.ScAddressTypeScAddressTypeContract,
+											ContractId: &xdr.Hash{0x1, 0x2},
+										},
+										FunctionName: "increment",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	encoded, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+	return encoded
+}
+
+func TestSimulateTransactions(t *testing.T) {
+	counterKey := xdr.Uint32(0)
+	var key xdr.LedgerKey
+	require.NoError(t, key.SetContractData(
+		xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.Hash{0x1, 0x2}},
+		xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &counterKey},
+		xdr.ContractDataDurabilityPersistent,
+	))
+	getter := counterPreflightGetter{key: key, failAt: 2}
+	handler := NewSimulateTransactionsHandler(
+		log.DefaultLogger,
+		&ConstantLedgerEntryReader{},
+		&ConstantLedgerReader{},
+		getter,
+		3,
+	)
+	simulate := func(request SimulateTransactionsRequest) (SimulateTransactionsResponse, error) {
+		params, err := json.Marshal(request)
+		require.NoError(t, err)
+		requests, err := jrpc2.ParseRequests([]byte(`{"jsonrpc":"2.0","id":1,"method":"simulateTransactions","params":` + string(params) + `}`))
+		require.NoError(t, err)
+		result, err := handler(context.Background(), requests[0].ToRequest())
+		if err != nil {
+			return SimulateTransactionsResponse{}, err
+		}
+		return result.(SimulateTransactionsResponse), nil
+	}
+	counterResult := func(value uint32) string {
+		encoded, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: (*xdr.Uint32)(&value)})
+		require.NoError(t, err)
+		return encoded
+	}
+
+	// each transaction observes the changes of the previous ones
+	response, err := simulate(SimulateTransactionsRequest{
+		Transactions: []string{invokeContractEnvelope(t), invokeContractEnvelope(t), invokeContractEnvelope(t)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, expectedLatestLedgerSequence, response.LatestLedger)
+	require.Len(t, response.Results, 3)
+	assert.Equal(t, counterResult(0), response.Results[0].Results[0].XDR)
+	assert.Equal(t, counterResult(1), response.Results[1].Results[0].XDR)
+	assert.Equal(t, "counter overflow", response.Results[2].Error)
+
+	// the overrides are applied before the first transaction
+	override, err := xdr.MarshalBase64(getter.counterEntry(1))
+	require.NoError(t, err)
+	keyXDR, err := key.MarshalBinary()
+	require.NoError(t, err)
+	ttlOverride, err := xdr.MarshalBase64(xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeTtl,
+			Ttl:  &xdr.TtlEntry{KeyHash: sha256.Sum256(keyXDR), LiveUntilLedgerSeq: 1000},
+		},
+	})
+	require.NoError(t, err)
+	response, err = simulate(SimulateTransactionsRequest{
+		Transactions:         []string{invokeContractEnvelope(t), invokeContractEnvelope(t), invokeContractEnvelope(t)},
+		LedgerEntryOverrides: &LedgerEntryOverrides{Upserts: []string{override, ttlOverride}},
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Results, 3)
+	assert.Equal(t, counterResult(1), response.Results[0].Results[0].XDR)
+	assert.Equal(t, "counter overflow", response.Results[1].Error)
+	assert.Equal(t, "not simulated, transaction 2 failed", response.Results[2].Error)
+
+	// new contract data overrides require their TTL
+	_, err = simulate(SimulateTransactionsRequest{
+		Transactions:         []string{invokeContractEnvelope(t)},
+		LedgerEntryOverrides: &LedgerEntryOverrides{Upserts: []string{override}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, jrpc2.InvalidParams, err.(*jrpc2.Error).Code)
+	assert.Equal(t, "ledger entry override upsert 1 has no TTL entry in the overrides or the ledger state", err.(*jrpc2.Error).Message)
+
+	// the json format decodes the return values
+	response, err = simulate(SimulateTransactionsRequest{
+		Transactions: []string{invokeContractEnvelope(t)},
+		Format:       FormatJSON,
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Results, 1)
+	require.NotNil(t, response.Results[0].Results[0].ReturnValueJSON)
+	assert.Equal(t, "u32", response.Results[0].Results[0].ReturnValueJSON.Type)
+	assert.Equal(t, uint32(0), response.Results[0].Results[0].ReturnValueJSON.Value)
+	assert.Empty(t, response.Results[0].Results[0].AuthJSON)
+
+	_, err = simulate(SimulateTransactionsRequest{Transactions: []string{invokeContractEnvelope(t)}, Format: "yaml"})
+	require.Error(t, err)
+	assert.Equal(t, jrpc2.InvalidParams, err.(*jrpc2.Error).Code)
+
+	_, err = simulate(SimulateTransactionsRequest{
+		Transactions: []string{invokeContractEnvelope(t), invokeContractEnvelope(t), invokeContractEnvelope(t), invokeContractEnvelope(t)},
+	})
+	require.Error(t, err)
+	assert.Equal(t, jrpc2.InvalidParams, err.(*jrpc2.Error).Code)
+
+	_, err = simulate(SimulateTransactionsRequest{Transactions: []string{"invalid"}})
+	require.Error(t, err)
+	assert.Equal(t, "could not unmarshal transaction 1", err.(*jrpc2.Error).Message)
+}
@@ -0,0 +1,87 @@
+package methods
+
+import (
+	"crypto/sha256"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
+)
+
+type simulationCacheKey struct {
+	envelopeHash   [sha256.Size]byte
+	resourceConfig preflight.ResourceConfig
+	latestLedger   uint32
+}
+
+// SimulationCache is an LRU cache of simulateTransaction responses, keyed by transaction envelope,
+// resource config and latest ledger. Since the ledger state only changes when a ledger is ingested,
+// the cache is purged after every ingested ledger.
+// A nil cache is valid and never caches anything.
+type SimulationCache struct {
+	cache          *lru.Cache
+	requestsMetric *prometheus.CounterVec
+}
+
+// NewSimulationCache creates a cache of the given amount of simulations. Caching is disabled if size is 0.
+func NewSimulationCache(daemon interfaces.Daemon, size uint) (*SimulationCache, error) {
+	if size == 0 {
+		return nil, nil
+	}
+	cache, err := lru.New(int(size))
+	if err != nil {
+		return nil, err
+	}
+	requestsMetric := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: daemon.MetricsNamespace(), Subsystem: "simulation_cache", Name: "requests_total",
+		Help: "simulation cache lookups, by result (hit or miss)",
+	}, []string{"result"})
+	daemon.MetricsRegistry().MustRegister(requestsMetric)
+	return &SimulationCache{
+		cache:          cache,
+		requestsMetric: requestsMetric,
+	}, nil
+}
+
+func newSimulationCacheKey(txEnvelope xdr.TransactionEnvelope, resourceConfig preflight.ResourceConfig, latestLedger uint32) (simulationCacheKey, error) {
+	envelope, err := txEnvelope.MarshalBinary()
+	if err != nil {
+		return simulationCacheKey{}, err
+	}
+	return simulationCacheKey{
+		envelopeHash:   sha256.Sum256(envelope),
+		resourceConfig: resourceConfig,
+		latestLedger:   latestLedger,
+	}, nil
+}
+
+func (c *SimulationCache) get(key simulationCacheKey) (SimulateTransactionResponse, bool) {
+	if c == nil {
+		return SimulateTransactionResponse{}, false
+	}
+	value, ok := c.cache.Get(key)
+	if !ok {
+		c.requestsMetric.With(prometheus.Labels{"result": "miss"}).Inc()
+		return SimulateTransactionResponse{}, false
+	}
+	c.requestsMetric.With(prometheus.Labels{"result": "hit"}).Inc()
+	return value.(SimulateTransactionResponse), true
+}
+
+func (c *SimulationCache) add(key simulationCacheKey, response SimulateTransactionResponse) {
+	if c == nil {
+		return
+	}
+	c.cache.Add(key, response)
+}
+
+// Purge removes all the cached simulations. It must be called whenever the ledger state changes.
+func (c *SimulationCache) Purge() {
+	if c == nil {
+		return
+	}
+	c.cache.Purge()
+}
@@ -0,0 +1,68 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stellar/go/support/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
+)
+
+// countingPreflightGetter counts the preflights it runs
+type countingPreflightGetter struct {
+	fixedPreflightGetter
+	count int
+}
+
+func (g *countingPreflightGetter) GetPreflight(ctx context.Context, params preflight.PreflightGetterParameters) (preflight.Preflight, error) {
+	g.count++
+	return g.fixedPreflightGetter.GetPreflight(ctx, params)
+}
+
+func TestSimulationCache(t *testing.T) {
+	cache, err := NewSimulationCache(interfaces.MakeNoOpDeamon(), 10)
+	require.NoError(t, err)
+	getter := &countingPreflightGetter{}
+	simulate := func(request SimulateTransactionRequest) SimulateTransactionResponse {
+		return simulateTransaction(context.Background(), log.DefaultLogger, &ConstantLedgerEntryReader{}, &ConstantLedgerReader{}, getter, cache, 0, request)
+	}
+	envelope := invokeContractEnvelope(t)
+
+	first := simulate(SimulateTransactionRequest{Transaction: envelope})
+	require.Empty(t, first.Error)
+	second := simulate(SimulateTransactionRequest{Transaction: envelope})
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, getter.count)
+	assert.Equal(t, 1.0, testutil.ToFloat64(cache.requestsMetric.With(prometheus.Labels{"result": "hit"})))
+	assert.Equal(t, 1.0, testutil.ToFloat64(cache.requestsMetric.With(prometheus.Labels{"result": "miss"})))
+
+	// decoding cached responses doesn't modify them
+	decoded := simulate(SimulateTransactionRequest{Transaction: envelope, Format: FormatJSON})
+	assert.NotNil(t, decoded.Results[0].ReturnValueJSON)
+	assert.Equal(t, first, simulate(SimulateTransactionRequest{Transaction: envelope}))
+	assert.Equal(t, 1, getter.count)
+
+	// a different resource config, the bypass or overrides require a new simulation
+	simulate(SimulateTransactionRequest{Transaction: envelope, ResourceConfig: &preflight.ResourceConfig{InstructionLeeway: 1}})
+	assert.Equal(t, 2, getter.count)
+	simulate(SimulateTransactionRequest{Transaction: envelope, BypassCache: true})
+	assert.Equal(t, 3, getter.count)
+	simulate(SimulateTransactionRequest{Transaction: envelope, LedgerEntryOverrides: &LedgerEntryOverrides{}})
+	assert.Equal(t, 4, getter.count)
+
+	cache.Purge()
+	simulate(SimulateTransactionRequest{Transaction: envelope})
+	assert.Equal(t, 5, getter.count)
+
+	// a nil cache doesn't cache anything
+	cache = nil
+	simulate(SimulateTransactionRequest{Transaction: envelope})
+	simulate(SimulateTransactionRequest{Transaction: envelope})
+	assert.Equal(t, 7, getter.count)
+}
@@ -0,0 +1,132 @@
+package methods
+
+import (
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrjson"
+)
+
+// diagnostic event topics emitted by the host when preflight debugging is enabled
+const (
+	fnCallTopic   = "fn_call"
+	fnReturnTopic = "fn_return"
+)
+
+// CallFrame is a contract function invocation of a simulation call tree.
+//
+// Args are taken from the fn_call diagnostic event, whose data is the only argument or
+// a vector of all the arguments, so a single vector argument is reported as its items.
+// The host only reports the resources of the whole invocation (in the core_metrics events),
+// so CPUInstructions and MemoryBytes are only present in the top-level frame.
+type CallFrame struct {
+	ContractID      string              `json:"contractId"`
+	Function        string              `json:"function"`
+	Args            []xdrjson.ScValJSON `json:"args"`
+	ReturnValue     *xdrjson.ScValJSON  `json:"returnValue,omitempty"`
+	Failed          bool                `json:"failed,omitempty"` // the frame didn't return
+	CPUInstructions *uint64             `json:"cpuInsns,string,omitempty"`
+	MemoryBytes     *uint64             `json:"memBytes,string,omitempty"`
+	Calls           []*CallFrame        `json:"calls"`
+}
+
+// simulationTrace builds the call tree of a simulation from its diagnostic events.
+// It returns nil if the events don't contain any calls (e.g. if preflight debugging is disabled).
+func simulationTrace(events [][]byte) []*CallFrame {
+	var (
+		roots []*CallFrame
+		stack []*CallFrame
+		cpu   *uint64
+		mem   *uint64
+	)
+	for _, eventXDR := range events {
+		var event xdr.DiagnosticEvent
+		if err := xdr.SafeUnmarshal(eventXDR, &event); err != nil {
+			return nil
+		}
+		body, ok := event.Event.Body.GetV0()
+		if !ok || event.Event.Type != xdr.ContractEventTypeDiagnostic || len(body.Topics) < 2 {
+			continue
+		}
+		topic, ok := body.Topics[0].GetSym()
+		if !ok {
+			continue
+		}
+		switch string(topic) {
+		case fnCallTopic:
+			frame, ok := newCallFrame(body)
+			if !ok {
+				continue
+			}
+			if len(stack) == 0 {
+				roots = append(roots, frame)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Calls = append(parent.Calls, frame)
+			}
+			stack = append(stack, frame)
+		case fnReturnTopic:
+			function, ok := body.Topics[1].GetSym()
+			if !ok || event.Event.ContractId == nil {
+				continue
+			}
+			contractID := strkey.MustEncode(strkey.VersionByteContract, event.Event.ContractId[:])
+			// frames above the returning one trapped without returning
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i].ContractID != contractID || stack[i].Function != string(function) {
+					continue
+				}
+				for _, failed := range stack[i+1:] {
+					failed.Failed = true
+				}
+				returnValue := xdrjson.ScValToJSON(body.Data)
+				stack[i].ReturnValue = &returnValue
+				stack = stack[:i]
+				break
+			}
+		case xdrjson.CoreMetricsTopic:
+			metric, value, ok := xdrjson.CoreMetric(body)
+			if !ok {
+				continue
+			}
+			switch metric {
+			case xdrjson.CPUInsnMetric:
+				cpu = &value
+			case xdrjson.MemByteMetric:
+				mem = &value
+			}
+		}
+	}
+	for _, frame := range stack {
+		frame.Failed = true
+	}
+	if len(roots) == 1 {
+		roots[0].CPUInstructions = cpu
+		roots[0].MemoryBytes = mem
+	}
+	return roots
+}
+
+func newCallFrame(body xdr.ContractEventV0) (*CallFrame, bool) {
+	if len(body.Topics) < 3 {
+		return nil, false
+	}
+	contractID, ok := body.Topics[1].GetBytes()
+	if !ok || len(contractID) != len(xdr.Hash{}) {
+		return nil, false
+	}
+	function, ok := body.Topics[2].GetSym()
+	if !ok {
+		return nil, false
+	}
+	args := []xdrjson.ScValJSON{xdrjson.ScValToJSON(body.Data)}
+	if vec, ok := body.Data.GetVec(); ok && vec != nil {
+		args = xdrjson.ScValsToJSON(*vec)
+	}
+	return &CallFrame{
+		ContractID: strkey.MustEncode(strkey.VersionByteContract, contractID),
+		Function:   string(function),
+		Args:       args,
+		Calls:      []*CallFrame{},
+	}, true
+}
@@ -0,0 +1,98 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrjson"
+)
+
+func diagnosticEventXDR(t *testing.T, contractID *xdr.Hash, data xdr.ScVal, topics ...xdr.ScVal) []byte {
+	event, err := xdr.DiagnosticEvent{
+		InSuccessfulContractCall: true,
+		Event: xdr.ContractEvent{
+			ContractId: contractID,
+			Type:       xdr.ContractEventTypeDiagnostic,
+			Body: xdr.ContractEventBody{
+				V:  0,
+				V0: &xdr.ContractEventV0{Topics: topics, Data: data},
+			},
+		},
+	}.MarshalBinary()
+	require.NoError(t, err)
+	return event
+}
+
+func symbolScVal(sym string) xdr.ScVal {
+	scSym := xdr.ScSymbol(sym)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &scSym}
+}
+
+func u32ScVal(v uint32) xdr.ScVal {
+	scU32 := xdr.Uint32(v)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &scU32}
+}
+
+func TestSimulationTrace(t *testing.T) {
+	outer, inner, failing := xdr.Hash{0x1}, xdr.Hash{0x2}, xdr.Hash{0x3}
+	fnCall := func(contractID xdr.Hash, function string, args ...xdr.ScVal) []byte {
+		id := xdr.ScBytes(contractID[:])
+		data := xdr.ScVal{Type: xdr.ScValTypeScvVec}
+		if len(args) == 1 {
+			data = args[0]
+		} else {
+			vec := xdr.ScVec(args)
+			vecPtr := &vec
+			data.Vec = &vecPtr
+		}
+		return diagnosticEventXDR(t, nil, data,
+			symbolScVal(fnCallTopic), xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &id}, symbolScVal(function))
+	}
+	fnReturn := func(contractID xdr.Hash, function string, value xdr.ScVal) []byte {
+		return diagnosticEventXDR(t, &contractID, value, symbolScVal(fnReturnTopic), symbolScVal(function))
+	}
+	coreMetric := func(metric string, value uint64) []byte {
+		v := xdr.Uint64(value)
+		return diagnosticEventXDR(t, nil, xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &v}, symbolScVal(xdrjson.CoreMetricsTopic), symbolScVal(metric))
+	}
+
+	assert.Nil(t, simulationTrace(nil))
+
+	trace := simulationTrace([][]byte{
+		fnCall(outer, "swap", u32ScVal(1), u32ScVal(2)),
+		fnCall(inner, "transfer", u32ScVal(3)),
+		fnReturn(inner, "transfer", u32ScVal(4)),
+		fnCall(failing, "check"),
+		fnReturn(outer, "swap", u32ScVal(5)),
+		coreMetric(xdrjson.CPUInsnMetric, 1000),
+		coreMetric(xdrjson.MemByteMetric, 2000),
+	})
+	require.Len(t, trace, 1)
+	root := trace[0]
+	assert.Equal(t, strkey.MustEncode(strkey.VersionByteContract, outer[:]), root.ContractID)
+	assert.Equal(t, "swap", root.Function)
+	require.Len(t, root.Args, 2)
+	assert.Equal(t, uint32(2), root.Args[1].Value)
+	require.NotNil(t, root.ReturnValue)
+	assert.Equal(t, uint32(5), root.ReturnValue.Value)
+	assert.False(t, root.Failed)
+	require.NotNil(t, root.CPUInstructions)
+	assert.Equal(t, uint64(1000), *root.CPUInstructions)
+	require.NotNil(t, root.MemoryBytes)
+	assert.Equal(t, uint64(2000), *root.MemoryBytes)
+
+	require.Len(t, root.Calls, 2)
+	assert.Equal(t, "transfer", root.Calls[0].Function)
+	require.Len(t, root.Calls[0].Args, 1)
+	assert.Equal(t, uint32(3), root.Calls[0].Args[0].Value)
+	assert.Equal(t, uint32(4), root.Calls[0].ReturnValue.Value)
+	assert.Nil(t, root.Calls[0].CPUInstructions)
+	assert.Equal(t, "check", root.Calls[1].Function)
+	assert.Empty(t, root.Calls[1].Args)
+	assert.Nil(t, root.Calls[1].ReturnValue)
+	assert.True(t, root.Calls[1].Failed)
+}
@@ -0,0 +1,253 @@
+package methods
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	proto "github.com/stellar/go/protocols/stellarcore"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
+)
+
+const (
+	// SubmissionStatusExpired indicates the transaction wasn't ingested before its time bounds expired
+	// (or before the tracker stopped retrying it).
+	SubmissionStatusExpired = "EXPIRED"
+
+	submissionTrackerPollInterval = time.Second
+	maxSubmissionRetryBackoff     = time.Minute
+)
+
+var (
+	// pendingSubmissionStatuses are the statuses of the submissions which are still tracked
+	pendingSubmissionStatuses = []string{proto.TXStatusPending, proto.TXStatusDuplicate, proto.TXStatusTryAgainLater}
+	// finalSubmissionStatuses are the statuses of the submissions which are no longer tracked
+	finalSubmissionStatuses = []string{proto.TXStatusError, TransactionStatusSuccess, TransactionStatusFailed, SubmissionStatusExpired}
+)
+
+// TrackedTransactionStore is the store used to find out whether the tracked transactions were ingested
+type TrackedTransactionStore interface {
+	transactionGetter
+	LatestLedgerStore
+}
+
+type SubmissionTrackerConfig struct {
+	Logger           *log.Entry
+	Daemon           interfaces.Daemon
+	Submitter        interfaces.CoreClient
+	Store            db.SubmissionStore
+	TransactionStore TrackedTransactionStore
+	// RetryBackoff is the delay before the first resubmission of a TRY_AGAIN_LATER transaction,
+	// which doubles after every attempt (up to a minute)
+	RetryBackoff time.Duration
+	// MaxRetryDuration is the maximum time a submission is tracked, for transactions without
+	// time bounds or with a later max time
+	MaxRetryDuration time.Duration
+	// RetentionWindow is the time the submissions are kept once they are no longer tracked
+	RetentionWindow time.Duration
+}
+
+// SubmissionTracker persists the transactions submitted through sendTransaction, resubmitting
+// the ones rejected with TRY_AGAIN_LATER until they expire and recording the final results once
+// they are ingested.
+// A nil tracker is valid and doesn't track anything.
+type SubmissionTracker struct {
+	logger              *log.Entry
+	submitter           interfaces.CoreClient
+	store               db.SubmissionStore
+	transactionStore    TrackedTransactionStore
+	retryBackoff        time.Duration
+	maxRetryDuration    time.Duration
+	retentionWindow     time.Duration
+	resubmissionsMetric *prometheus.CounterVec
+	now                 func() time.Time
+	done                context.CancelFunc
+	wg                  sync.WaitGroup
+}
+
+func NewSubmissionTracker(cfg SubmissionTrackerConfig) *SubmissionTracker {
+	resubmissionsMetric := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: cfg.Daemon.MetricsNamespace(), Subsystem: "submission_tracker", Name: "resubmissions_total",
+		Help: "transaction resubmissions, by stellar-core response status",
+	}, []string{"status"})
+	cfg.Daemon.MetricsRegistry().MustRegister(resubmissionsMetric)
+	return &SubmissionTracker{
+		logger:              cfg.Logger,
+		submitter:           cfg.Submitter,
+		store:               cfg.Store,
+		transactionStore:    cfg.TransactionStore,
+		retryBackoff:        cfg.RetryBackoff,
+		maxRetryDuration:    cfg.MaxRetryDuration,
+		retentionWindow:     cfg.RetentionWindow,
+		resubmissionsMetric: resubmissionsMetric,
+		now:                 time.Now,
+	}
+}
+
+// Start runs the tracker in the background until Close is called.
+func (t *SubmissionTracker) Start() {
+	ctx, done := context.WithCancel(context.Background())
+	t.done = done
+	t.wg.Add(1)
+	panicGroup := util.UnrecoverablePanicGroup.Log(t.logger)
+	panicGroup.Go(func() {
+		defer t.wg.Done()
+		ticker := time.NewTicker(submissionTrackerPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := t.poll(ctx); err != nil && ctx.Err() == nil {
+					t.logger.WithError(err).Error("could not update tracked submissions")
+				}
+			}
+		}
+	})
+}
+
+// Close stops the tracker, waiting for the ongoing updates to finish.
+func (t *SubmissionTracker) Close() {
+	if t == nil || t.done == nil {
+		return
+	}
+	t.done()
+	t.wg.Wait()
+}
+
+func (t *SubmissionTracker) retryDelay(attempts uint32) time.Duration {
+	delay := t.retryBackoff
+	for i := uint32(1); i < attempts && delay < maxSubmissionRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxSubmissionRetryBackoff {
+		return maxSubmissionRetryBackoff
+	}
+	return delay
+}
+
+// withStatus returns the submission updated after an attempt which resulted in the given status
+func (t *SubmissionTracker) withStatus(submission db.Submission, status string, now time.Time) db.Submission {
+	submission.Status = status
+	submission.UpdatedAt = now.Unix()
+	submission.NextAttemptAt = 0
+	if status == proto.TXStatusTryAgainLater {
+		submission.NextAttemptAt = now.Add(t.retryDelay(submission.Attempts)).Unix()
+	}
+	return submission
+}
+
+// track records the submission of a transaction by a client, with the status returned by stellar-core.
+// Transactions which were already tracked are tracked again unless they were already ingested.
+func (t *SubmissionTracker) track(ctx context.Context, hash string, envelope xdr.TransactionEnvelope, envelopeXDR string, status string, errorResultXDR string) error {
+	if t == nil {
+		return nil
+	}
+	now := t.now()
+	expiresAt := now.Add(t.maxRetryDuration).Unix()
+	if timeBounds := envelope.TimeBounds(); timeBounds != nil && timeBounds.MaxTime != 0 && int64(timeBounds.MaxTime) < expiresAt {
+		expiresAt = int64(timeBounds.MaxTime)
+	}
+
+	previous, _, ok, err := t.store.GetSubmission(ctx, hash)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		submission := t.withStatus(db.Submission{
+			Hash:        hash,
+			Envelope:    envelopeXDR,
+			Attempts:    1,
+			SubmittedAt: now.Unix(),
+			ExpiresAt:   expiresAt,
+		}, status, now)
+		return t.store.InsertSubmission(ctx, submission, errorResultXDR)
+	}
+	if previous.Status == TransactionStatusSuccess || previous.Status == TransactionStatusFailed {
+		return nil
+	}
+	updated := previous
+	updated.Attempts++
+	updated.ExpiresAt = expiresAt
+	// a concurrent update is as recent as this one, so there is no need to retry
+	_, err = t.store.UpdateSubmission(ctx, previous, t.withStatus(updated, status, now), errorResultXDR)
+	return err
+}
+
+// poll updates all the tracked submissions, finalizing the ingested or expired ones and
+// resubmitting the ones due for a retry. It also trims the submissions outside the retention window.
+func (t *SubmissionTracker) poll(ctx context.Context) error {
+	submissions, err := t.store.GetSubmissionsByStatus(ctx, pendingSubmissionStatuses)
+	if err != nil {
+		return err
+	}
+	for _, submission := range submissions {
+		if err := t.update(ctx, submission); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			t.logger.WithError(err).WithField("hash", submission.Hash).Error("could not update tracked submission")
+		}
+	}
+	return t.store.TrimSubmissions(ctx, finalSubmissionStatuses, t.now().Add(-t.retentionWindow).Unix())
+}
+
+func (t *SubmissionTracker) update(ctx context.Context, submission db.Submission) error {
+	now := t.now()
+	var hash xdr.Hash
+	if _, err := hex.Decode(hash[:], []byte(submission.Hash)); err != nil {
+		return err
+	}
+	if tx, found, _ := t.transactionStore.GetTransaction(hash); found {
+		status := TransactionStatusFailed
+		if tx.Successful {
+			status = TransactionStatusSuccess
+		}
+		updated := t.withStatus(submission, status, now)
+		updated.Ledger = tx.Ledger.Sequence
+		_, err := t.store.UpdateSubmission(ctx, submission, updated, "")
+		return err
+	}
+	// the transaction can no longer be included once a ledger closes after its max time
+	if t.transactionStore.GetLatestLedger().CloseTime > submission.ExpiresAt {
+		_, err := t.store.UpdateSubmission(ctx, submission, t.withStatus(submission, SubmissionStatusExpired, now), "")
+		return err
+	}
+	if submission.Status != proto.TXStatusTryAgainLater || now.Unix() < submission.NextAttemptAt {
+		return nil
+	}
+
+	updated := submission
+	updated.Attempts++
+	resp, err := t.submitter.SubmitTransaction(ctx, submission.Envelope)
+	if err == nil && resp.IsException() {
+		t.logger.WithField("exception", resp.Exception).WithField("hash", submission.Hash).
+			Error("received exception from stellar core when resubmitting transaction")
+		resp.Status = proto.TXStatusTryAgainLater
+	} else if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		t.logger.WithError(err).WithField("hash", submission.Hash).Error("could not resubmit transaction")
+		// retry later as if stellar-core had asked to
+		resp = &proto.TXResponse{Status: proto.TXStatusTryAgainLater}
+	}
+	switch resp.Status {
+	case proto.TXStatusError, proto.TXStatusPending, proto.TXStatusDuplicate, proto.TXStatusTryAgainLater:
+	default:
+		t.logger.WithField("status", resp.Status).WithField("hash", submission.Hash).
+			Error("Unrecognized stellar-core status response when resubmitting transaction")
+		resp.Status = proto.TXStatusTryAgainLater
+	}
+	t.resubmissionsMetric.With(prometheus.Labels{"status": resp.Status}).Inc()
+	_, err = t.store.UpdateSubmission(ctx, submission, t.withStatus(updated, resp.Status, now), resp.Error)
+	return err
+}
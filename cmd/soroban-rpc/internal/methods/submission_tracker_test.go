@@ -0,0 +1,146 @@
+package methods
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	proto "github.com/stellar/go/protocols/stellarcore"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/transactions"
+)
+
+// queuedCoreClient responds to the submissions with the queued statuses
+type queuedCoreClient struct {
+	statuses    []string
+	submissions int
+}
+
+func (c *queuedCoreClient) Info(context.Context) (*proto.InfoResponse, error) {
+	return &proto.InfoResponse{}, nil
+}
+
+func (c *queuedCoreClient) SubmitTransaction(context.Context, string) (*proto.TXResponse, error) {
+	status := c.statuses[0]
+	c.statuses = c.statuses[1:]
+	c.submissions++
+	return &proto.TXResponse{Status: status}, nil
+}
+
+type fakeTransactionStore struct {
+	transactions map[xdr.Hash]transactions.Transaction
+	latestLedger transactions.LedgerInfo
+}
+
+func (s *fakeTransactionStore) GetTransaction(hash xdr.Hash) (transactions.Transaction, bool, transactions.StoreRange) {
+	tx, ok := s.transactions[hash]
+	return tx, ok, transactions.StoreRange{LastLedger: s.latestLedger}
+}
+
+func (s *fakeTransactionStore) GetLatestLedger() transactions.LedgerInfo {
+	return s.latestLedger
+}
+
+func TestSubmissionTracker(t *testing.T) {
+	ctx := context.Background()
+	dbInstance, err := db.OpenSQLiteDB(path.Join(t.TempDir(), "soroban_rpc.sqlite"))
+	require.NoError(t, err)
+	defer dbInstance.Close()
+	core := &queuedCoreClient{statuses: []string{proto.TXStatusTryAgainLater, proto.TXStatusPending}}
+	store := &fakeTransactionStore{transactions: map[xdr.Hash]transactions.Transaction{}}
+	tracker := NewSubmissionTracker(SubmissionTrackerConfig{
+		Logger:           log.DefaultLogger,
+		Daemon:           interfaces.MakeNoOpDeamon(),
+		Submitter:        core,
+		Store:            db.NewSubmissionStore(dbInstance),
+		TransactionStore: store,
+		RetryBackoff:     2 * time.Second,
+		MaxRetryDuration: time.Minute,
+		RetentionWindow:  time.Hour,
+	})
+	now := time.Unix(1000, 0)
+	tracker.now = func() time.Time { return now }
+	getStatus := func(hash string) (GetSubmissionStatusResponse, error) {
+		handler := NewGetSubmissionStatusHandler(log.DefaultLogger, tracker)
+		requests, err := jrpc2.ParseRequests([]byte(`{"jsonrpc":"2.0","id":1,"method":"getSubmissionStatus","params":{"hash":"` + hash + `"}}`))
+		require.NoError(t, err)
+		result, err := handler(ctx, requests[0].ToRequest())
+		if err != nil {
+			return GetSubmissionStatusResponse{}, err
+		}
+		return result.(GetSubmissionStatusResponse), nil
+	}
+
+	var envelope xdr.TransactionEnvelope
+	envelopeXDR := invokeContractEnvelope(t)
+	require.NoError(t, xdr.SafeUnmarshalBase64(envelopeXDR, &envelope))
+	hash := xdr.Hash{1}
+	require.NoError(t, tracker.track(ctx, hash.HexString(), envelope, envelopeXDR, proto.TXStatusTryAgainLater, ""))
+
+	// the transaction is resubmitted with backoff until it's accepted
+	require.NoError(t, tracker.poll(ctx))
+	assert.Equal(t, 0, core.submissions)
+	now = now.Add(2 * time.Second)
+	require.NoError(t, tracker.poll(ctx))
+	assert.Equal(t, 1, core.submissions)
+	status, err := getStatus(hash.HexString())
+	require.NoError(t, err)
+	assert.Equal(t, proto.TXStatusTryAgainLater, status.Status)
+	assert.Equal(t, uint32(2), status.Attempts)
+	assert.Equal(t, now.Add(4*time.Second).Unix(), status.NextAttemptAt)
+	assert.Equal(t, int64(1060), status.ExpiresAt)
+	now = now.Add(4 * time.Second)
+	require.NoError(t, tracker.poll(ctx))
+	assert.Equal(t, 2, core.submissions)
+
+	// once ingested, the submission links to the transaction
+	store.transactions[hash] = transactions.Transaction{
+		Hash:       hash,
+		Successful: true,
+		Ledger:     transactions.LedgerInfo{Sequence: 10, CloseTime: now.Unix()},
+	}
+	now = now.Add(time.Second)
+	require.NoError(t, tracker.poll(ctx))
+	status, err = getStatus(hash.HexString())
+	require.NoError(t, err)
+	assert.Equal(t, TransactionStatusSuccess, status.Status)
+	assert.Equal(t, uint32(10), status.Ledger)
+	require.NotNil(t, status.Transaction)
+	assert.Equal(t, TransactionStatusSuccess, status.Transaction.Status)
+	assert.Equal(t, []SubmissionStatusChange{
+		{Status: proto.TXStatusTryAgainLater, Timestamp: 1000},
+		{Status: proto.TXStatusPending, Timestamp: 1006},
+		{Status: TransactionStatusSuccess, Timestamp: 1007},
+	}, status.History)
+
+	// submissions which aren't ingested before their time bounds expire are no longer tracked
+	envelope.V1.Tx.Cond = xdr.NewPreconditionsWithTimeBounds(&xdr.TimeBounds{MaxTime: 1030})
+	expiredHash := xdr.Hash{2}
+	require.NoError(t, tracker.track(ctx, expiredHash.HexString(), envelope, envelopeXDR, proto.TXStatusPending, ""))
+	store.latestLedger = transactions.LedgerInfo{Sequence: 11, CloseTime: 1031}
+	require.NoError(t, tracker.poll(ctx))
+	status, err = getStatus(expiredHash.HexString())
+	require.NoError(t, err)
+	assert.Equal(t, SubmissionStatusExpired, status.Status)
+	assert.Equal(t, int64(1030), status.ExpiresAt)
+
+	// and are trimmed after the retention window
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, tracker.poll(ctx))
+	_, err = getStatus(expiredHash.HexString())
+	require.Error(t, err)
+	assert.Equal(t, "transaction submission not found", err.(*jrpc2.Error).Message)
+
+	tracker = nil
+	_, err = getStatus(hash.HexString())
+	require.Error(t, err)
+	assert.Equal(t, "submission tracking is disabled", err.(*jrpc2.Error).Message)
+}
@@ -0,0 +1,312 @@
+package methods
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
+)
+
+// EventNotificationMethod is the method of the notifications pushed to event subscribers
+const EventNotificationMethod = "eventNotification"
+
+type SubscribeEventsRequest struct {
+	// StartLedger replays the events from the given ledger before streaming new ones.
+	StartLedger uint32 `json:"startLedger,omitempty"`
+	// Cursor replays the events after the given cursor before streaming new ones.
+	Cursor  *events.Cursor `json:"cursor,omitempty"`
+	Filters []EventFilter  `json:"filters"`
+}
+
+func (r *SubscribeEventsRequest) valid() error {
+	if r.Cursor != nil && r.StartLedger != 0 {
+		return errors.New("startLedger and cursor cannot both be set")
+	}
+	return validFilters(r.Filters)
+}
+
+// SubscribeEventsResponse is the response for the Soroban-RPC subscribeEvents() endpoint
+type SubscribeEventsResponse struct {
+	// Subscription identifies the notifications of the subscription, and is used to unsubscribe.
+	Subscription string `json:"subscription"`
+	// Events are the replayed events, when resuming from a ledger or a cursor.
+	// The following events are pushed as notifications.
+	Events []EventInfo `json:"events"`
+	// LatestLedger is the latest ledger included in the replayed events.
+	LatestLedger uint32 `json:"latestLedger"`
+}
+
+type UnsubscribeRequest struct {
+	Subscription string `json:"subscription"`
+}
+
+// EventNotification is pushed to the subscribers with the matching events of newly ingested ledgers
+type EventNotification struct {
+	Subscription string      `json:"subscription"`
+	Events       []EventInfo `json:"events"`
+}
+
+type notifier interface {
+	Notify(ctx context.Context, method string, params any) error
+	Stop()
+}
+
+type eventSubscription struct {
+	filters []EventFilter
+	// start is the cursor of the first event to push, the previous ones were replayed
+	start events.Cursor
+}
+
+// EventSubscriptions holds the event subscriptions of a connection.
+// All the subscriptions of a connection share a single buffer in the event store,
+// if the connection doesn't keep up with the ingested events it's closed, and
+// the client can resubscribe from the cursor of the last event received.
+type EventSubscriptions struct {
+	store            *events.MemoryStore
+	maxSubscriptions uint
+	bufferSize       uint
+	maxReplay        uint
+
+	lock          sync.Mutex
+	source        *events.Subscription
+	subscriptions map[string]*eventSubscription
+}
+
+// NewEventSubscriptions creates the event subscriptions of a new connection
+func NewEventSubscriptions(store *events.MemoryStore, maxSubscriptions, bufferSize, maxReplay uint) *EventSubscriptions {
+	return &EventSubscriptions{
+		store:            store,
+		maxSubscriptions: maxSubscriptions,
+		bufferSize:       bufferSize,
+		maxReplay:        maxReplay,
+		subscriptions:    make(map[string]*eventSubscription),
+	}
+}
+
+// Close cancels all the subscriptions of the connection
+func (s *EventSubscriptions) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.source != nil {
+		s.source.Close()
+	}
+	s.subscriptions = make(map[string]*eventSubscription)
+}
+
+func newSubscriptionID() (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id[:]), nil
+}
+
+func (s *EventSubscriptions) subscribe(server notifier, request SubscribeEventsRequest) (SubscribeEventsResponse, error) {
+	if err := request.valid(); err != nil {
+		return SubscribeEventsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if uint(len(s.subscriptions)) >= s.maxSubscriptions {
+		return SubscribeEventsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidRequest,
+			Message: fmt.Sprintf("maximum %d subscriptions per connection", s.maxSubscriptions),
+		}
+	}
+	id, err := newSubscriptionID()
+	if err != nil {
+		return SubscribeEventsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	if s.source == nil {
+		// subscribe before replaying, so that no ledger is missed in between
+		s.source = s.store.Subscribe(s.bufferSize)
+		go s.dispatch(server, s.source)
+	}
+
+	response := SubscribeEventsResponse{
+		Subscription: id,
+		Events:       []EventInfo{},
+		LatestLedger: s.store.GetLatestLedger(),
+	}
+	if request.Cursor != nil || request.StartLedger != 0 {
+		if response.Events, response.LatestLedger, err = s.replay(request); err != nil {
+			return SubscribeEventsResponse{}, err
+		}
+	}
+	s.subscriptions[id] = &eventSubscription{
+		filters: request.Filters,
+		start:   events.Cursor{Ledger: response.LatestLedger + 1},
+	}
+	return response, nil
+}
+
+// replay returns the matching events already in the store. It must be called with the lock held.
+func (s *EventSubscriptions) replay(request SubscribeEventsRequest) ([]EventInfo, uint32, error) {
+	start := events.Cursor{Ledger: request.StartLedger}
+	if request.Cursor != nil {
+		start = *request.Cursor
+		// we start with the event right after the cursor
+		start.Event++
+	}
+	var found []events.EventInfoRaw
+	tooMany := false
+	latestLedger, err := s.store.Scan(
+		events.Range{
+			Start:      start,
+			ClampStart: false,
+			End:        events.MaxCursor,
+			ClampEnd:   true,
+		},
+		func(event xdr.DiagnosticEvent, cursor events.Cursor, ledgerCloseTimestamp int64, txHash *xdr.Hash) bool {
+			if !matchesFilters(request.Filters, event) {
+				return true
+			}
+			if uint(len(found)) >= s.maxReplay {
+				tooMany = true
+				return false
+			}
+			found = append(found, events.EventInfoRaw{
+				Event:          event,
+				Cursor:         cursor,
+				LedgerClosedAt: time.Unix(ledgerCloseTimestamp, 0).UTC().Format(time.RFC3339),
+				TxHash:         txHash,
+			})
+			return true
+		},
+	)
+	if err != nil {
+		return nil, 0, &jrpc2.Error{
+			Code:    jrpc2.InvalidRequest,
+			Message: err.Error(),
+		}
+	}
+	if tooMany {
+		return nil, 0, &jrpc2.Error{
+			Code:    jrpc2.InvalidRequest,
+			Message: fmt.Sprintf("more than %d events to replay, catch up with getEvents first", s.maxReplay),
+		}
+	}
+	infos, err := eventInfosForEvents(found)
+	if err != nil {
+		return nil, 0, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	return infos, latestLedger, nil
+}
+
+func eventInfosForEvents(found []events.EventInfoRaw) ([]EventInfo, error) {
+	infos := make([]EventInfo, 0, len(found))
+	for _, event := range found {
+		info, err := eventInfoForEvent(event.Event, event.Cursor, event.LedgerClosedAt, event.TxHash.HexString())
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse event")
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+func (s *EventSubscriptions) unsubscribe(id string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.subscriptions[id]; !ok {
+		return false
+	}
+	delete(s.subscriptions, id)
+	return true
+}
+
+// dispatch pushes the ingested events to the matching subscriptions, until the source is closed
+func (s *EventSubscriptions) dispatch(server notifier, source *events.Subscription) {
+	for event := range source.Events() {
+		batch := []events.EventInfoRaw{event}
+		// batch the events which are already buffered
+	drain:
+		for {
+			select {
+			case next, ok := <-source.Events():
+				if !ok {
+					break drain
+				}
+				batch = append(batch, next)
+			default:
+				break drain
+			}
+		}
+		if err := s.notify(server, batch); err != nil {
+			server.Stop()
+			return
+		}
+	}
+	if source.Err() != nil {
+		// the connection didn't keep up, drop it so that the client resubscribes
+		server.Stop()
+	}
+}
+
+func (s *EventSubscriptions) notify(server notifier, batch []events.EventInfoRaw) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for id, subscription := range s.subscriptions {
+		var found []events.EventInfoRaw
+		for _, event := range batch {
+			if event.Cursor.Cmp(subscription.start) >= 0 && matchesFilters(subscription.filters, event.Event) {
+				found = append(found, event)
+			}
+		}
+		if len(found) == 0 {
+			continue
+		}
+		infos, err := eventInfosForEvents(found)
+		if err != nil {
+			return err
+		}
+		notification := EventNotification{Subscription: id, Events: infos}
+		if err := server.Notify(context.Background(), EventNotificationMethod, notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewSubscribeEventsHandler returns a json rpc handler to subscribe to the events of the connection.
+// It requires a server with push notifications.
+func NewSubscribeEventsHandler(subscriptions *EventSubscriptions) jrpc2.Handler {
+	return handler.New(func(ctx context.Context, request SubscribeEventsRequest) (SubscribeEventsResponse, error) {
+		return subscriptions.subscribe(jrpc2.ServerFromContext(ctx), request)
+	})
+}
+
+type unsubscriber interface {
+	unsubscribe(id string) bool
+}
+
+// NewUnsubscribeHandler returns a json rpc handler to cancel a subscription of the connection
+func NewUnsubscribeHandler(subscriptions ...unsubscriber) jrpc2.Handler {
+	return handler.New(func(ctx context.Context, request UnsubscribeRequest) (bool, error) {
+		for _, s := range subscriptions {
+			if s.unsubscribe(request.Subscription) {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
@@ -0,0 +1,140 @@
+package methods
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/channel"
+	"github.com/creachadair/jrpc2/handler"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
+)
+
+func startSubscriptionServer(t *testing.T, subscriptions *EventSubscriptions) (*jrpc2.Client, chan EventNotification) {
+	notifications := make(chan EventNotification, 10)
+	clientChannel, serverChannel := channel.Direct()
+	server := jrpc2.NewServer(handler.Map{
+		"subscribeEvents": NewSubscribeEventsHandler(subscriptions),
+		"unsubscribe":     NewUnsubscribeHandler(subscriptions),
+	}, &jrpc2.ServerOptions{AllowPush: true}).Start(serverChannel)
+	client := jrpc2.NewClient(clientChannel, &jrpc2.ClientOptions{
+		OnNotify: func(request *jrpc2.Request) {
+			require.Equal(t, EventNotificationMethod, request.Method())
+			var notification EventNotification
+			require.NoError(t, request.UnmarshalParams(&notification))
+			notifications <- notification
+		},
+	})
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+		subscriptions.Close()
+	})
+	return client, notifications
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	now := time.Now().UTC()
+	counter := xdr.ScSymbol("COUNTER")
+	counterScVal := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &counter}
+	contractIDs := []xdr.Hash{{0x1}, {0x2}}
+	ledger := func(sequence uint32) xdr.LedgerCloseMeta {
+		var txMeta []xdr.TransactionMeta
+		for _, contractID := range contractIDs {
+			txMeta = append(txMeta, transactionMetaWithEvents(
+				contractEvent(contractID, xdr.ScVec{counterScVal}, counterScVal),
+			))
+		}
+		return ledgerCloseMetaWithEvents(sequence, now.Unix(), txMeta...)
+	}
+	filter := EventFilter{ContractIDs: []string{strkey.MustEncode(strkey.VersionByteContract, contractIDs[1][:])}}
+
+	store := events.NewMemoryStore(interfaces.MakeNoOpDeamon(), "unit-tests", 100)
+	require.NoError(t, store.IngestEvents(ledger(1)))
+	require.NoError(t, store.IngestEvents(ledger(2)))
+	client, notifications := startSubscriptionServer(t, NewEventSubscriptions(store, 2, 100, 10))
+	ctx := context.Background()
+
+	// resuming from a cursor replays the matching events
+	var resumed SubscribeEventsResponse
+	require.NoError(t, client.CallResult(ctx, "subscribeEvents", SubscribeEventsRequest{
+		Cursor:  &events.Cursor{Ledger: 1, Tx: 2},
+		Filters: []EventFilter{filter},
+	}, &resumed))
+	require.Equal(t, uint32(2), resumed.LatestLedger)
+	require.Len(t, resumed.Events, 1)
+	require.Equal(t, events.Cursor{Ledger: 2, Tx: 2}.String(), resumed.Events[0].ID)
+
+	// without a cursor, only new events are pushed
+	var live SubscribeEventsResponse
+	require.NoError(t, client.CallResult(ctx, "subscribeEvents", SubscribeEventsRequest{}, &live))
+	require.Empty(t, live.Events)
+	require.NotEqual(t, resumed.Subscription, live.Subscription)
+
+	var response SubscribeEventsResponse
+	err := client.CallResult(ctx, "subscribeEvents", SubscribeEventsRequest{}, &response)
+	require.ErrorContains(t, err, "maximum 2 subscriptions per connection")
+
+	require.NoError(t, store.IngestEvents(ledger(3)))
+	received := map[string][]EventInfo{}
+	for len(received[resumed.Subscription]) < 1 || len(received[live.Subscription]) < 2 {
+		select {
+		case notification := <-notifications:
+			received[notification.Subscription] = append(received[notification.Subscription], notification.Events...)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for notifications")
+		}
+	}
+	require.Len(t, received[resumed.Subscription], 1)
+	require.Equal(t, events.Cursor{Ledger: 3, Tx: 2}.String(), received[resumed.Subscription][0].ID)
+	require.Len(t, received[live.Subscription], 2)
+	require.Equal(t, events.Cursor{Ledger: 3, Tx: 1}.String(), received[live.Subscription][0].ID)
+
+	// unsubscribed subscriptions aren't notified anymore
+	var unsubscribed bool
+	require.NoError(t, client.CallResult(ctx, "unsubscribe", UnsubscribeRequest{Subscription: live.Subscription}, &unsubscribed))
+	require.True(t, unsubscribed)
+	require.NoError(t, client.CallResult(ctx, "unsubscribe", UnsubscribeRequest{Subscription: live.Subscription}, &unsubscribed))
+	require.False(t, unsubscribed)
+
+	require.NoError(t, store.IngestEvents(ledger(4)))
+	select {
+	case notification := <-notifications:
+		require.Equal(t, resumed.Subscription, notification.Subscription)
+		require.Len(t, notification.Events, 1)
+		require.Equal(t, events.Cursor{Ledger: 4, Tx: 2}.String(), notification.Events[0].ID)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for notifications")
+	}
+}
+
+func TestSubscribeEventsReplayLimit(t *testing.T) {
+	now := time.Now().UTC()
+	counter := xdr.ScSymbol("COUNTER")
+	counterScVal := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &counter}
+	store := events.NewMemoryStore(interfaces.MakeNoOpDeamon(), "unit-tests", 100)
+	var txMeta []xdr.TransactionMeta
+	for i := 0; i < 3; i++ {
+		txMeta = append(txMeta, transactionMetaWithEvents(
+			contractEvent(xdr.Hash{}, xdr.ScVec{counterScVal}, counterScVal),
+		))
+	}
+	require.NoError(t, store.IngestEvents(ledgerCloseMetaWithEvents(1, now.Unix(), txMeta...)))
+	client, _ := startSubscriptionServer(t, NewEventSubscriptions(store, 10, 100, 2))
+
+	var response SubscribeEventsResponse
+	err := client.CallResult(context.Background(), "subscribeEvents", SubscribeEventsRequest{StartLedger: 1}, &response)
+	require.ErrorContains(t, err, "more than 2 events to replay")
+
+	err = client.CallResult(context.Background(), "subscribeEvents", SubscribeEventsRequest{
+		StartLedger: 1,
+		Cursor:      &events.Cursor{Ledger: 1},
+	}, &response)
+	require.ErrorContains(t, err, "startLedger and cursor cannot both be set")
+}
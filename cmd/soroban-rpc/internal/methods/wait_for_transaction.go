@@ -0,0 +1,172 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/transactions"
+)
+
+// TransactionNotificationMethod is the method of the notifications pushed to transaction subscribers
+const TransactionNotificationMethod = "transactionNotification"
+
+type WaitForTransactionRequest struct {
+	Hash string `json:"hash"`
+	// Timeout is the maximum amount of seconds to wait for the transaction, it defaults to the maximum allowed.
+	Timeout uint `json:"timeout,omitempty"`
+}
+
+// SubscribeTransactionResponse is the response for the Soroban-RPC subscribeTransaction() endpoint
+type SubscribeTransactionResponse struct {
+	// Subscription identifies the notification of the subscription, and is used to unsubscribe.
+	Subscription string `json:"subscription"`
+}
+
+// TransactionNotification is pushed to the subscriber once the transaction is ingested or the timeout passes,
+// in which case the status of the transaction is TransactionStatusNotFound.
+type TransactionNotification struct {
+	Subscription string                 `json:"subscription"`
+	Transaction  GetTransactionResponse `json:"transaction"`
+}
+
+type transactionWaiter interface {
+	WaitForTransaction(ctx context.Context, hash xdr.Hash) (transactions.Transaction, bool, transactions.StoreRange)
+}
+
+func (r WaitForTransactionRequest) parse(maxTimeout time.Duration) (xdr.Hash, time.Duration, error) {
+	txHash, err := parseTransactionHash(r.Hash)
+	if err != nil {
+		return xdr.Hash{}, 0, err
+	}
+	timeout := maxTimeout
+	if r.Timeout > 0 {
+		timeout = time.Duration(r.Timeout) * time.Second
+		if timeout > maxTimeout {
+			return xdr.Hash{}, 0, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: fmt.Sprintf("timeout must not exceed %d seconds", int64(maxTimeout.Seconds())),
+			}
+		}
+	}
+	return txHash, timeout, nil
+}
+
+// NewWaitForTransactionHandler returns a json rpc handler which waits for a transaction to be ingested,
+// returning the same response as getTransaction.
+func NewWaitForTransactionHandler(waiter transactionWaiter, maxTimeout time.Duration) jrpc2.Handler {
+	return handler.New(func(ctx context.Context, request WaitForTransactionRequest) (GetTransactionResponse, error) {
+		txHash, timeout, err := request.parse(maxTimeout)
+		if err != nil {
+			return GetTransactionResponse{}, err
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		tx, found, storeRange := waiter.WaitForTransaction(ctx, txHash)
+		return transactionResponse(tx, found, storeRange), nil
+	})
+}
+
+// TransactionSubscriptions holds the transaction subscriptions of a connection.
+// Each subscription is notified once, and then it's removed.
+type TransactionSubscriptions struct {
+	waiter           transactionWaiter
+	maxTimeout       time.Duration
+	maxSubscriptions uint
+
+	lock          sync.Mutex
+	subscriptions map[string]context.CancelFunc
+}
+
+// NewTransactionSubscriptions creates the transaction subscriptions of a new connection
+func NewTransactionSubscriptions(waiter transactionWaiter, maxSubscriptions uint, maxTimeout time.Duration) *TransactionSubscriptions {
+	return &TransactionSubscriptions{
+		waiter:           waiter,
+		maxTimeout:       maxTimeout,
+		maxSubscriptions: maxSubscriptions,
+		subscriptions:    make(map[string]context.CancelFunc),
+	}
+}
+
+// Close cancels all the subscriptions of the connection
+func (s *TransactionSubscriptions) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for id, cancel := range s.subscriptions {
+		cancel()
+		delete(s.subscriptions, id)
+	}
+}
+
+func (s *TransactionSubscriptions) subscribe(server notifier, request WaitForTransactionRequest) (SubscribeTransactionResponse, error) {
+	txHash, timeout, err := request.parse(s.maxTimeout)
+	if err != nil {
+		return SubscribeTransactionResponse{}, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if uint(len(s.subscriptions)) >= s.maxSubscriptions {
+		return SubscribeTransactionResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidRequest,
+			Message: fmt.Sprintf("maximum %d subscriptions per connection", s.maxSubscriptions),
+		}
+	}
+	id, err := newSubscriptionID()
+	if err != nil {
+		return SubscribeTransactionResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	s.subscriptions[id] = cancel
+	go s.wait(ctx, server, id, txHash)
+	return SubscribeTransactionResponse{Subscription: id}, nil
+}
+
+func (s *TransactionSubscriptions) wait(ctx context.Context, server notifier, id string, txHash xdr.Hash) {
+	tx, found, storeRange := s.waiter.WaitForTransaction(ctx, txHash)
+
+	s.lock.Lock()
+	cancel, ok := s.subscriptions[id]
+	delete(s.subscriptions, id)
+	s.lock.Unlock()
+	if !ok {
+		// unsubscribed or closed
+		return
+	}
+	cancel()
+	notification := TransactionNotification{
+		Subscription: id,
+		Transaction:  transactionResponse(tx, found, storeRange),
+	}
+	if err := server.Notify(context.Background(), TransactionNotificationMethod, notification); err != nil {
+		server.Stop()
+	}
+}
+
+func (s *TransactionSubscriptions) unsubscribe(id string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	cancel, ok := s.subscriptions[id]
+	if !ok {
+		return false
+	}
+	cancel()
+	delete(s.subscriptions, id)
+	return true
+}
+
+// NewSubscribeTransactionHandler returns a json rpc handler to be notified once a transaction is ingested.
+// It requires a server with push notifications.
+func NewSubscribeTransactionHandler(subscriptions *TransactionSubscriptions) jrpc2.Handler {
+	return handler.New(func(ctx context.Context, request WaitForTransactionRequest) (SubscribeTransactionResponse, error) {
+		return subscriptions.subscribe(jrpc2.ServerFromContext(ctx), request)
+	})
+}
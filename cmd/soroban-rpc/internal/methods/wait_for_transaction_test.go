@@ -0,0 +1,118 @@
+package methods
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/channel"
+	"github.com/creachadair/jrpc2/handler"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/transactions"
+)
+
+// blockingWaiter finds the transactions which are sent to it before the context is done
+type blockingWaiter struct {
+	found chan xdr.Hash
+}
+
+func (w blockingWaiter) WaitForTransaction(ctx context.Context, hash xdr.Hash) (transactions.Transaction, bool, transactions.StoreRange) {
+	storeRange := transactions.StoreRange{
+		FirstLedger: transactions.LedgerInfo{Sequence: 1},
+		LastLedger:  transactions.LedgerInfo{Sequence: 10},
+	}
+	select {
+	case found := <-w.found:
+		return transactions.Transaction{
+			Hash:       found,
+			Successful: true,
+			Ledger:     transactions.LedgerInfo{Sequence: 10, CloseTime: 125},
+		}, true, storeRange
+	case <-ctx.Done():
+		return transactions.Transaction{}, false, storeRange
+	}
+}
+
+func TestWaitForTransaction(t *testing.T) {
+	waiter := blockingWaiter{found: make(chan xdr.Hash, 1)}
+	waitForTransaction := NewWaitForTransactionHandler(waiter, time.Second)
+	hash := "c4b8e1d2bc7ddc0d2c6dd1e3b38d4e53c5bb4e5be52e1e3c5edab5acc6cd3d4f"
+	call := func(request string) (GetTransactionResponse, error) {
+		requests, err := jrpc2.ParseRequests([]byte(`{"jsonrpc":"2.0","id":1,"method":"waitForTransaction","params":` + request + `}`))
+		require.NoError(t, err)
+		result, err := waitForTransaction(context.Background(), requests[0].ToRequest())
+		if err != nil {
+			return GetTransactionResponse{}, err
+		}
+		return result.(GetTransactionResponse), nil
+	}
+
+	_, err := call(`{"hash":"` + hash + `","timeout":2}`)
+	require.ErrorContains(t, err, "timeout must not exceed 1 seconds")
+	_, err = call(`{"hash":"abc"}`)
+	require.ErrorContains(t, err, "unexpected hash length")
+
+	response, err := call(`{"hash":"` + hash + `","timeout":1}`)
+	require.NoError(t, err)
+	require.Equal(t, TransactionStatusNotFound, response.Status)
+	require.Equal(t, uint32(10), response.LatestLedger)
+
+	waiter.found <- xdr.Hash{}
+	response, err = call(`{"hash":"` + hash + `"}`)
+	require.NoError(t, err)
+	require.Equal(t, TransactionStatusSuccess, response.Status)
+	require.Equal(t, uint32(10), response.Ledger)
+}
+
+func TestSubscribeTransaction(t *testing.T) {
+	waiter := blockingWaiter{found: make(chan xdr.Hash)}
+	subscriptions := NewTransactionSubscriptions(waiter, 2, time.Minute)
+	notifications := make(chan TransactionNotification, 10)
+	clientChannel, serverChannel := channel.Direct()
+	server := jrpc2.NewServer(handler.Map{
+		"subscribeTransaction": NewSubscribeTransactionHandler(subscriptions),
+		"unsubscribe":          NewUnsubscribeHandler(subscriptions),
+	}, &jrpc2.ServerOptions{AllowPush: true}).Start(serverChannel)
+	client := jrpc2.NewClient(clientChannel, &jrpc2.ClientOptions{
+		OnNotify: func(request *jrpc2.Request) {
+			require.Equal(t, TransactionNotificationMethod, request.Method())
+			var notification TransactionNotification
+			require.NoError(t, request.UnmarshalParams(&notification))
+			notifications <- notification
+		},
+	})
+	defer func() {
+		client.Close()
+		server.Stop()
+		subscriptions.Close()
+	}()
+	ctx := context.Background()
+	request := WaitForTransactionRequest{Hash: "c4b8e1d2bc7ddc0d2c6dd1e3b38d4e53c5bb4e5be52e1e3c5edab5acc6cd3d4f"}
+
+	var first, second SubscribeTransactionResponse
+	require.NoError(t, client.CallResult(ctx, "subscribeTransaction", request, &first))
+	require.NoError(t, client.CallResult(ctx, "subscribeTransaction", request, &second))
+	err := client.CallResult(ctx, "subscribeTransaction", request, &SubscribeTransactionResponse{})
+	require.ErrorContains(t, err, "maximum 2 subscriptions per connection")
+
+	// unsubscribed subscriptions aren't notified
+	var unsubscribed bool
+	require.NoError(t, client.CallResult(ctx, "unsubscribe", UnsubscribeRequest{Subscription: first.Subscription}, &unsubscribed))
+	require.True(t, unsubscribed)
+
+	waiter.found <- xdr.Hash{}
+	select {
+	case notification := <-notifications:
+		require.Equal(t, second.Subscription, notification.Subscription)
+		require.Equal(t, TransactionStatusSuccess, notification.Transaction.Status)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for notifications")
+	}
+
+	// notified subscriptions are removed
+	require.NoError(t, client.CallResult(ctx, "unsubscribe", UnsubscribeRequest{Subscription: second.Subscription}, &unsubscribed))
+	require.False(t, unsubscribed)
+}
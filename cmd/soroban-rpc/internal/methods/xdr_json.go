@@ -0,0 +1,160 @@
+package methods
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/xdrjson"
+)
+
+const (
+	// FormatXDR renders results as base64 XDR only (the default)
+	FormatXDR = "xdr"
+	// FormatJSON additionally renders results as decoded JSON
+	FormatJSON = "json"
+)
+
+func validateFormat(format string) error {
+	switch format {
+	case "", FormatXDR, FormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("format must be one of %q or %q", FormatXDR, FormatJSON)
+	}
+}
+
+// DiagnosticEventJSON is the decoded JSON rendering of a DiagnosticEvent
+type DiagnosticEventJSON struct {
+	InSuccessfulContractCall bool                `json:"inSuccessfulContractCall"`
+	Type                     string              `json:"type"`
+	ContractID               string              `json:"contractId,omitempty"`
+	Topics                   []xdrjson.ScValJSON `json:"topics"`
+	Data                     xdrjson.ScValJSON   `json:"data"`
+}
+
+// AuthorizationEntryJSON is the decoded JSON rendering of a SorobanAuthorizationEntry
+type AuthorizationEntryJSON struct {
+	Credentials    AuthorizationCredentialsJSON `json:"credentials"`
+	RootInvocation AuthorizedInvocationJSON     `json:"rootInvocation"`
+}
+
+// AuthorizationCredentialsJSON is the decoded JSON rendering of SorobanCredentials.
+// Only address credentials have the fields other than Type.
+type AuthorizationCredentialsJSON struct {
+	Type                      string             `json:"type"`
+	Address                   string             `json:"address,omitempty"`
+	Nonce                     string             `json:"nonce,omitempty"`
+	SignatureExpirationLedger uint32             `json:"signatureExpirationLedger,omitempty"`
+	Signature                 *xdrjson.ScValJSON `json:"signature,omitempty"`
+}
+
+// AuthorizedInvocationJSON is the decoded JSON rendering of a SorobanAuthorizedInvocation
+type AuthorizedInvocationJSON struct {
+	Function       AuthorizedFunctionJSON     `json:"function"`
+	SubInvocations []AuthorizedInvocationJSON `json:"subInvocations"`
+}
+
+// AuthorizedFunctionJSON is the decoded JSON rendering of a SorobanAuthorizedFunction.
+// Contract function invocations have a contract address, function name and arguments,
+// while contract creations have a contract id preimage and executable.
+type AuthorizedFunctionJSON struct {
+	Type               string                          `json:"type"`
+	ContractAddress    string                          `json:"contractAddress,omitempty"`
+	FunctionName       string                          `json:"functionName,omitempty"`
+	Args               []xdrjson.ScValJSON             `json:"args,omitempty"`
+	ContractIDPreimage *ContractIDPreimageJSON         `json:"contractIdPreimage,omitempty"`
+	Executable         *xdrjson.ContractExecutableJSON `json:"executable,omitempty"`
+}
+
+// ContractIDPreimageJSON is the decoded JSON rendering of a ContractIdPreimage
+type ContractIDPreimageJSON struct {
+	Type    string `json:"type"`
+	Address string `json:"address,omitempty"`
+	Salt    string `json:"salt,omitempty"`
+	Asset   string `json:"asset,omitempty"`
+}
+
+func diagnosticEventToJSON(event xdr.DiagnosticEvent) (DiagnosticEventJSON, error) {
+	result := DiagnosticEventJSON{
+		InSuccessfulContractCall: event.InSuccessfulContractCall,
+		Type:                     eventTypeFromXDR[event.Event.Type],
+	}
+	if event.Event.ContractId != nil {
+		contractID, err := strkey.Encode(strkey.VersionByteContract, event.Event.ContractId[:])
+		if err != nil {
+			return DiagnosticEventJSON{}, err
+		}
+		result.ContractID = contractID
+	}
+	body, ok := event.Event.Body.GetV0()
+	if !ok {
+		return DiagnosticEventJSON{}, fmt.Errorf("unsupported event body version (%d)", event.Event.Body.V)
+	}
+	result.Topics = xdrjson.ScValsToJSON(body.Topics)
+	result.Data = xdrjson.ScValToJSON(body.Data)
+	return result, nil
+}
+
+func authorizationEntryToJSON(entry xdr.SorobanAuthorizationEntry) AuthorizationEntryJSON {
+	credentials := AuthorizationCredentialsJSON{Type: "sourceAccount"}
+	if address, ok := entry.Credentials.GetAddress(); ok {
+		signature := xdrjson.ScValToJSON(address.Signature)
+		credentials = AuthorizationCredentialsJSON{
+			Type:                      "address",
+			Address:                   xdrjson.ScAddressToJSON(address.Address),
+			Nonce:                     strconv.FormatInt(int64(address.Nonce), 10),
+			SignatureExpirationLedger: uint32(address.SignatureExpirationLedger),
+			Signature:                 &signature,
+		}
+	}
+	return AuthorizationEntryJSON{
+		Credentials:    credentials,
+		RootInvocation: authorizedInvocationToJSON(entry.RootInvocation),
+	}
+}
+
+func authorizedInvocationToJSON(invocation xdr.SorobanAuthorizedInvocation) AuthorizedInvocationJSON {
+	result := AuthorizedInvocationJSON{
+		SubInvocations: make([]AuthorizedInvocationJSON, 0, len(invocation.SubInvocations)),
+	}
+	switch invocation.Function.Type {
+	case xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn:
+		fn := invocation.Function.MustContractFn()
+		result.Function = AuthorizedFunctionJSON{
+			Type:            "contractFn",
+			ContractAddress: xdrjson.ScAddressToJSON(fn.ContractAddress),
+			FunctionName:    string(fn.FunctionName),
+			Args:            xdrjson.ScValsToJSON(fn.Args),
+		}
+	case xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeCreateContractHostFn:
+		fn := invocation.Function.MustCreateContractHostFn()
+		executable := xdrjson.ContractExecutableToJSON(fn.Executable)
+		result.Function = AuthorizedFunctionJSON{
+			Type:               "createContractHostFn",
+			ContractIDPreimage: contractIDPreimageToJSON(fn.ContractIdPreimage),
+			Executable:         &executable,
+		}
+	}
+	for _, subInvocation := range invocation.SubInvocations {
+		result.SubInvocations = append(result.SubInvocations, authorizedInvocationToJSON(subInvocation))
+	}
+	return result
+}
+
+func contractIDPreimageToJSON(preimage xdr.ContractIdPreimage) *ContractIDPreimageJSON {
+	if fromAddress, ok := preimage.GetFromAddress(); ok {
+		return &ContractIDPreimageJSON{
+			Type:    "address",
+			Address: xdrjson.ScAddressToJSON(fromAddress.Address),
+			Salt:    hex.EncodeToString(fromAddress.Salt[:]),
+		}
+	}
+	return &ContractIDPreimageJSON{
+		Type:  "asset",
+		Asset: preimage.MustFromAsset().StringCanonical(),
+	}
+}
@@ -0,0 +1,75 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnosticEventToJSON(t *testing.T) {
+	contractID := xdr.Hash{0x1, 0x2}
+	sym := xdr.ScSymbol("transfer")
+	amount := xdr.Int128Parts{Hi: 0, Lo: 100}
+	event := xdr.DiagnosticEvent{
+		InSuccessfulContractCall: true,
+		Event: xdr.ContractEvent{
+			ContractId: &contractID,
+			Type:       xdr.ContractEventTypeContract,
+			Body: xdr.ContractEventBody{
+				V: 0,
+				V0: &xdr.ContractEventV0{
+					Topics: []xdr.ScVal{{Type: xdr.ScValTypeScvSymbol, Sym: &sym}},
+					Data:   xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &amount},
+				},
+			},
+		},
+	}
+	decoded, err := diagnosticEventToJSON(event)
+	require.NoError(t, err)
+	assert.True(t, decoded.InSuccessfulContractCall)
+	assert.Equal(t, EventTypeContract, decoded.Type)
+	assert.Equal(t, strkey.MustEncode(strkey.VersionByteContract, contractID[:]), decoded.ContractID)
+	require.Len(t, decoded.Topics, 1)
+	assert.Equal(t, "symbol", decoded.Topics[0].Type)
+	assert.Equal(t, "transfer", decoded.Topics[0].Value)
+	assert.Equal(t, "i128", decoded.Data.Type)
+	assert.Equal(t, "100", decoded.Data.Value)
+}
+
+func TestAuthorizationEntryToJSON(t *testing.T) {
+	account := keypair.MustRandom().Address()
+	contractID := xdr.Hash{0x1, 0x2}
+	entry := xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{
+			Type: xdr.SorobanCredentialsTypeSorobanCredentialsAddress,
+			Address: &xdr.SorobanAddressCredentials{
+				Address:                   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: xdr.MustAddressPtr(account)},
+				Nonce:                     -42,
+				SignatureExpirationLedger: 100,
+				Signature:                 xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+			},
+		},
+		RootInvocation: xdr.SorobanAuthorizedInvocation{
+			Function: xdr.SorobanAuthorizedFunction{
+				Type: xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+				ContractFn: &xdr.InvokeContractArgs{
+					ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+					FunctionName:    "transfer",
+				},
+			},
+		},
+	}
+	decoded := authorizationEntryToJSON(entry)
+	assert.Equal(t, "address", decoded.Credentials.Type)
+	assert.Equal(t, account, decoded.Credentials.Address)
+	assert.Equal(t, "-42", decoded.Credentials.Nonce)
+	assert.Equal(t, uint32(100), decoded.Credentials.SignatureExpirationLedger)
+	assert.Equal(t, "contractFn", decoded.RootInvocation.Function.Type)
+	assert.Equal(t, strkey.MustEncode(strkey.VersionByteContract, contractID[:]), decoded.RootInvocation.Function.ContractAddress)
+	assert.Equal(t, "transfer", decoded.RootInvocation.Function.FunctionName)
+	assert.Empty(t, decoded.RootInvocation.SubInvocations)
+}
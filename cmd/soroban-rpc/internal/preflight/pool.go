@@ -147,15 +147,17 @@ func (pwp *PreflightWorkerPool) GetPreflight(ctx context.Context, params Preflig
 		LedgerEntryReadTx: params.LedgerEntryReadTx,
 	}
 	preflightParams := PreflightParameters{
-		Logger:            pwp.logger,
-		SourceAccount:     params.SourceAccount,
-		OpBody:            params.OperationBody,
-		NetworkPassphrase: pwp.networkPassphrase,
-		LedgerEntryReadTx: &wrappedTx,
-		BucketListSize:    params.BucketListSize,
-		Footprint:         params.Footprint,
-		ResourceConfig:    params.ResourceConfig,
-		EnableDebug:       pwp.enableDebug,
+		Logger:              pwp.logger,
+		SourceAccount:       params.SourceAccount,
+		OpBody:              params.OperationBody,
+		NetworkPassphrase:   pwp.networkPassphrase,
+		LedgerEntryReadTx:   &wrappedTx,
+		BucketListSize:      params.BucketListSize,
+		Footprint:           params.Footprint,
+		ResourceConfig:      params.ResourceConfig,
+		EnableDebug:         pwp.enableDebug,
+		RecordLedgerChanges: params.RecordLedgerChanges,
+		Timestamp:           params.Timestamp,
 	}
 	resultC := make(chan workerResult)
 	select {
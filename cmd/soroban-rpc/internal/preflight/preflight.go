@@ -33,6 +33,8 @@ import "C"
 type snapshotSourceHandle struct {
 	readTx db.LedgerEntryReadTx
 	logger *log.Entry
+	// err is the first error found reading ledger entries, which fails the preflight
+	err error
 }
 
 const (
@@ -44,7 +46,7 @@ const (
 //
 //export SnapshotSourceGet
 func SnapshotSourceGet(handle C.uintptr_t, cLedgerKey C.xdr_t) C.xdr_t {
-	h := cgo.Handle(handle).Value().(snapshotSourceHandle)
+	h := cgo.Handle(handle).Value().(*snapshotSourceHandle)
 	ledgerKeyXDR := GoXDR(cLedgerKey)
 	var ledgerKey xdr.LedgerKey
 	if err := xdr.SafeUnmarshal(ledgerKeyXDR, &ledgerKey); err != nil {
@@ -54,6 +56,11 @@ func SnapshotSourceGet(handle C.uintptr_t, cLedgerKey C.xdr_t) C.xdr_t {
 	present, entry, _, err := db.GetLedgerEntry(h.readTx, ledgerKey)
 	if err != nil {
 		h.logger.WithError(err).Error("SnapshotSourceGet(): GetLedgerEntry() failed")
+		// The Rust side can't tell a failed read from a missing entry,
+		// so we record the error to fail the preflight once it returns
+		if h.err == nil {
+			h.err = err
+		}
 		return C.xdr_t{}
 	}
 	if !present {
@@ -92,6 +99,10 @@ type PreflightGetterParameters struct {
 	OperationBody     xdr.OperationBody
 	Footprint         xdr.LedgerFootprint
 	ResourceConfig    ResourceConfig
+	// RecordLedgerChanges reports the ledger entries modified by InvokeHostFunction operations
+	RecordLedgerChanges bool
+	// Timestamp is the ledger close time of the simulation, the current time if 0
+	Timestamp uint64
 }
 
 type PreflightParameters struct {
@@ -104,6 +115,10 @@ type PreflightParameters struct {
 	BucketListSize    uint64
 	ResourceConfig    ResourceConfig
 	EnableDebug       bool
+	// RecordLedgerChanges reports the ledger entries modified by InvokeHostFunction operations
+	RecordLedgerChanges bool
+	// Timestamp is the ledger close time of the simulation, the current time if 0
+	Timestamp uint64
 }
 
 type Preflight struct {
@@ -117,6 +132,7 @@ type Preflight struct {
 	MemoryBytes               uint64
 	PreRestoreTransactionData []byte // SorobanTransactionData XDR
 	PreRestoreMinFee          int64
+	LedgerChanges             [][]byte // LedgerEntryChange XDR, only set when recording ledger changes
 }
 
 func CXDR(xdr []byte) C.xdr_t {
@@ -161,7 +177,8 @@ func getFootprintTtlPreflight(params PreflightParameters) (Preflight, error) {
 		return Preflight{}, err
 	}
 	footprintCXDR := CXDR(footprintXDR)
-	handle := cgo.NewHandle(snapshotSourceHandle{params.LedgerEntryReadTx, params.Logger})
+	snapshotSource := &snapshotSourceHandle{readTx: params.LedgerEntryReadTx, logger: params.Logger}
+	handle := cgo.NewHandle(snapshotSource)
 	defer handle.Delete()
 
 	simulationLedgerSeq, err := getSimulationLedgerSeq(params.LedgerEntryReadTx)
@@ -180,7 +197,11 @@ func getFootprintTtlPreflight(params PreflightParameters) (Preflight, error) {
 	FreeGoXDR(opBodyCXDR)
 	FreeGoXDR(footprintCXDR)
 
-	return GoPreflight(res), nil
+	preflight := GoPreflight(res, false)
+	if snapshotSource.err != nil {
+		return Preflight{}, fmt.Errorf("could not read ledger entries: %w", snapshotSource.err)
+	}
+	return preflight, nil
 }
 
 func getSimulationLedgerSeq(readTx db.LedgerEntryReadTx) (uint32, error) {
@@ -225,12 +246,17 @@ func getInvokeHostFunctionPreflight(params PreflightParameters) (Preflight, erro
 		return Preflight{}, err
 	}
 
+	timestamp := params.Timestamp
+	if timestamp == 0 {
+		timestamp = uint64(time.Now().Unix())
+	}
+
 	stateArchival := stateArchivalConfig.Data.MustConfigSetting().MustStateArchivalSettings()
 	li := C.ledger_info_t{
 		network_passphrase: C.CString(params.NetworkPassphrase),
 		sequence_number:    C.uint32_t(simulationLedgerSeq),
 		protocol_version:   20,
-		timestamp:          C.uint64_t(time.Now().Unix()),
+		timestamp:          C.uint64_t(timestamp),
 		// Current base reserve is 0.5XLM (in stroops)
 		base_reserve:             5_000_000,
 		min_temp_entry_ttl:       C.uint(stateArchival.MinTemporaryTtl),
@@ -238,7 +264,8 @@ func getInvokeHostFunctionPreflight(params PreflightParameters) (Preflight, erro
 		max_entry_ttl:            C.uint(stateArchival.MaxEntryTtl),
 	}
 
-	handle := cgo.NewHandle(snapshotSourceHandle{params.LedgerEntryReadTx, params.Logger})
+	snapshotSource := &snapshotSourceHandle{readTx: params.LedgerEntryReadTx, logger: params.Logger}
+	handle := cgo.NewHandle(snapshotSource)
 	defer handle.Delete()
 	resourceConfig := C.resource_config_t{
 		instruction_leeway: C.uint64_t(params.ResourceConfig.InstructionLeeway),
@@ -251,14 +278,19 @@ func getInvokeHostFunctionPreflight(params PreflightParameters) (Preflight, erro
 		li,
 		resourceConfig,
 		C.bool(params.EnableDebug),
+		C.bool(params.RecordLedgerChanges),
 	)
 	FreeGoXDR(invokeHostFunctionCXDR)
 	FreeGoXDR(sourceAccountCXDR)
 
-	return GoPreflight(res), nil
+	preflight := GoPreflight(res, params.RecordLedgerChanges)
+	if snapshotSource.err != nil {
+		return Preflight{}, fmt.Errorf("could not read ledger entries: %w", snapshotSource.err)
+	}
+	return preflight, nil
 }
 
-func GoPreflight(result *C.preflight_result_t) Preflight {
+func GoPreflight(result *C.preflight_result_t, withLedgerChanges bool) Preflight {
 	defer C.free_preflight_result(result)
 
 	preflight := Preflight{
@@ -273,5 +305,8 @@ func GoPreflight(result *C.preflight_result_t) Preflight {
 		PreRestoreTransactionData: GoXDR(result.pre_restore_transaction_data),
 		PreRestoreMinFee:          int64(result.pre_restore_min_fee),
 	}
+	if withLedgerChanges {
+		preflight.LedgerChanges = GoXDRVector(result.ledger_changes)
+	}
 	return preflight
 }
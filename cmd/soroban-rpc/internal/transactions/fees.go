@@ -0,0 +1,178 @@
+package transactions
+
+import (
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+)
+
+// ledgerFees holds the inclusion fees (per operation) paid by the transactions of a ledger
+type ledgerFees struct {
+	classic []uint64
+	soroban []uint64
+}
+
+// FeeDistribution summarizes the inclusion fees (per operation) over a window of ledgers
+type FeeDistribution struct {
+	Max              uint64
+	Min              uint64
+	Mode             uint64
+	P10              uint64
+	P20              uint64
+	P30              uint64
+	P40              uint64
+	P50              uint64
+	P60              uint64
+	P70              uint64
+	P80              uint64
+	P90              uint64
+	P95              uint64
+	P99              uint64
+	TransactionCount uint32
+	LedgerCount      uint32
+}
+
+func computeFeeDistribution(fees []uint64, ledgerCount uint32) FeeDistribution {
+	if len(fees) == 0 {
+		return FeeDistribution{LedgerCount: ledgerCount}
+	}
+	sorted := make([]uint64, len(fees))
+	copy(sorted, fees)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	// the mode is the most frequent fee, the lowest one among ties
+	mode := sorted[0]
+	for i, count, maxCount := 0, 0, 0; i < len(sorted); i++ {
+		if i > 0 && sorted[i] == sorted[i-1] {
+			count++
+		} else {
+			count = 1
+		}
+		if count > maxCount {
+			mode = sorted[i]
+			maxCount = count
+		}
+	}
+
+	// nearest-rank percentiles
+	percentile := func(p int) uint64 {
+		rank := (p*len(sorted) + 99) / 100
+		if rank < 1 {
+			rank = 1
+		}
+		return sorted[rank-1]
+	}
+	return FeeDistribution{
+		Max:              sorted[len(sorted)-1],
+		Min:              sorted[0],
+		Mode:             mode,
+		P10:              percentile(10),
+		P20:              percentile(20),
+		P30:              percentile(30),
+		P40:              percentile(40),
+		P50:              percentile(50),
+		P60:              percentile(60),
+		P70:              percentile(70),
+		P80:              percentile(80),
+		P90:              percentile(90),
+		P95:              percentile(95),
+		P99:              percentile(99),
+		TransactionCount: uint32(len(sorted)),
+		LedgerCount:      ledgerCount,
+	}
+}
+
+// sorobanBaseFee returns the discounted base fee applied to the Soroban phase of the transaction set, if any.
+func sorobanBaseFee(ledgerCloseMeta xdr.LedgerCloseMeta) (uint64, bool) {
+	v1, ok := ledgerCloseMeta.GetV1()
+	if !ok || v1.TxSet.V1TxSet == nil || len(v1.TxSet.V1TxSet.Phases) < 2 {
+		return 0, false
+	}
+	components, ok := v1.TxSet.V1TxSet.Phases[1].GetV0Components()
+	if !ok {
+		return 0, false
+	}
+	for _, component := range components {
+		if component.TxsMaybeDiscountedFee != nil && component.TxsMaybeDiscountedFee.BaseFee != nil {
+			return uint64(*component.TxsMaybeDiscountedFee.BaseFee), true
+		}
+	}
+	return 0, false
+}
+
+func getSorobanData(envelope xdr.TransactionEnvelope) (xdr.SorobanTransactionData, bool) {
+	var tx xdr.Transaction
+	switch envelope.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		tx = envelope.V1.Tx
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		tx = envelope.FeeBump.Tx.InnerTx.V1.Tx
+	default:
+		return xdr.SorobanTransactionData{}, false
+	}
+	return tx.Ext.GetSorobanData()
+}
+
+// inclusionFee returns the inclusion fee per operation paid by a transaction, and whether it is a Soroban transaction.
+// Classic fees are taken from the charged fee. Soroban transactions are charged their resource fee on top,
+// so their inclusion fee is the bid, discounted to the base fee of the Soroban phase if any.
+func inclusionFee(tx ingest.LedgerTransaction, sorobanBaseFee uint64, hasSorobanBaseFee bool) (uint64, bool) {
+	ops := uint64(len(tx.Envelope.Operations()))
+	if tx.Envelope.IsFeeBump() {
+		// the fee bump counts as an operation
+		ops++
+	}
+	if ops == 0 {
+		// malformed transactions without operations are charged as if they had one
+		ops = 1
+	}
+	sorobanData, isSoroban := getSorobanData(tx.Envelope)
+	if !isSoroban {
+		return uint64(tx.Result.Result.FeeCharged) / ops, false
+	}
+
+	fee := int64(tx.Envelope.Fee())
+	if tx.Envelope.IsFeeBump() {
+		fee = tx.Envelope.FeeBumpFee()
+	}
+	bid := uint64(max(fee-int64(sorobanData.ResourceFee), 0)) / ops
+	if hasSorobanBaseFee {
+		return min(bid, sorobanBaseFee), true
+	}
+	return bid, true
+}
+
+// FeeStats holds the inclusion fee distributions of the latest ledgers in the store
+type FeeStats struct {
+	SorobanInclusionFee FeeDistribution
+	InclusionFee        FeeDistribution
+	LatestLedger        uint32
+}
+
+// GetFeeStats returns the inclusion fee distributions of classic and Soroban transactions
+// over the latest ledgerWindow ledgers in the store.
+func (m *MemoryStore) GetFeeStats(ledgerWindow uint32) FeeStats {
+	startTime := time.Now()
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	length := m.feesByLedger.Len()
+	first := uint32(0)
+	if ledgerWindow < length {
+		first = length - ledgerWindow
+	}
+	var classic, soroban []uint64
+	var stats FeeStats
+	for i := first; i < length; i++ {
+		bucket := m.feesByLedger.Get(i)
+		classic = append(classic, bucket.BucketContent.classic...)
+		soroban = append(soroban, bucket.BucketContent.soroban...)
+		stats.LatestLedger = bucket.LedgerSeq
+	}
+	stats.InclusionFee = computeFeeDistribution(classic, length-first)
+	stats.SorobanInclusionFee = computeFeeDistribution(soroban, length-first)
+	m.transactionDurationMetric.With(prometheus.Labels{"operation": "fee_stats"}).Observe(time.Since(startTime).Seconds())
+	return stats
+}
@@ -0,0 +1,136 @@
+package transactions
+
+import (
+	"testing"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestComputeFeeDistribution(t *testing.T) {
+	require.Equal(t, FeeDistribution{LedgerCount: 2}, computeFeeDistribution(nil, 2))
+
+	fees := []uint64{300, 100, 200, 100, 1000, 200, 100, 400, 500, 600}
+	require.Equal(t, FeeDistribution{
+		Max:              1000,
+		Min:              100,
+		Mode:             100,
+		P10:              100,
+		P20:              100,
+		P30:              100,
+		P40:              200,
+		P50:              200,
+		P60:              300,
+		P70:              400,
+		P80:              500,
+		P90:              600,
+		P95:              1000,
+		P99:              1000,
+		TransactionCount: 10,
+		LedgerCount:      3,
+	}, computeFeeDistribution(fees, 3))
+
+	// ties are broken with the lowest fee
+	require.Equal(t, uint64(100), computeFeeDistribution([]uint64{200, 100, 200, 100}, 1).Mode)
+}
+
+// feeStatsTxMeta returns a ledger with a classic transaction charged classicFee and
+// a Soroban transaction bidding sorobanFee on top of its resource fee
+func feeStatsTxMeta(ledgerSequence uint32, classicFee int64, sorobanFee uint32, sorobanBaseFee *xdr.Int64) xdr.LedgerCloseMeta {
+	classicEnvelope := txEnvelope(ledgerSequence, false)
+	sorobanEnvelope, err := xdr.NewTransactionEnvelope(xdr.EnvelopeTypeEnvelopeTypeTx, xdr.TransactionV1Envelope{
+		Tx: xdr.Transaction{
+			Fee:           xdr.Uint32(sorobanFee + 1000),
+			SeqNum:        xdr.SequenceNumber(ledgerSequence + 91),
+			SourceAccount: xdr.MustMuxedAddress("MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK"),
+			Operations: []xdr.Operation{
+				{
+					Body: xdr.OperationBody{
+						Type:           xdr.OperationTypeBumpSequence,
+						BumpSequenceOp: &xdr.BumpSequenceOp{},
+					},
+				},
+			},
+			Ext: xdr.TransactionExt{
+				V:           1,
+				SorobanData: &xdr.SorobanTransactionData{ResourceFee: 1000},
+			},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	sorobanHash, err := network.HashTransactionInEnvelope(sorobanEnvelope, "passphrase")
+	if err != nil {
+		panic(err)
+	}
+
+	meta := txMeta(ledgerSequence, false)
+	meta.V1.TxProcessing[0].Result.Result.FeeCharged = xdr.Int64(classicFee)
+	meta.V1.TxProcessing = append(meta.V1.TxProcessing, xdr.TransactionResultMeta{
+		TxApplyProcessing: xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{}},
+		Result: xdr.TransactionResultPair{
+			TransactionHash: sorobanHash,
+			Result: xdr.TransactionResult{
+				FeeCharged: 1000,
+				Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &[]xdr.OperationResult{}},
+			},
+		},
+	})
+	sorobanComponents := []xdr.TxSetComponent{
+		{
+			Type: xdr.TxSetComponentTypeTxsetCompTxsMaybeDiscountedFee,
+			TxsMaybeDiscountedFee: &xdr.TxSetComponentTxsMaybeDiscountedFee{
+				BaseFee: sorobanBaseFee,
+				Txs:     []xdr.TransactionEnvelope{sorobanEnvelope},
+			},
+		},
+	}
+	classicComponents := []xdr.TxSetComponent{
+		{
+			Type: xdr.TxSetComponentTypeTxsetCompTxsMaybeDiscountedFee,
+			TxsMaybeDiscountedFee: &xdr.TxSetComponentTxsMaybeDiscountedFee{
+				Txs: []xdr.TransactionEnvelope{classicEnvelope},
+			},
+		},
+	}
+	meta.V1.TxSet.V1TxSet.Phases = []xdr.TransactionPhase{
+		{V: 0, V0Components: &classicComponents},
+		{V: 0, V0Components: &sorobanComponents},
+	}
+	return meta
+}
+
+func TestGetFeeStats(t *testing.T) {
+	store := NewMemoryStore(interfaces.MakeNoOpDeamon(), "passphrase", 3)
+	require.Equal(t, FeeStats{}, store.GetFeeStats(10))
+
+	baseFee := xdr.Int64(150)
+	require.NoError(t, store.IngestTransactions(feeStatsTxMeta(1, 100, 300, nil)))
+	require.NoError(t, store.IngestTransactions(feeStatsTxMeta(2, 200, 200, nil)))
+	// the Soroban bid is discounted to the base fee of the phase
+	require.NoError(t, store.IngestTransactions(feeStatsTxMeta(3, 300, 400, &baseFee)))
+
+	stats := store.GetFeeStats(2)
+	require.Equal(t, uint32(3), stats.LatestLedger)
+	require.Equal(t, uint32(2), stats.InclusionFee.LedgerCount)
+	require.Equal(t, uint32(2), stats.InclusionFee.TransactionCount)
+	require.Equal(t, uint64(200), stats.InclusionFee.Min)
+	require.Equal(t, uint64(300), stats.InclusionFee.Max)
+	require.Equal(t, uint32(2), stats.SorobanInclusionFee.TransactionCount)
+	require.Equal(t, uint64(150), stats.SorobanInclusionFee.Min)
+	require.Equal(t, uint64(200), stats.SorobanInclusionFee.Max)
+
+	// the window is capped by the ledgers in the store, and evicted ledgers are dropped
+	require.NoError(t, store.IngestTransactions(feeStatsTxMeta(4, 400, 500, nil)))
+	stats = store.GetFeeStats(10)
+	require.Equal(t, uint32(4), stats.LatestLedger)
+	require.Equal(t, uint32(3), stats.InclusionFee.LedgerCount)
+	require.Equal(t, uint64(200), stats.InclusionFee.Min)
+	require.Equal(t, uint64(400), stats.InclusionFee.Max)
+	require.Equal(t, uint64(150), stats.SorobanInclusionFee.Min)
+	require.Equal(t, uint64(500), stats.SorobanInclusionFee.Max)
+}
@@ -14,9 +14,10 @@ import (
 
 type transaction struct {
 	bucket           *ledgerbucketwindow.LedgerBucket[[]xdr.Hash]
-	result           []byte // encoded XDR of xdr.TransactionResult
-	meta             []byte // encoded XDR of xdr.TransactionMeta
-	envelope         []byte // encoded XDR of xdr.TransactionEnvelope
+	hash             xdr.Hash // hash of the envelope, which is the outer hash for fee bump transactions
+	result           []byte   // encoded XDR of xdr.TransactionResult
+	meta             []byte   // encoded XDR of xdr.TransactionMeta
+	envelope         []byte   // encoded XDR of xdr.TransactionEnvelope
 	feeBump          bool
 	successful       bool
 	applicationOrder int32
@@ -31,7 +32,9 @@ type MemoryStore struct {
 	networkPassphrase         string
 	lock                      sync.RWMutex
 	transactions              map[xdr.Hash]transaction
+	waiters                   map[xdr.Hash]*waiter
 	transactionsByLedger      *ledgerbucketwindow.LedgerBucketWindow[[]xdr.Hash]
+	feesByLedger              *ledgerbucketwindow.LedgerBucketWindow[ledgerFees]
 	transactionDurationMetric *prometheus.SummaryVec
 	transactionCountMetric    prometheus.Summary
 }
@@ -64,7 +67,9 @@ func NewMemoryStore(daemon interfaces.Daemon, networkPassphrase string, retentio
 	return &MemoryStore{
 		networkPassphrase:         networkPassphrase,
 		transactions:              make(map[xdr.Hash]transaction),
+		waiters:                   make(map[xdr.Hash]*waiter),
 		transactionsByLedger:      window,
+		feesByLedger:              ledgerbucketwindow.NewLedgerBucketWindow[ledgerFees](retentionWindow),
 		transactionDurationMetric: transactionDurationMetric,
 		transactionCountMetric:    transactionCountMetric,
 	}
@@ -85,6 +90,8 @@ func (m *MemoryStore) IngestTransactions(ledgerCloseMeta xdr.LedgerCloseMeta) er
 	hashes := make([]xdr.Hash, 0, txCount)
 	hashMap := map[xdr.Hash]transaction{}
 	var bucket ledgerbucketwindow.LedgerBucket[[]xdr.Hash]
+	var fees ledgerFees
+	baseFee, hasBaseFee := sorobanBaseFee(ledgerCloseMeta)
 
 	for i := 0; i < txCount; i++ {
 		tx, err := reader.Read()
@@ -93,6 +100,7 @@ func (m *MemoryStore) IngestTransactions(ledgerCloseMeta xdr.LedgerCloseMeta) er
 		}
 		transactions[i] = transaction{
 			bucket:           &bucket,
+			hash:             tx.Result.TransactionHash,
 			feeBump:          tx.Envelope.IsFeeBump(),
 			applicationOrder: int32(tx.Index),
 			successful:       tx.Result.Result.Successful(),
@@ -113,6 +121,11 @@ func (m *MemoryStore) IngestTransactions(ledgerCloseMeta xdr.LedgerCloseMeta) er
 		}
 		hashMap[tx.Result.TransactionHash] = transactions[i]
 		hashes = append(hashes, tx.Result.TransactionHash)
+		if fee, isSoroban := inclusionFee(tx, baseFee, hasBaseFee); isSoroban {
+			fees.soroban = append(fees.soroban, fee)
+		} else {
+			fees.classic = append(fees.classic, fee)
+		}
 	}
 	bucket = ledgerbucketwindow.LedgerBucket[[]xdr.Hash]{
 		LedgerSeq:            ledgerCloseMeta.LedgerSequence(),
@@ -126,6 +139,13 @@ func (m *MemoryStore) IngestTransactions(ledgerCloseMeta xdr.LedgerCloseMeta) er
 	if err != nil {
 		return err
 	}
+	if _, err := m.feesByLedger.Append(ledgerbucketwindow.LedgerBucket[ledgerFees]{
+		LedgerSeq:            bucket.LedgerSeq,
+		LedgerCloseTimestamp: bucket.LedgerCloseTimestamp,
+		BucketContent:        fees,
+	}); err != nil {
+		return err
+	}
 	if evicted != nil {
 		// garbage-collect evicted entries
 		for _, evictedTxHash := range evicted.BucketContent {
@@ -134,6 +154,7 @@ func (m *MemoryStore) IngestTransactions(ledgerCloseMeta xdr.LedgerCloseMeta) er
 	}
 	for hash, tx := range hashMap {
 		m.transactions[hash] = tx
+		m.notifyWaitersLocked(hash)
 	}
 	m.transactionDurationMetric.With(prometheus.Labels{"operation": "ingest"}).Observe(time.Since(startTime).Seconds())
 	m.transactionCountMetric.Observe(float64(txCount))
@@ -158,6 +179,7 @@ type LedgerInfo struct {
 }
 
 type Transaction struct {
+	Hash             xdr.Hash // hash of the envelope, which is the outer hash for fee bump transactions
 	Result           []byte   // XDR encoded xdr.TransactionResult
 	Meta             []byte   // XDR encoded xdr.TransactionMeta
 	Envelope         []byte   // XDR encoded xdr.TransactionEnvelope
@@ -187,11 +209,8 @@ func (m *MemoryStore) GetLatestLedger() LedgerInfo {
 	return LedgerInfo{}
 }
 
-// GetTransaction obtains a transaction from the store and whether it's present and the current store range
-func (m *MemoryStore) GetTransaction(hash xdr.Hash) (Transaction, bool, StoreRange) {
-	startTime := time.Now()
-	m.lock.RLock()
-	defer m.lock.RUnlock()
+// storeRange returns the range of ledgers in the store. It must be called with the lock held.
+func (m *MemoryStore) storeRange() StoreRange {
 	var storeRange StoreRange
 	if m.transactionsByLedger.Len() > 0 {
 		firstBucket := m.transactionsByLedger.Get(0)
@@ -207,20 +226,76 @@ func (m *MemoryStore) GetTransaction(hash xdr.Hash) (Transaction, bool, StoreRan
 			},
 		}
 	}
+	return storeRange
+}
+
+// GetTransaction obtains a transaction from the store and whether it's present and the current store range
+func (m *MemoryStore) GetTransaction(hash xdr.Hash) (Transaction, bool, StoreRange) {
+	startTime := time.Now()
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	storeRange := m.storeRange()
 	internalTx, ok := m.transactions[hash]
 	if !ok {
 		return Transaction{}, false, storeRange
 	}
-
-	var txMeta xdr.TransactionMeta
-	err := txMeta.UnmarshalBinary(internalTx.meta)
+	tx, err := internalTx.decode()
 	if err != nil {
 		return Transaction{}, false, storeRange
 	}
 
+	m.transactionDurationMetric.With(prometheus.Labels{"operation": "get"}).Observe(time.Since(startTime).Seconds())
+	return tx, true, storeRange
+}
+
+// GetTransactions returns up to limit transactions in ledger and application order, starting at the given
+// ledger and application order (both inclusive), along with the current store range.
+// Fee bump transactions are only returned once, under their outer hash.
+func (m *MemoryStore) GetTransactions(startLedger uint32, startApplicationOrder int32, limit uint) ([]Transaction, StoreRange, error) {
+	startTime := time.Now()
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	storeRange := m.storeRange()
+
+	var txs []Transaction
+	for i := uint32(0); i < m.transactionsByLedger.Len() && uint(len(txs)) < limit; i++ {
+		bucket := m.transactionsByLedger.Get(i)
+		if bucket.LedgerSeq < startLedger {
+			continue
+		}
+		for _, hash := range bucket.BucketContent {
+			internalTx := m.transactions[hash]
+			if internalTx.hash != hash {
+				// inner hash of a fee bump transaction
+				continue
+			}
+			if bucket.LedgerSeq == startLedger && internalTx.applicationOrder < startApplicationOrder {
+				continue
+			}
+			tx, err := internalTx.decode()
+			if err != nil {
+				return nil, storeRange, err
+			}
+			txs = append(txs, tx)
+			if uint(len(txs)) >= limit {
+				break
+			}
+		}
+	}
+
+	m.transactionDurationMetric.With(prometheus.Labels{"operation": "scan"}).Observe(time.Since(startTime).Seconds())
+	return txs, storeRange, nil
+}
+
+func (internalTx transaction) decode() (Transaction, error) {
+	var txMeta xdr.TransactionMeta
+	if err := txMeta.UnmarshalBinary(internalTx.meta); err != nil {
+		return Transaction{}, err
+	}
+
 	txEvents, err := txMeta.GetDiagnosticEvents()
 	if err != nil {
-		return Transaction{}, false, storeRange
+		return Transaction{}, err
 	}
 
 	events := make([][]byte, 0, len(txEvents))
@@ -228,12 +303,13 @@ func (m *MemoryStore) GetTransaction(hash xdr.Hash) (Transaction, bool, StoreRan
 	for _, e := range txEvents {
 		diagnosticEventXDR, err := e.MarshalBinary()
 		if err != nil {
-			return Transaction{}, false, storeRange
+			return Transaction{}, err
 		}
 		events = append(events, diagnosticEventXDR)
 	}
 
-	tx := Transaction{
+	return Transaction{
+		Hash:             internalTx.hash,
 		Result:           internalTx.result,
 		Meta:             internalTx.meta,
 		Envelope:         internalTx.envelope,
@@ -245,8 +321,5 @@ func (m *MemoryStore) GetTransaction(hash xdr.Hash) (Transaction, bool, StoreRan
 			Sequence:  internalTx.bucket.LedgerSeq,
 			CloseTime: internalTx.bucket.LedgerCloseTimestamp,
 		},
-	}
-
-	m.transactionDurationMetric.With(prometheus.Labels{"operation": "get"}).Observe(time.Since(startTime).Seconds())
-	return tx, true, storeRange
+	}, nil
 }
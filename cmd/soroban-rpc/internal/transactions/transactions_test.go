@@ -17,6 +17,7 @@ import (
 
 func expectedTransaction(t *testing.T, ledger uint32, feeBump bool) Transaction {
 	tx := Transaction{
+		Hash:             txHash(ledger, feeBump),
 		FeeBump:          feeBump,
 		ApplicationOrder: 1,
 		Ledger:           expectedLedgerInfo(ledger),
@@ -369,6 +370,33 @@ func TestGetTransactionsWithEventData(t *testing.T) {
 	require.Equal(t, eventBytes, tx.Events[0])
 }
 
+func TestGetTransactionsRange(t *testing.T) {
+	store := NewMemoryStore(interfaces.MakeNoOpDeamon(), "passphrase", 100)
+	require.NoError(t, store.IngestTransactions(txMeta(1, false)))
+	require.NoError(t, store.IngestTransactions(txMeta(2, true)))
+	require.NoError(t, store.IngestTransactions(txMeta(3, false)))
+
+	// fee bump transactions are only returned once
+	txs, storeRange, err := store.GetTransactions(1, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, expectedStoreRange(1, 3), storeRange)
+	require.Equal(t, []Transaction{
+		expectedTransaction(t, 1, false),
+		expectedTransaction(t, 2, true),
+		expectedTransaction(t, 3, false),
+	}, txs)
+
+	// the start is inclusive and the limit applies
+	txs, _, err = store.GetTransactions(2, 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, []Transaction{expectedTransaction(t, 2, true)}, txs)
+
+	// skip transactions before the application order
+	txs, _, err = store.GetTransactions(2, 2, 10)
+	require.NoError(t, err)
+	require.Equal(t, []Transaction{expectedTransaction(t, 3, false)}, txs)
+}
+
 func stableHeapInUse() int64 {
 	var (
 		m         = runtime.MemStats{}
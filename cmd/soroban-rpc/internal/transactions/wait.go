@@ -0,0 +1,55 @@
+package transactions
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stellar/go/xdr"
+)
+
+// waiter is shared by all the callers waiting for the same transaction
+type waiter struct {
+	ingested chan struct{}
+	count    int
+}
+
+// notifyWaitersLocked wakes up the callers waiting for the given transaction. It must be called with the lock held.
+func (m *MemoryStore) notifyWaitersLocked(hash xdr.Hash) {
+	if w, ok := m.waiters[hash]; ok {
+		close(w.ingested)
+		delete(m.waiters, hash)
+	}
+}
+
+// WaitForTransaction returns the transaction with the given hash as soon as it's in the store.
+// If the context is done before the transaction is ingested, it returns false and the range of the store.
+func (m *MemoryStore) WaitForTransaction(ctx context.Context, hash xdr.Hash) (Transaction, bool, StoreRange) {
+	startTime := time.Now()
+	m.lock.Lock()
+	if _, ok := m.transactions[hash]; ok {
+		m.lock.Unlock()
+		return m.GetTransaction(hash)
+	}
+	w, ok := m.waiters[hash]
+	if !ok {
+		w = &waiter{ingested: make(chan struct{})}
+		m.waiters[hash] = w
+	}
+	w.count++
+	m.lock.Unlock()
+
+	select {
+	case <-w.ingested:
+	case <-ctx.Done():
+		m.lock.Lock()
+		w.count--
+		// the waiter may have been notified and replaced in the meantime
+		if w.count == 0 && m.waiters[hash] == w {
+			delete(m.waiters, hash)
+		}
+		m.lock.Unlock()
+	}
+	m.transactionDurationMetric.With(prometheus.Labels{"operation": "wait"}).Observe(time.Since(startTime).Seconds())
+	return m.GetTransaction(hash)
+}
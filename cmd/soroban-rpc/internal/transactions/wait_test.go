@@ -0,0 +1,53 @@
+package transactions
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestWaitForTransaction(t *testing.T) {
+	store := NewMemoryStore(interfaces.MakeNoOpDeamon(), "passphrase", 100)
+	require.NoError(t, store.IngestTransactions(txMeta(1, false)))
+
+	// already ingested transactions are returned right away
+	tx, ok, storeRange := store.WaitForTransaction(context.Background(), txHash(1, false))
+	require.True(t, ok)
+	require.Equal(t, expectedTransaction(t, 1, false), tx)
+	require.Equal(t, expectedStoreRange(1, 1), storeRange)
+
+	// the waiters are woken up when the transaction is ingested
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx, ok, storeRange := store.WaitForTransaction(context.Background(), txHash(2, true))
+			require.True(t, ok)
+			require.Equal(t, expectedTransaction(t, 2, true), tx)
+			require.Equal(t, expectedStoreRange(1, 2), storeRange)
+		}()
+	}
+	require.Eventually(t, func() bool {
+		store.lock.RLock()
+		defer store.lock.RUnlock()
+		w, ok := store.waiters[txHash(2, true)]
+		return ok && w.count == 2
+	}, 10*time.Second, time.Millisecond)
+	require.NoError(t, store.IngestTransactions(txMeta(2, true)))
+	wg.Wait()
+	require.Empty(t, store.waiters)
+
+	// the wait ends with the context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, ok, storeRange = store.WaitForTransaction(ctx, txHash(3, false))
+	require.False(t, ok)
+	require.Equal(t, expectedStoreRange(1, 2), storeRange)
+	require.Empty(t, store.waiters)
+}
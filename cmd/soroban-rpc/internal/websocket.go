@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+	"github.com/stellar/go/support/log"
+	"golang.org/x/net/websocket"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
+)
+
+// webSocketChannel transmits each JSON RPC message in a WebSocket text frame
+type webSocketChannel struct {
+	conn *websocket.Conn
+}
+
+func (c webSocketChannel) Send(msg []byte) error {
+	return websocket.Message.Send(c.conn, string(msg))
+}
+
+func (c webSocketChannel) Recv() ([]byte, error) {
+	var msg []byte
+	err := websocket.Message.Receive(c.conn, &msg)
+	return msg, err
+}
+
+func (c webSocketChannel) Close() error {
+	return c.conn.Close()
+}
+
+// WebSocketHandler serves the subscription methods over WebSocket, pushing
+// the notifications of each subscription to its connection.
+type WebSocketHandler struct {
+	http.Handler
+	logger  *log.Entry
+	lock    sync.Mutex
+	servers map[*jrpc2.Server]struct{}
+}
+
+// Close stops all the connections of the handler
+func (h *WebSocketHandler) Close() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for server := range h.servers {
+		server.Stop()
+	}
+}
+
+// NewWebSocketHandler constructs a WebSocketHandler instance
+func NewWebSocketHandler(cfg *config.Config, params HandlerParams) *WebSocketHandler {
+	h := &WebSocketHandler{
+		logger:  params.Logger,
+		servers: make(map[*jrpc2.Server]struct{}),
+	}
+	h.Handler = websocket.Server{
+		// like the HTTP endpoint, requests are accepted from any origin
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			// the read timeout of the http server doesn't apply to long-lived connections
+			if err := conn.SetReadDeadline(time.Time{}); err != nil {
+				h.logger.WithError(err).Warn("could not reset websocket read deadline")
+				return
+			}
+			conn.MaxPayloadBytes = maxHTTPRequestSize
+
+			eventSubscriptions := methods.NewEventSubscriptions(
+				params.EventStore,
+				cfg.MaxSubscriptionsPerConnection,
+				cfg.SubscriptionBufferSize,
+				cfg.MaxEventsLimit,
+			)
+			defer eventSubscriptions.Close()
+			transactionSubscriptions := methods.NewTransactionSubscriptions(
+				params.TransactionStore,
+				cfg.MaxSubscriptionsPerConnection,
+				cfg.MaxWaitForTransactionTimeout,
+			)
+			defer transactionSubscriptions.Close()
+			server := jrpc2.NewServer(handler.Map{
+				"subscribeEvents":      methods.NewSubscribeEventsHandler(eventSubscriptions),
+				"subscribeTransaction": methods.NewSubscribeTransactionHandler(transactionSubscriptions),
+				"unsubscribe":          methods.NewUnsubscribeHandler(eventSubscriptions, transactionSubscriptions),
+			}, &jrpc2.ServerOptions{
+				AllowPush: true,
+				Logger:    func(text string) { h.logger.Debug(text) },
+			})
+
+			h.lock.Lock()
+			h.servers[server] = struct{}{}
+			h.lock.Unlock()
+			defer func() {
+				h.lock.Lock()
+				delete(h.servers, server)
+				h.lock.Unlock()
+			}()
+
+			if err := server.Start(webSocketChannel{conn: conn}).Wait(); err != nil {
+				h.logger.WithError(err).Debug("websocket connection closed")
+			}
+		},
+	}
+	return h
+}
@@ -0,0 +1,31 @@
+package xdrjson
+
+import (
+	"github.com/stellar/go/xdr"
+)
+
+// core_metrics diagnostic events emitted by the host with the resources used by a host function
+const (
+	CoreMetricsTopic      = "core_metrics"
+	CPUInsnMetric         = "cpu_insn"
+	MemByteMetric         = "mem_byte"
+	LedgerReadByteMetric  = "ledger_read_byte"
+	LedgerWriteByteMetric = "ledger_write_byte"
+)
+
+// CoreMetric returns the name and value of a core_metrics diagnostic event body
+func CoreMetric(body xdr.ContractEventV0) (string, uint64, bool) {
+	if len(body.Topics) != 2 {
+		return "", 0, false
+	}
+	topic, ok := body.Topics[0].GetSym()
+	if !ok || string(topic) != CoreMetricsTopic {
+		return "", 0, false
+	}
+	metric, ok := body.Topics[1].GetSym()
+	value, isU64 := body.Data.GetU64()
+	if !ok || !isU64 {
+		return "", 0, false
+	}
+	return string(metric), uint64(value), true
+}
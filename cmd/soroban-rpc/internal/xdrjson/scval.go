@@ -0,0 +1,248 @@
+package xdrjson
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/stellar/go/xdr"
+)
+
+// ScValJSON is the lossless JSON rendering of an ScVal, tagged with its type name
+// (e.g. "u32", "i128", "address", "vec").
+//
+// No information is dropped: 32 bit integers are JSON numbers,
+// wider integers are decimal strings (so that JavaScript clients don't lose precision), bytes
+// are hex encoded, addresses are strkey encoded (G... or C...), maps keep their keys typed and
+// void values have no value. Strings which aren't valid UTF-8 are hex encoded in Hex instead of Value.
+// The indexer and the spec decoder use this rendering too, without the type tags.
+type ScValJSON struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value,omitempty"`
+	Hex   string      `json:"hex,omitempty"`
+}
+
+// ScMapEntryJSON is the lossless JSON rendering of an ScMapEntry
+type ScMapEntryJSON struct {
+	Key   ScValJSON `json:"key"`
+	Value ScValJSON `json:"value"`
+}
+
+// ScErrorJSON is the lossless JSON rendering of an ScError. Code is the contract
+// error code for contract errors, or the name of the ScErrorCode otherwise.
+type ScErrorJSON struct {
+	Type string      `json:"type"`
+	Code interface{} `json:"code"`
+}
+
+// ContractExecutableJSON is the lossless JSON rendering of a ContractExecutable
+type ContractExecutableJSON struct {
+	Type     string `json:"type"`
+	WasmHash string `json:"wasmHash,omitempty"`
+}
+
+// ContractInstanceJSON is the lossless JSON rendering of an ScContractInstance
+type ContractInstanceJSON struct {
+	Executable ContractExecutableJSON `json:"executable"`
+	Storage    []ScMapEntryJSON       `json:"storage"`
+}
+
+// ScValBigInt returns the value of 64, 128 and 256 bit integer ScVals
+func ScValBigInt(val xdr.ScVal) (*big.Int, bool) {
+	switch val.Type {
+	case xdr.ScValTypeScvU64:
+		return new(big.Int).SetUint64(uint64(*val.U64)), true
+	case xdr.ScValTypeScvI64:
+		return big.NewInt(int64(*val.I64)), true
+	case xdr.ScValTypeScvTimepoint:
+		return new(big.Int).SetUint64(uint64(*val.Timepoint)), true
+	case xdr.ScValTypeScvDuration:
+		return new(big.Int).SetUint64(uint64(*val.Duration)), true
+	case xdr.ScValTypeScvU128:
+		return joinWords(false, uint64(val.U128.Hi), uint64(val.U128.Lo)), true
+	case xdr.ScValTypeScvI128:
+		return joinWords(true, uint64(val.I128.Hi), uint64(val.I128.Lo)), true
+	case xdr.ScValTypeScvU256:
+		return joinWords(false, uint64(val.U256.HiHi), uint64(val.U256.HiLo), uint64(val.U256.LoHi), uint64(val.U256.LoLo)), true
+	case xdr.ScValTypeScvI256:
+		return joinWords(true, uint64(val.I256.HiHi), uint64(val.I256.HiLo), uint64(val.I256.LoHi), uint64(val.I256.LoLo)), true
+	}
+	return nil, false
+}
+
+// ScValToJSON renders an ScVal losslessly, see ScValJSON.
+func ScValToJSON(val xdr.ScVal) ScValJSON {
+	result := ScValJSON{Type: scValTypeName(val.Type)}
+	switch val.Type {
+	case xdr.ScValTypeScvBool:
+		result.Value = *val.B
+	case xdr.ScValTypeScvVoid, xdr.ScValTypeScvLedgerKeyContractInstance:
+	case xdr.ScValTypeScvError:
+		result.Value = ScErrorToJSON(*val.Error)
+	case xdr.ScValTypeScvU32:
+		result.Value = uint32(*val.U32)
+	case xdr.ScValTypeScvI32:
+		result.Value = int32(*val.I32)
+	case xdr.ScValTypeScvU64, xdr.ScValTypeScvI64, xdr.ScValTypeScvTimepoint, xdr.ScValTypeScvDuration,
+		xdr.ScValTypeScvU128, xdr.ScValTypeScvI128, xdr.ScValTypeScvU256, xdr.ScValTypeScvI256:
+		n, _ := ScValBigInt(val)
+		result.Value = n.String()
+	case xdr.ScValTypeScvBytes:
+		result.Value = hex.EncodeToString(*val.Bytes)
+	case xdr.ScValTypeScvString:
+		if utf8.ValidString(string(*val.Str)) {
+			result.Value = string(*val.Str)
+		} else {
+			result.Hex = hex.EncodeToString([]byte(*val.Str))
+		}
+	case xdr.ScValTypeScvSymbol:
+		result.Value = string(*val.Sym)
+	case xdr.ScValTypeScvVec:
+		if val.Vec != nil && *val.Vec != nil {
+			result.Value = ScValsToJSON(**val.Vec)
+		}
+	case xdr.ScValTypeScvMap:
+		if val.Map != nil && *val.Map != nil {
+			result.Value = ScMapToJSON(**val.Map)
+		}
+	case xdr.ScValTypeScvAddress:
+		result.Value = ScAddressToJSON(*val.Address)
+	case xdr.ScValTypeScvLedgerKeyNonce:
+		result.Value = strconv.FormatInt(int64(val.NonceKey.Nonce), 10)
+	case xdr.ScValTypeScvContractInstance:
+		instance := ContractInstanceJSON{
+			Executable: ContractExecutableToJSON(val.Instance.Executable),
+		}
+		if val.Instance.Storage != nil {
+			instance.Storage = ScMapToJSON(*val.Instance.Storage)
+		}
+		result.Value = instance
+	}
+	return result
+}
+
+// ScValsToJSON renders a list of ScVals losslessly
+func ScValsToJSON(vals []xdr.ScVal) []ScValJSON {
+	result := make([]ScValJSON, 0, len(vals))
+	for _, val := range vals {
+		result = append(result, ScValToJSON(val))
+	}
+	return result
+}
+
+// ScMapToJSON renders an ScMap losslessly, keeping the order of its entries
+func ScMapToJSON(scMap xdr.ScMap) []ScMapEntryJSON {
+	result := make([]ScMapEntryJSON, 0, len(scMap))
+	for _, entry := range scMap {
+		result = append(result, ScMapEntryJSON{
+			Key:   ScValToJSON(entry.Key),
+			Value: ScValToJSON(entry.Val),
+		})
+	}
+	return result
+}
+
+// ScAddressToJSON renders an account or contract address as a strkey
+func ScAddressToJSON(address xdr.ScAddress) string {
+	encoded, err := address.String()
+	if err != nil {
+		return ""
+	}
+	return encoded
+}
+
+func ScErrorToJSON(scError xdr.ScError) ScErrorJSON {
+	result := ScErrorJSON{Type: scError.Type.String()}
+	if scError.ContractCode != nil {
+		result.Code = uint32(*scError.ContractCode)
+	} else if scError.Code != nil {
+		result.Code = scError.Code.String()
+	}
+	return result
+}
+
+func ContractExecutableToJSON(executable xdr.ContractExecutable) ContractExecutableJSON {
+	if executable.WasmHash != nil {
+		return ContractExecutableJSON{Type: "wasm", WasmHash: hex.EncodeToString(executable.WasmHash[:])}
+	}
+	return ContractExecutableJSON{Type: "stellarAsset"}
+}
+
+func scValTypeName(t xdr.ScValType) string {
+	switch t {
+	case xdr.ScValTypeScvBool:
+		return "bool"
+	case xdr.ScValTypeScvVoid:
+		return "void"
+	case xdr.ScValTypeScvError:
+		return "error"
+	case xdr.ScValTypeScvU32:
+		return "u32"
+	case xdr.ScValTypeScvI32:
+		return "i32"
+	case xdr.ScValTypeScvU64:
+		return "u64"
+	case xdr.ScValTypeScvI64:
+		return "i64"
+	case xdr.ScValTypeScvTimepoint:
+		return "timepoint"
+	case xdr.ScValTypeScvDuration:
+		return "duration"
+	case xdr.ScValTypeScvU128:
+		return "u128"
+	case xdr.ScValTypeScvI128:
+		return "i128"
+	case xdr.ScValTypeScvU256:
+		return "u256"
+	case xdr.ScValTypeScvI256:
+		return "i256"
+	case xdr.ScValTypeScvBytes:
+		return "bytes"
+	case xdr.ScValTypeScvString:
+		return "string"
+	case xdr.ScValTypeScvSymbol:
+		return "symbol"
+	case xdr.ScValTypeScvVec:
+		return "vec"
+	case xdr.ScValTypeScvMap:
+		return "map"
+	case xdr.ScValTypeScvAddress:
+		return "address"
+	case xdr.ScValTypeScvContractInstance:
+		return "contractInstance"
+	case xdr.ScValTypeScvLedgerKeyContractInstance:
+		return "ledgerKeyContractInstance"
+	case xdr.ScValTypeScvLedgerKeyNonce:
+		return "ledgerKeyNonce"
+	}
+	return t.String()
+}
+
+// ScValTypeFromName returns the ScVal type with the given name, as rendered in ScValJSON
+func ScValTypeFromName(name string) (xdr.ScValType, bool) {
+	// the ScVal types are numbered consecutively from 0
+	var t xdr.ScValType
+	for value := int32(0); t.ValidEnum(value); value++ {
+		if scValTypeName(xdr.ScValType(value)) == name {
+			return xdr.ScValType(value), true
+		}
+	}
+	return 0, false
+}
+
+// joinWords joins big-endian 64 bit words into an integer, interpreting the
+// most significant word as signed if requested.
+func joinWords(signed bool, words ...uint64) *big.Int {
+	result := new(big.Int)
+	if signed {
+		result.SetInt64(int64(words[0]))
+	} else {
+		result.SetUint64(words[0])
+	}
+	for _, word := range words[1:] {
+		result.Lsh(result, 64)
+		result.Add(result, new(big.Int).SetUint64(word))
+	}
+	return result
+}
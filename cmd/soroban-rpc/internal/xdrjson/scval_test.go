@@ -0,0 +1,76 @@
+package xdrjson
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScValToJSON(t *testing.T) {
+	u32 := xdr.Uint32(7)
+	i64 := xdr.Int64(math.MinInt64)
+	u64 := xdr.Uint64(math.MaxUint64)
+	i128 := xdr.Int128Parts{Hi: -1, Lo: 0}
+	bytes := xdr.ScBytes{0xca, 0xfe}
+	str := xdr.ScString("hello")
+	invalidStr := xdr.ScString([]byte{0xff, 0xfe})
+	sym := xdr.ScSymbol("balance")
+	contractAddress := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.Hash{0x1}}
+	vec := &xdr.ScVec{
+		{Type: xdr.ScValTypeScvU32, U32: &u32},
+		{Type: xdr.ScValTypeScvVoid},
+	}
+	scMap := &xdr.ScMap{
+		{
+			Key: xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym},
+			Val: xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &i128},
+		},
+	}
+	contractCode := xdr.Uint32(3)
+
+	for _, testCase := range []struct {
+		name     string
+		val      xdr.ScVal
+		expected string
+	}{
+		{"u32", xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u32}, `{"type":"u32","value":7}`},
+		{"i64", xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &i64}, `{"type":"i64","value":"-9223372036854775808"}`},
+		{"u64", xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &u64}, `{"type":"u64","value":"18446744073709551615"}`},
+		{"i128", xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &i128}, `{"type":"i128","value":"-18446744073709551616"}`},
+		{"void", xdr.ScVal{Type: xdr.ScValTypeScvVoid}, `{"type":"void"}`},
+		{"bytes", xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &bytes}, `{"type":"bytes","value":"cafe"}`},
+		{"string", xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str}, `{"type":"string","value":"hello"}`},
+		{"invalid utf8 string", xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &invalidStr}, `{"type":"string","hex":"fffe"}`},
+		{
+			"contract address",
+			xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &contractAddress},
+			`{"type":"address","value":"` + strkey.MustEncode(strkey.VersionByteContract, contractAddress.ContractId[:]) + `"}`,
+		},
+		{
+			"vec",
+			xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vec},
+			`{"type":"vec","value":[{"type":"u32","value":7},{"type":"void"}]}`,
+		},
+		{
+			"map",
+			xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &scMap},
+			`{"type":"map","value":[{"key":{"type":"symbol","value":"balance"},"value":{"type":"i128","value":"-18446744073709551616"}}]}`,
+		},
+		{
+			"contract error",
+			xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &contractCode}},
+			`{"type":"error","value":{"type":"ScErrorTypeSceContract","code":3}}`,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			data, err := json.Marshal(ScValToJSON(testCase.val))
+			require.NoError(t, err)
+			assert.JSONEq(t, testCase.expected, string(data))
+		})
+	}
+}
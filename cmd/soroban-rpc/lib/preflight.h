@@ -40,6 +40,7 @@ typedef struct preflight_result_t {
     uint64_t      memory_bytes;
     xdr_t         pre_restore_transaction_data; // SorobanTransactionData XDR for a prerequired RestoreFootprint operation
     int64_t       pre_restore_min_fee; // Minimum recommended resource fee for a prerequired RestoreFootprint operation
+    xdr_vector_t  ledger_changes; // array of XDR LedgerEntryChange, only set when recording ledger changes
 } preflight_result_t;
 
 preflight_result_t *preflight_invoke_hf_op(uintptr_t handle, // Go Handle to forward to SnapshotSourceGet
@@ -48,7 +49,8 @@ preflight_result_t *preflight_invoke_hf_op(uintptr_t handle, // Go Handle to for
                                            const xdr_t source_account, // AccountId XDR
                                            const ledger_info_t ledger_info,
                                            const resource_config_t resource_config,
-                                           bool enable_debug);
+                                           bool enable_debug,
+                                           bool record_ledger_changes); // Report the ledger changes of the invocation
 
 preflight_result_t *preflight_footprint_ttl_op(uintptr_t   handle, // Go Handle to forward to SnapshotSourceGet
                                                uint64_t bucket_list_size, // Bucket list size of current ledger
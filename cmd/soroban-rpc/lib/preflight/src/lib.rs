@@ -5,11 +5,14 @@ extern crate soroban_env_host;
 extern crate soroban_simulation;
 
 use sha2::{Digest, Sha256};
+use soroban_env_host::budget::Budget;
+use soroban_env_host::storage::{AccessType, SnapshotSource, Storage};
 use soroban_env_host::xdr::{
-    AccountId, Hash, InvokeHostFunctionOp, LedgerEntry, LedgerEntryData, LedgerFootprint,
-    LedgerKey, LedgerKeyTtl, Limits, OperationBody, ReadXdr, TtlEntry, WriteXdr,
+    AccountId, Hash, InvokeHostFunctionOp, LedgerEntry, LedgerEntryChange, LedgerEntryData,
+    LedgerEntryExt, LedgerFootprint, LedgerKey, LedgerKeyTtl, Limits, OperationBody, ReadXdr,
+    TtlEntry, WriteXdr,
 };
-use soroban_env_host::LedgerInfo;
+use soroban_env_host::{Host, LedgerInfo};
 use soroban_simulation::{ledger_storage, ResourceConfig};
 use soroban_simulation::{
     simulate_footprint_ttl_op, simulate_invoke_hf_op, LedgerStorage, SimulationResult,
@@ -18,6 +21,7 @@ use std::error::Error;
 use std::ffi::{CStr, CString};
 use std::panic;
 use std::ptr::null_mut;
+use std::rc::Rc;
 use std::{mem, slice};
 
 #[repr(C)]
@@ -114,6 +118,8 @@ pub struct CPreflightResult {
     pub pre_restore_transaction_data: CXDR,
     // Minimum recommended resource fee for a prerequired RestoreFootprint operation
     pub pre_restore_min_fee: i64,
+    // array of XDR LedgerEntryChange, only set when recording ledger changes
+    pub ledger_changes: CXDRVector,
 }
 
 impl From<SimulationResult> for CPreflightResult {
@@ -129,6 +135,7 @@ impl From<SimulationResult> for CPreflightResult {
             memory_bytes: s.memory_bytes,
             pre_restore_transaction_data: get_default_c_xdr(),
             pre_restore_min_fee: 0,
+            ledger_changes: get_default_c_xdr_vector(),
         };
         if let Some(p) = s.restore_preamble {
             result.pre_restore_min_fee = p.min_fee;
@@ -147,6 +154,7 @@ pub extern "C" fn preflight_invoke_hf_op(
     ledger_info: CLedgerInfo,
     resource_config: CResourceConfig,
     enable_debug: bool,
+    record_ledger_changes: bool, // Report the ledger changes of the invocation
 ) -> *mut CPreflightResult {
     catch_preflight_panic(Box::new(move || {
         preflight_invoke_hf_op_or_maybe_panic(
@@ -157,6 +165,7 @@ pub extern "C" fn preflight_invoke_hf_op(
             ledger_info,
             resource_config,
             enable_debug,
+            record_ledger_changes,
         )
     }))
 }
@@ -169,29 +178,93 @@ fn preflight_invoke_hf_op_or_maybe_panic(
     ledger_info: CLedgerInfo,
     resource_config: CResourceConfig,
     enable_debug: bool,
+    record_ledger_changes: bool,
 ) -> Result<CPreflightResult, Box<dyn Error>> {
     let invoke_hf_op =
         InvokeHostFunctionOp::from_xdr(from_c_xdr(invoke_hf_op), Limits::none()).unwrap();
     let source_account = AccountId::from_xdr(from_c_xdr(source_account), Limits::none()).unwrap();
-    let go_storage = GoLedgerStorage {
-        golang_handle: handle,
-        current_ledger_sequence: ledger_info.sequence_number,
+    let new_ledger_storage = || {
+        let go_storage = GoLedgerStorage {
+            golang_handle: handle,
+            current_ledger_sequence: ledger_info.sequence_number,
+        };
+        LedgerStorage::with_restore_tracking(Box::new(go_storage), ledger_info.sequence_number)
     };
-    let ledger_storage =
-        LedgerStorage::with_restore_tracking(Box::new(go_storage), ledger_info.sequence_number)?;
     let result = simulate_invoke_hf_op(
-        ledger_storage,
+        new_ledger_storage()?,
         bucket_list_size,
-        invoke_hf_op,
-        source_account,
+        invoke_hf_op.clone(),
+        source_account.clone(),
         LedgerInfo::from(ledger_info),
         resource_config.into(),
         enable_debug,
+    )?;
+    let failed = !result.error.is_empty();
+    let mut c_result: CPreflightResult = result.into();
+    if record_ledger_changes && !failed {
+        let ledger_changes = get_ledger_changes(
+            Rc::new(new_ledger_storage()?),
+            &invoke_hf_op,
+            &source_account,
+            LedgerInfo::from(ledger_info),
+        )?;
+        c_result.ledger_changes = xdr_vec_to_c(ledger_changes);
+    }
+    Ok(c_result)
+}
+
+// The simulation doesn't expose the ledger entries modified by the invocation,
+// so we run it again in recording mode and collect its read-write entries.
+// The run is bound by the same default budget as the simulation, so a recorded
+// invocation can't consume more resources than the simulated one.
+fn get_ledger_changes(
+    snapshot: Rc<dyn SnapshotSource>,
+    invoke_hf_op: &InvokeHostFunctionOp,
+    source_account: &AccountId,
+    ledger_info: LedgerInfo,
+) -> Result<Vec<LedgerEntryChange>, Box<dyn Error>> {
+    let budget = Budget::default();
+    let host = Host::with_storage_and_budget(
+        Storage::with_recording_footprint(snapshot),
+        budget.clone(),
     );
-    match result {
-        Ok(r) => Ok(r.into()),
-        Err(e) => Err(e),
+    host.set_source_account(source_account.clone())?;
+    host.set_ledger_info(ledger_info)?;
+    host.switch_to_recording_auth(true)?;
+    host.invoke_function(invoke_hf_op.host_function.clone())?;
+    let (storage, _) = host.try_finish()?;
+
+    let mut changes = Vec::new();
+    for (key, access_type) in storage.footprint.0.iter(&budget)? {
+        if *access_type != AccessType::ReadWrite {
+            continue;
+        }
+        match storage.map.get::<Rc<LedgerKey>>(key, &budget)? {
+            Some(Some((entry, live_until_ledger_seq))) => {
+                changes.push(LedgerEntryChange::Updated((**entry).clone()));
+                if let Some(live_until_ledger_seq) = live_until_ledger_seq {
+                    changes.push(LedgerEntryChange::Updated(ttl_entry(
+                        key,
+                        *live_until_ledger_seq,
+                    )?));
+                }
+            }
+            _ => changes.push(LedgerEntryChange::Removed((**key).clone())),
+        }
     }
+    Ok(changes)
+}
+
+fn ttl_entry(key: &LedgerKey, live_until_ledger_seq: u32) -> Result<LedgerEntry, Box<dyn Error>> {
+    let key_hash: [u8; 32] = Sha256::digest(key.to_xdr(Limits::none())?).into();
+    Ok(LedgerEntry {
+        last_modified_ledger_seq: 0,
+        data: LedgerEntryData::Ttl(TtlEntry {
+            key_hash: Hash(key_hash),
+            live_until_ledger_seq,
+        }),
+        ext: LedgerEntryExt::V0,
+    })
 }
 
 #[no_mangle]
@@ -253,6 +326,7 @@ fn preflight_error(str: String) -> CPreflightResult {
         memory_bytes: 0,
         pre_restore_transaction_data: get_default_c_xdr(),
         pre_restore_min_fee: 0,
+        ledger_changes: get_default_c_xdr_vector(),
     }
 }
 
@@ -333,6 +407,7 @@ pub unsafe extern "C" fn free_preflight_result(result: *mut CPreflightResult) {
     free_c_xdr(boxed.transaction_data);
     free_c_xdr_array(boxed.events);
     free_c_xdr(boxed.pre_restore_transaction_data);
+    free_c_xdr_array(boxed.ledger_changes);
 }
 
 fn free_c_string(str: *mut libc::c_char) {
@@ -458,3 +533,76 @@ impl ledger_storage::LedgerGetter for GoLedgerStorage {
 pub(crate) fn is_live(live_until_ledger_seq: u32, current_ledger_seq: u32) -> bool {
     live_until_ledger_seq >= current_ledger_seq
 }
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use soroban_env_host::xdr::{
+        Asset, ContractExecutable, ContractIdPreimage, CreateContractArgs, HostFunction,
+        PublicKey, ScErrorCode, ScErrorType, ScVal, Uint256, VecM,
+    };
+    use soroban_env_host::HostError;
+
+    struct EmptySnapshot;
+
+    impl SnapshotSource for EmptySnapshot {
+        fn get(&self, _key: &Rc<LedgerKey>) -> Result<(Rc<LedgerEntry>, Option<u32>), HostError> {
+            Err(soroban_env_host::Error::from_type_and_code(
+                ScErrorType::Storage,
+                ScErrorCode::MissingValue,
+            )
+            .into())
+        }
+
+        fn has(&self, _key: &Rc<LedgerKey>) -> Result<bool, HostError> {
+            Ok(false)
+        }
+    }
+
+    #[test]
+    fn test_get_ledger_changes() {
+        let invoke_hf_op = InvokeHostFunctionOp {
+            host_function: HostFunction::CreateContract(CreateContractArgs {
+                contract_id_preimage: ContractIdPreimage::Asset(Asset::Native),
+                executable: ContractExecutable::StellarAsset,
+            }),
+            auth: VecM::default(),
+        };
+        let source_account = AccountId(PublicKey::PublicKeyTypeEd25519(Uint256([0; 32])));
+        let ledger_info = LedgerInfo {
+            protocol_version: 20,
+            sequence_number: 100,
+            timestamp: 500,
+            network_id: [1; 32],
+            base_reserve: 5_000_000,
+            min_temp_entry_ttl: 16,
+            min_persistent_entry_ttl: 4096,
+            max_entry_ttl: 6_312_000,
+        };
+
+        let changes = get_ledger_changes(
+            Rc::new(EmptySnapshot),
+            &invoke_hf_op,
+            &source_account,
+            ledger_info,
+        )
+        .unwrap();
+
+        // the asset contract instance is created, together with its TTL
+        assert_eq!(changes.len(), 2);
+        match &changes[0] {
+            LedgerEntryChange::Updated(LedgerEntry {
+                data: LedgerEntryData::ContractData(data),
+                ..
+            }) => assert_eq!(data.key, ScVal::LedgerKeyContractInstance),
+            change => panic!("unexpected ledger change {change:?}"),
+        }
+        match &changes[1] {
+            LedgerEntryChange::Updated(LedgerEntry {
+                data: LedgerEntryData::Ttl(ttl),
+                ..
+            }) => assert!(ttl.live_until_ledger_seq >= 100),
+            change => panic!("unexpected ledger change {change:?}"),
+        }
+    }
+}
@@ -56,6 +56,111 @@ func main() {
 				return nil
 			},
 		},
+		{
+			ID: "create contract_code_entries table",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.ContractCodeEntry{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.ContractCodeEntry{})
+			},
+		},
+		{
+			ID: "create contracts and contract_upgrades tables",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.Contract{}, &model.ContractUpgrade{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.Contract{}, &model.ContractUpgrade{})
+			},
+		},
+		{
+			ID: "add spec decoded columns to events and contract_data_entries",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.Event{}, &model.ContractDataEntry{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropColumn(&model.Event{}, "decoded_topic"); err != nil {
+					return err
+				}
+				if err := tx.Migrator().DropColumn(&model.Event{}, "decoded_value"); err != nil {
+					return err
+				}
+				if err := tx.Migrator().DropColumn(&model.ContractDataEntry{}, "decoded_key"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&model.ContractDataEntry{}, "decoded_val")
+			},
+		},
+		{
+			ID: "create invocations table",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.Invocation{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.Invocation{})
+			},
+		},
+		{
+			ID: "create operations table",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.Operation{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.Operation{})
+			},
+		},
+		{
+			ID: "widen transaction fees and add result codes, resource fees and raw xdr",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.Transaction{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, column := range []string{"result_code", "inner_result_code", "operation_count", "resource_fee",
+					"resource_fee_refund", "non_refundable_resource_fee", "refundable_resource_fee", "rent_fee",
+					"envelope_xdr", "result_xdr", "meta_xdr"} {
+					if err := tx.Migrator().DropColumn(&model.Transaction{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "add tx hash and op index to events",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.Event{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, column := range []string{"tx_hash", "op_index"} {
+					if err := tx.Migrator().DropColumn(&model.Event{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "create participants table and add tx hash to token operations",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.Participant{}, &model.TokenOperation{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropColumn(&model.TokenOperation{}, "tx_hash"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropTable(&model.Participant{})
+			},
+		},
+		{
+			ID: "create ledgers table",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.Ledger{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.Ledger{})
+			},
+		},
 	}
 
 	for _, m := range ms {